@@ -2,11 +2,17 @@
 
 set -e
 echo "" > coverage.txt
+root=$(pwd)
 
-for d in $(go list ./... | grep -v vendor); do
-  go test -v -coverprofile=profile.out -covermode=atomic $d
-  if [ -f profile.out ]; then
-    cat profile.out >> coverage.txt
-    rm profile.out
-  fi
-done
\ No newline at end of file
+# the root package & each adapter subpackage with dependencies of its own are
+# separate modules, test every one of them
+for mod in $(find . -name go.mod -not -path "./vendor/*" -exec dirname {} \; | sort); do
+  cd $root/$mod
+  for d in $(go list ./... | grep -v vendor); do
+    go test -v -coverprofile=profile.out -covermode=atomic $d
+    if [ -f profile.out ]; then
+      cat profile.out >> $root/coverage.txt
+      rm profile.out
+    fi
+  done
+done
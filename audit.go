@@ -0,0 +1,93 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ActorDetail is the detail key holding who performed an audited action
+const ActorDetail = "actor"
+
+// audit outcomes
+const (
+	// AuditDenied is the outcome of actions refused for lack of credentials
+	// or permission
+	AuditDenied = "denied"
+	// AuditFailed is the outcome of all other failed actions
+	AuditFailed = "failed"
+)
+
+// AuditLine formats an error as a canonical audit log line for compliance
+// logs. Every line has the same keys in the same order, and carries no
+// messages, data or stack traces, eg:
+//
+//	time=2021-03-04T05:06:07Z id=8f3a code=forbidden op=dataset.delete actor=b5 outcome=denied
+//
+// time is t in UTC. id & op come from OriginOf, and actor from ActorDetail.
+// Missing values are written as empty quoted strings
+func AuditLine(t time.Time, err error) string {
+	reg := DefaultRegistry
+	actor := ""
+	if e, ok := AsError(err); ok {
+		reg = e.Registry()
+		if a, ok := e.Details()[ActorDetail]; ok {
+			actor = fmt.Sprint(a)
+		}
+	}
+	o := OriginOf(err)
+	code := reg.Canonical(o.Code)
+	outcome := AuditFailed
+	if reg.IsInCategory(code, CodeUnauthorized) || reg.IsInCategory(code, CodeForbidden) {
+		outcome = AuditDenied
+	}
+
+	pairs := [][2]string{
+		{"time", t.UTC().Format(time.RFC3339)},
+		{"id", o.ID},
+		{"code", reg.CodeSlug(code)},
+		{"op", o.Op},
+		{"actor", actor},
+		{"outcome", outcome},
+	}
+	strs := make([]string, len(pairs))
+	for i, p := range pairs {
+		strs[i] = p[0] + "=" + logfmtValue(p[1])
+	}
+	return strings.Join(strs, " ")
+}
+
+// AuditLog appends audit lines to a writer, one line per error. AuditLog is
+// a Reporter, so it can be set with SetReporter or combined with other
+// reporters using MultiReporter. AuditLog is safe for concurrent use
+type AuditLog struct {
+	now func() time.Time
+
+	lk sync.Mutex
+	w  io.Writer
+}
+
+// NewAuditLog creates an audit log writing to w
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{w: w, now: time.Now}
+}
+
+// Log appends an audit line for err. nil errors aren't logged
+func (a *AuditLog) Log(err error) error {
+	if err == nil {
+		return nil
+	}
+	line := AuditLine(a.now(), err) + "\n"
+	a.lk.Lock()
+	defer a.lk.Unlock()
+	_, werr := io.WriteString(a.w, line)
+	return werr
+}
+
+// Report implements the Reporter interface
+func (a *AuditLog) Report(ctx context.Context, e *Error) {
+	a.Log(e)
+}
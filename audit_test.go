@@ -0,0 +1,46 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestAuditLine(t *testing.T) {
+	ts := time.Date(2021, 3, 4, 5, 6, 7, 0, time.FixedZone("EST", -5*60*60))
+	cases := []struct {
+		err    error
+		expect string
+	}{
+		{New(CodeForbidden, "not the owner").WithDetail(ActorDetail, "b5").WithDetail(OpDetail, "dataset.delete").WithDetail(ErrorIDDetail, "8f3a"),
+			`time=2021-03-04T10:06:07Z id=8f3a code=forbidden op=dataset.delete actor=b5 outcome=denied`},
+		{New(CodeNotFound, "no dataset").WithDetail(ActorDetail, "jane doe"),
+			`time=2021-03-04T10:06:07Z id="" code=not_found op="" actor="jane doe" outcome=failed`},
+		{fmt.Errorf("oh no"),
+			`time=2021-03-04T10:06:07Z id="" code=unknown op="" actor="" outcome=failed`},
+	}
+	for i, c := range cases {
+		if got := AuditLine(ts, c.err); got != c.expect {
+			t.Errorf("case %d line mismatch.\nexpected: %s\ngot:      %s", i, c.expect, got)
+		}
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	buf := &bytes.Buffer{}
+	a := NewAuditLog(buf)
+	a.now = func() time.Time { return time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC) }
+
+	a.Report(context.Background(), New(CodeUnauthorized, "bad token"))
+	if err := a.Log(nil); err != nil {
+		t.Fatal(err)
+	}
+	a.Log(New(CodeNotFound, "no dataset"))
+	expect := "time=2021-03-04T05:06:07Z id=\"\" code=unauthorized op=\"\" actor=\"\" outcome=denied\n" +
+		"time=2021-03-04T05:06:07Z id=\"\" code=not_found op=\"\" actor=\"\" outcome=failed\n"
+	if buf.String() != expect {
+		t.Errorf("log mismatch.\nexpected: %s\ngot:      %s", expect, buf.String())
+	}
+}
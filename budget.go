@@ -0,0 +1,133 @@
+package errors
+
+import (
+	"encoding/json"
+	"unicode/utf8"
+)
+
+// SizeBudget caps the size in bytes of JSON bodies written by the HTTP
+// writers and RenderJSONLine, so large data payloads attached to errors can't
+// blow up response bodies. Errors over budget are truncated and marked with
+// "truncated": true. MarshalJSON, MarshalJSONChain & MarshalJSONDebug, and the
+// codecs built on them, are never truncated. A budget of zero or less
+// disables truncation
+var SizeBudget = 64 << 10
+
+// maxValueLen is the length strings in data & details are cut to when an
+// error is over budget
+const maxValueLen = 1024
+
+// marshalBudget marshals a serialized error to JSON, truncating it to fit
+// SizeBudget. Truncation is deterministic, removing parts of the error in a
+// fixed order until it fits: stack traces, then long strings in data,
+// details and fix commands are shortened, then causes, data, and details are
+// dropped, and finally messages & fix commands are shortened
+func marshalBudget(je jsonError) ([]byte, error) {
+	data, err := json.Marshal(je)
+	budget := SizeBudget
+	if err != nil || budget <= 0 || len(data) <= budget {
+		return data, err
+	}
+
+	je.Truncated = true
+	steps := []func(je *jsonError){
+		func(je *jsonError) { je.dropStacks() },
+		func(je *jsonError) {
+			for i, d := range je.Data {
+				je.Data[i] = truncateValue(d)
+			}
+			for key, val := range je.Details {
+				je.Details[key] = truncateValue(val)
+			}
+			for i, cmd := range je.FixCommands {
+				je.FixCommands[i] = truncateString(cmd, maxValueLen)
+			}
+		},
+		func(je *jsonError) { je.Cause, je.Causes = nil, nil },
+		func(je *jsonError) { je.Data = nil },
+		func(je *jsonError) { je.Details = nil },
+		func(je *jsonError) {
+			n := budget / 4
+			je.Message = truncateString(je.Message, n)
+			je.Friendly = truncateString(je.Friendly, n)
+			je.Fix = truncateString(je.Fix, n)
+			je.Field = truncateString(je.Field, n)
+			if len(je.FixCommands) > 0 {
+				n /= len(je.FixCommands)
+			}
+			for i, cmd := range je.FixCommands {
+				je.FixCommands[i] = truncateString(cmd, n)
+			}
+		},
+	}
+	// copy data, details & fix commands so truncating doesn't modify the error
+	je.Data = append([]interface{}(nil), je.Data...)
+	je.FixCommands = append([]string(nil), je.FixCommands...)
+	if je.Details != nil {
+		details := make(map[string]interface{}, len(je.Details))
+		for key, val := range je.Details {
+			details[key] = val
+		}
+		je.Details = details
+	}
+
+	for _, step := range steps {
+		step(&je)
+		if data, err = json.Marshal(je); err != nil || len(data) <= budget {
+			break
+		}
+	}
+	return data, err
+}
+
+// dropStacks removes stack traces from a serialized error & it's causes
+func (je *jsonError) dropStacks() {
+	je.Stack = nil
+	if je.Cause != nil {
+		cause := *je.Cause
+		cause.dropStacks()
+		je.Cause = &cause
+	}
+	if len(je.Causes) > 0 {
+		causes := make([]jsonError, len(je.Causes))
+		for i, c := range je.Causes {
+			c.dropStacks()
+			causes[i] = c
+		}
+		je.Causes = causes
+	}
+}
+
+// truncateValue shortens long strings, and the JSON encoding of any other
+// long value
+func truncateValue(v interface{}) interface{} {
+	if s, ok := v.(string); ok {
+		return truncateString(s, maxValueLen)
+	}
+	data, err := json.Marshal(v)
+	if err != nil || len(data) <= maxValueLen {
+		return v
+	}
+	return truncateString(string(data), maxValueLen)
+}
+
+// truncateString cuts s to at most n bytes on a rune boundary, marking cut
+// strings with an ellipsis when there's room for one
+func truncateString(s string, n int) string {
+	const ellipsis = "…"
+	if len(s) <= n {
+		return s
+	}
+	if n <= 0 {
+		return ""
+	}
+	mark := ellipsis
+	if n < len(ellipsis) {
+		mark = ""
+	}
+	n -= len(mark)
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + mark
+}
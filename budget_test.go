@@ -0,0 +1,120 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSizeBudget(t *testing.T) {
+	prev := SizeBudget
+	SizeBudget = 2048
+	defer func() { SizeBudget = prev }()
+
+	big := strings.Repeat("ö", 4000)
+	e := New(CodeInvalidArgs, "invalid body", big).WithDetail("body", big)
+
+	data := httpBody(e)
+	if len(data) > SizeBudget {
+		t.Errorf("expected output to fit budget of %d. got: %d bytes", SizeBudget, len(data))
+	}
+	got := map[string]interface{}{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("expected truncated output to be valid JSON: %s", err)
+	}
+	if got["truncated"] != true {
+		t.Errorf("expected truncated marker. got: %s", data)
+	}
+	if got["message"] != "invalid body" {
+		t.Errorf("expected message to be kept. got: %v", got["message"])
+	}
+	if e.Data()[0] != big || e.Details()["body"] != big {
+		t.Errorf("expected truncation not to modify the error")
+	}
+
+	if again := httpBody(e); !bytes.Equal(data, again) {
+		t.Errorf("expected truncation to be deterministic")
+	}
+
+	full, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(full) < 8000 || strings.Contains(string(full), "truncated") {
+		t.Errorf("expected MarshalJSON not to be truncated")
+	}
+	debug, err := MarshalJSONDebug(Wrap(CodeInternal, e, "handling request"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(debug), `"stack"`) {
+		t.Errorf("expected MarshalJSONDebug to keep stacks")
+	}
+
+	small := httpBody(New(CodeInvalidArgs, "invalid body"))
+	if strings.Contains(string(small), "truncated") {
+		t.Errorf("expected errors within budget to be left alone. got: %s", small)
+	}
+
+	SizeBudget = 0
+	if data = httpBody(e); len(data) < 8000 {
+		t.Errorf("expected a zero budget to disable truncation")
+	}
+}
+
+func TestSizeBudgetFixCommands(t *testing.T) {
+	prev := SizeBudget
+	SizeBudget = 512
+	defer func() { SizeBudget = prev }()
+
+	cmd := "qri connect " + strings.Repeat("x", 2000)
+	e := New(CodeUnavailable, "not connected").WithFixCommands(cmd, cmd)
+
+	data := httpBody(e)
+	if len(data) > SizeBudget {
+		t.Errorf("expected output to fit budget of %d. got: %d bytes", SizeBudget, len(data))
+	}
+	if e.FixCommands()[0] != cmd {
+		t.Errorf("expected truncation not to modify the error")
+	}
+}
+
+// httpBody returns the body WriteHTTP writes for err
+func httpBody(err error) []byte {
+	w := httptest.NewRecorder()
+	WriteHTTP(w, err)
+	return w.Body.Bytes()
+}
+
+func TestTruncateString(t *testing.T) {
+	cases := []struct {
+		in     string
+		n      int
+		expect string
+	}{
+		{"hello", 10, "hello"},
+		{"hello world", 8, "hello…"},
+		{"ööö", 5, "ö…"},
+		{"hello", 0, ""},
+		{"hello", 1, "h"},
+		{"hello", 2, "he"},
+		{"hello", 3, "…"},
+		{"hello", 4, "h…"},
+		{"ööö", 0, ""},
+		{"ööö", 1, ""},
+		{"ööö", 2, "ö"},
+		{"ööö", 3, "…"},
+		{"", 0, ""},
+	}
+	for _, c := range cases {
+		got := truncateString(c.in, c.n)
+		if got != c.expect {
+			t.Errorf("truncate %q to %d mismatch. expected: %q, got: %q", c.in, c.n, c.expect, got)
+		}
+		if c.n >= 0 && len(got) > c.n {
+			t.Errorf("truncate %q to %d exceeded budget: %q", c.in, c.n, got)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package errors
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"sync"
+)
+
+// catalog is a declarative listing of codes, used to manage an error
+// taxonomy as data. catalogs are written in YAML or JSON
+type catalog struct {
+	Codes []catalogCode `json:"codes" yaml:"codes"`
+}
+
+// catalogCode is a single code entry in a catalog
+type catalogCode struct {
+	Code       Code   `json:"code" yaml:"code"`
+	Name       string `json:"name" yaml:"name"`
+	Slug       string `json:"slug,omitempty" yaml:"slug,omitempty"`
+	HTTPStatus int    `json:"http_status" yaml:"http_status"`
+	Severity   string `json:"severity,omitempty" yaml:"severity,omitempty"`
+	Retryable  bool   `json:"retryable,omitempty" yaml:"retryable,omitempty"`
+	DocsURL    string `json:"docs_url,omitempty" yaml:"docs_url,omitempty"`
+	GRPCCode   uint32 `json:"grpc_code,omitempty" yaml:"grpc_code,omitempty"`
+	ExitStatus int    `json:"exit_status,omitempty" yaml:"exit_status,omitempty"`
+	Friendly   string `json:"friendly,omitempty" yaml:"friendly,omitempty"`
+	Fix        string `json:"fix,omitempty" yaml:"fix,omitempty"`
+	Parent     Code   `json:"parent,omitempty" yaml:"parent,omitempty"`
+}
+
+// spec converts a catalog entry to a CodeSpec
+func (cc catalogCode) spec() (CodeSpec, error) {
+	sev := SeverityError
+	if cc.Severity != "" {
+		var err error
+		if sev, err = ParseSeverity(cc.Severity); err != nil {
+			return CodeSpec{}, err
+		}
+	}
+	return CodeSpec{
+		HTTPStatus: cc.HTTPStatus,
+		Name:       cc.Name,
+		Slug:       cc.Slug,
+		Severity:   sev,
+		Retryable:  cc.Retryable,
+		DocsURL:    cc.DocsURL,
+		GRPCCode:   cc.GRPCCode,
+		ExitStatus: cc.ExitStatus,
+		Friendly:   cc.Friendly,
+		Fix:        cc.Fix,
+		Parent:     cc.Parent,
+	}, nil
+}
+
+// catalogCodeFromSpec creates a catalog entry from a CodeSpec
+func catalogCodeFromSpec(c Code, spec CodeSpec) catalogCode {
+	return catalogCode{
+		Code:       c,
+		Name:       spec.Name,
+		Slug:       spec.slug(),
+		HTTPStatus: spec.HTTPStatus,
+		Severity:   spec.Severity.String(),
+		Retryable:  spec.Retryable,
+		DocsURL:    spec.DocsURL,
+		GRPCCode:   spec.GRPCCode,
+		ExitStatus: spec.ExitStatus,
+		Friendly:   spec.Friendly,
+		Fix:        spec.Fix,
+		Parent:     spec.Parent,
+	}
+}
+
+// Format enumerates document formats for exporting a registry
+type Format int
+
+const (
+	// FormatJSON is a JSON catalog document
+	FormatJSON Format = iota
+	// FormatCSV is a table of comma-separated values with a header row
+	FormatCSV
+	// FormatYAML is a YAML catalog document. Importing
+	// github.com/qri-io/errors/errorsyaml registers YAML catalog support
+	FormatYAML
+)
+
+// CatalogCodec reads & writes catalog documents in a format
+type CatalogCodec struct {
+	Marshal   func(v interface{}) ([]byte, error)
+	Unmarshal func(data []byte, v interface{}) error
+}
+
+// catalogCodecs maps formats to the codec for that format, for formats that
+// need a third-party package
+var catalogCodecs = struct {
+	lk       sync.RWMutex
+	byFormat map[Format]CatalogCodec
+}{byFormat: map[Format]CatalogCodec{}}
+
+// RegisterCatalogFormat sets the codec Load & Export use for a catalog
+// format, replacing any existing one. JSON & CSV are built in
+func RegisterCatalogFormat(format Format, c CatalogCodec) {
+	catalogCodecs.lk.Lock()
+	defer catalogCodecs.lk.Unlock()
+	catalogCodecs.byFormat[format] = c
+}
+
+// catalogCodec returns the codec registered for a catalog format
+func catalogCodec(format Format) (CatalogCodec, bool) {
+	catalogCodecs.lk.RLock()
+	defer catalogCodecs.lk.RUnlock()
+	c, ok := catalogCodecs.byFormat[format]
+	return c, ok
+}
+
+// LoadRegistry reads a JSON or YAML catalog of codes and registers each code
+// in the default registry
+func LoadRegistry(r io.Reader) error {
+	return DefaultRegistry.Load(r)
+}
+
+// Load reads a JSON catalog of codes and registers each code. YAML catalogs
+// are read when FormatYAML is registered with RegisterCatalogFormat. The
+// catalog is validated before anything is registered, so a catalog with
+// duplicate or invalid entries leaves the registry untouched. A YAML catalog
+// looks like:
+//
+//	codes:
+//	- code: 100
+//	  name: database
+//	  slug: database_unavailable
+//	  http_status: 504
+//	  severity: error
+//	  retryable: true
+//	  friendly: the database couldn't be reached
+//	  fix: please try again later
+//	  docs_url: https://example.com/errors/database
+func (r *Registry) Load(rd io.Reader) error {
+	data, err := ioutil.ReadAll(rd)
+	if err != nil {
+		return Wrap(CodeGeneric, err, "reading catalog")
+	}
+	unmarshal := json.Unmarshal
+	if c, ok := catalogCodec(FormatYAML); ok {
+		// YAML is a superset of JSON, so a YAML codec reads both
+		unmarshal = c.Unmarshal
+	}
+	cat := catalog{}
+	if err := unmarshal(data, &cat); err != nil {
+		return Wrap(CodeInvalidSyntax, err, "parsing catalog")
+	}
+
+	specs := make(map[Code]CodeSpec, len(cat.Codes))
+	for _, cc := range cat.Codes {
+		if _, ok := specs[cc.Code]; ok {
+			return New(CodeInvalidArgs, "duplicate code in catalog", cc.Code)
+		}
+		if cc.Name == "" {
+			return New(CodeInvalidArgs, "catalog code is missing a name", cc.Code)
+		}
+		spec, err := cc.spec()
+		if err != nil {
+			return err
+		}
+		specs[cc.Code] = spec
+	}
+	return r.RegisterSpecs(specs)
+}
+
+// ExportRegistry writes the codes registered with the default registry to w
+func ExportRegistry(w io.Writer, format Format) error {
+	return DefaultRegistry.Export(w, format)
+}
+
+// Export writes registered codes to w in ascending order. Built-in codes are
+// left out, so an export loads into a new registry. JSON output, and the
+// output of formats registered with RegisterCatalogFormat, use the same
+// catalog layout Load reads
+func (r *Registry) Export(w io.Writer, format Format) error {
+	cat := catalog{Codes: []catalogCode{}}
+	for _, c := range r.Codes() {
+		if _, ok := builtinCodes[c]; ok {
+			continue
+		}
+		spec, _ := r.Lookup(c)
+		cat.Codes = append(cat.Codes, catalogCodeFromSpec(c, spec))
+	}
+
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(cat)
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"code", "name", "slug", "http_status", "severity", "retryable", "docs_url", "grpc_code", "exit_status", "friendly", "fix", "parent"})
+		for _, cc := range cat.Codes {
+			cw.Write([]string{
+				strconv.Itoa(int(cc.Code)),
+				cc.Name,
+				cc.Slug,
+				strconv.Itoa(cc.HTTPStatus),
+				cc.Severity,
+				strconv.FormatBool(cc.Retryable),
+				cc.DocsURL,
+				strconv.FormatUint(uint64(cc.GRPCCode), 10),
+				strconv.Itoa(cc.ExitStatus),
+				cc.Friendly,
+				cc.Fix,
+				strconv.Itoa(int(cc.Parent)),
+			})
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		c, ok := catalogCodec(format)
+		if !ok {
+			return New(CodeInvalidArgs, "unknown export format", format)
+		}
+		data, err := c.Marshal(cat)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+}
@@ -0,0 +1,143 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLoadRegistry(t *testing.T) {
+	jsonCatalog := `{"codes":[
+  {
+    "code": 400,
+    "name": "database",
+    "http_status": 504,
+    "severity": "critical",
+    "retryable": true,
+    "friendly": "the database couldn't be reached",
+    "fix": "please try again later",
+    "docs_url": "https://qri.io/docs/errors/database"
+  },
+  {"code": 401, "name": "quota", "http_status": 402}
+]}`
+	r := NewRegistry()
+	if err := r.Load(strings.NewReader(jsonCatalog)); err != nil {
+		t.Fatal(err)
+	}
+	spec, ok := r.Lookup(Code(400))
+	if !ok {
+		t.Fatal("expected code 400 to be registered")
+	}
+	expect := CodeSpec{
+		HTTPStatus: 504,
+		Name:       "database",
+		Severity:   SeverityCritical,
+		Retryable:  true,
+		DocsURL:    "https://qri.io/docs/errors/database",
+		Friendly:   "the database couldn't be reached",
+		Fix:        "please try again later",
+	}
+	if spec != expect {
+		t.Errorf("spec mismatch. expected: %v, got: %v", expect, spec)
+	}
+	if r.CodeSeverity(Code(401)) != SeverityError {
+		t.Errorf("expected catalog severity to default to error")
+	}
+
+}
+
+func TestLoadRegistryErrors(t *testing.T) {
+	cases := []struct {
+		catalog string
+		code    Code
+	}{
+		{`{"codes": [{"code": 500, "name": "a"}, {"code": 500, "name": "b"}]}`, CodeInvalidArgs},
+		{`{"codes": [{"code": 501, "name": "a"}, {"code": 404, "name": "b"}]}`, CodeInvalidArgs},
+		{`{"codes": [{"code": 6, "name": "a"}]}`, CodeInvalidArgs},
+		{`{"codes": [{"code": 502}]}`, CodeInvalidArgs},
+		{`{"codes": [{"code": 503, "name": "a", "severity": "meh"}]}`, CodeInvalidArgs},
+		{`codes: [{code: 500, name: a}]`, CodeInvalidSyntax},
+	}
+
+	for i, c := range cases {
+		r := NewRegistry()
+		r.Register(Code(404), 500, "existing")
+		err := r.Load(strings.NewReader(c.catalog))
+		if err == nil {
+			t.Errorf("case %d expected error, got nil", i)
+			continue
+		}
+		if e, ok := err.(*Error); !ok || e.Code() != c.code {
+			t.Errorf("case %d error code mismatch. expected: %d, got: %s", i, c.code, err)
+		}
+		if _, ok := r.Lookup(Code(500)); ok {
+			t.Errorf("case %d expected failed load not to register any codes", i)
+		}
+	}
+}
+
+func TestExportRegistry(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterSpec(Code(100), CodeSpec{HTTPStatus: 504, Name: "database", Severity: SeverityCritical, Friendly: "no db, sorry"})
+	r.RegisterSpec(Code(101), CodeSpec{HTTPStatus: 404, Name: "no table", Severity: SeverityError, Parent: CodeNotFound})
+
+	buf := &bytes.Buffer{}
+	if err := r.Export(buf, FormatCSV); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Errorf("expected a header & a line per registered code. got: %d lines", len(lines))
+	}
+	expect := []string{
+		"code,name,slug,http_status,severity,retryable,docs_url,grpc_code,exit_status,friendly,fix,parent",
+		"100,database,database,504,critical,false,,0,0,\"no db, sorry\",,0",
+		"101,no table,no_table,404,error,false,,0,0,,,6",
+	}
+	for i, line := range expect {
+		if i < len(lines) && lines[i] != line {
+			t.Errorf("csv line %d mismatch. expected: %s, got: %s", i, line, lines[i])
+		}
+	}
+
+	buf.Reset()
+	if err := r.Export(buf, FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	cat := catalog{}
+	if err := json.Unmarshal(buf.Bytes(), &cat); err != nil {
+		t.Fatal(err)
+	}
+	if len(cat.Codes) != 2 {
+		t.Fatalf("expected built-in codes to be left out. got: %d codes", len(cat.Codes))
+	}
+	if cat.Codes[0].Code != Code(100) {
+		t.Errorf("expected codes to be sorted ascending")
+	}
+
+	// an export loads into a new registry
+	loaded := NewRegistry()
+	if err := loaded.Load(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.CodeParent(Code(101)) != CodeNotFound {
+		t.Errorf("parent mismatch. expected: %d, got: %d", CodeNotFound, loaded.CodeParent(Code(101)))
+	}
+
+	if err := r.Export(buf, Format(-1)); err == nil {
+		t.Errorf("expected unknown format to error")
+	}
+
+	indented := Format(100)
+	RegisterCatalogFormat(indented, CatalogCodec{
+		Marshal: func(v interface{}) ([]byte, error) { return json.MarshalIndent(v, "", "\t") },
+	})
+	buf.Reset()
+	if err := r.Export(buf, indented); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "{\n\t\"codes\"") {
+		t.Errorf("expected registered format to write the catalog. got: %s", buf.String())
+	}
+}
@@ -0,0 +1,17 @@
+package errors
+
+// MarshalCBOR implements the cbor.Marshaler interface with the serializer
+// registered for "application/cbor", so errors can travel inside CBOR
+// envelopes. Programs must import github.com/qri-io/errors/errorscbor to
+// register one, without it MarshalCBOR fails with CodeNotImplemented:
+//
+//	import _ "github.com/qri-io/errors/errorscbor"
+func (e Error) MarshalCBOR() ([]byte, error) {
+	return marshalWith("application/cbor", "github.com/qri-io/errors/errorscbor", &e)
+}
+
+// UnmarshalCBOR implements the cbor.Unmarshaler interface, reading the output
+// of MarshalCBOR
+func (e *Error) UnmarshalCBOR(data []byte) error {
+	return unmarshalWith("application/cbor", "github.com/qri-io/errors/errorscbor", e, data)
+}
@@ -0,0 +1,82 @@
+package errors
+
+import (
+	"bytes"
+	goerrors "errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxResponseBody is the most of a response body FromHTTPResponse reads
+const maxResponseBody = 1 << 20
+
+// FromHTTPResponse creates an Error from an http response with an error
+// status, returning nil for statuses below 400. Bodies written by WriteHTTP,
+// problem details documents, and any content type with a registered
+// Serializer are decoded into a structured error. Other bodies, including
+// bodies that decode without a code, slug or message, become the
+// message of an error with the code named in the ErrorCodeHeader header, or
+// the code for the status, which also fills in codes missing from decoded
+// bodies. Rate limit headers are available from the error's RateLimit method,
+// and origin headers or trailers from OriginOf. The body is read, and replaced
+// so callers can read it again
+func FromHTTPResponse(resp *http.Response) *Error {
+	if resp == nil || resp.StatusCode < 400 {
+		return nil
+	}
+
+	var body []byte
+	if resp.Body != nil {
+		body, _ = io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	statusCode := CodeFromHTTPStatus(resp.StatusCode)
+	if slug := resp.Header.Get(ErrorCodeHeader); slug != "" {
+		if c := CodeFromSlug(slug); c != CodeUnknown {
+			statusCode = c
+		}
+	}
+	e := decodeResponseBody(resp, body)
+	if e == nil {
+		msg := strings.TrimSpace(string(body))
+		if msg == "" {
+			msg = http.StatusText(resp.StatusCode)
+		}
+		e = &Error{cause: goerrors.New(msg)}
+	}
+	if e.code == CodeUnknown {
+		e.code = statusCode
+	}
+	if rl, ok := rateLimitFromHeaders(resp.Header); ok {
+		e.rateLimit = rl
+	}
+	if o, ok := OriginFromHeader(resp.Header); ok {
+		e.origin = &o
+	} else if o, ok := OriginFromHeader(resp.Trailer); ok {
+		e.origin = &o
+	}
+	return e
+}
+
+// decodeResponseBody decodes an error from a response body with the
+// serializer for the response content type, returning nil if the body can't
+// be decoded. Bodies that decode without a code, slug or message, like the
+// JSON of an unrelated API's error layout, aren't errors this package wrote
+// and also return nil
+func decodeResponseBody(resp *http.Response, body []byte) *Error {
+	s, ok := SerializerFor(resp.Header.Get("Content-Type"))
+	if !ok || len(body) == 0 {
+		return nil
+	}
+	e, err := s.Unmarshal(body)
+	if err != nil || e == nil {
+		return nil
+	}
+	if e.code == CodeUnknown && e.Message() == "" {
+		return nil
+	}
+	return e
+}
@@ -0,0 +1,91 @@
+package errors
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFromHTTPResponse(t *testing.T) {
+	e := NewFriendlyFix(CodeNotFound, "no dataset", "couldn't find that dataset", "check the name").WithDetail("ref", "b5/world_bank")
+	for _, accept := range []string{"application/json", ProblemContentType, "application/xml"} {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		WriteHTTPRequest(w, r, e)
+
+		got := FromHTTPResponse(w.Result())
+		if got == nil {
+			t.Fatalf("%s: expected an error", accept)
+		}
+		if got.Code() != CodeNotFound {
+			t.Errorf("%s code mismatch. expected: %d, got: %d", accept, CodeNotFound, got.Code())
+		}
+		if got.Friendly() != e.Friendly() {
+			t.Errorf("%s friendly mismatch. expected: %s, got: %s", accept, e.Friendly(), got.Friendly())
+		}
+		if got.Fix() != e.Fix() {
+			t.Errorf("%s fix mismatch. expected: %s, got: %s", accept, e.Fix(), got.Fix())
+		}
+	}
+}
+
+func TestFromHTTPResponsePlain(t *testing.T) {
+	w := httptest.NewRecorder()
+	http.Error(w, "bad gateway token", http.StatusUnauthorized)
+	resp := w.Result()
+	got := FromHTTPResponse(resp)
+	if got.Code() != CodeUnauthorized {
+		t.Errorf("code mismatch. expected: %d, got: %d", CodeUnauthorized, got.Code())
+	}
+	if got.Message() != "bad gateway token" {
+		t.Errorf("message mismatch. expected: %s, got: %s", "bad gateway token", got.Message())
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "bad gateway token\n" {
+		t.Errorf("expected body to be readable again. got: %q", body)
+	}
+
+	w = httptest.NewRecorder()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	w.WriteString(`{"error":"dataset foo/bar not found"}`)
+	got = FromHTTPResponse(w.Result())
+	if got.Code() != CodeNotFound {
+		t.Errorf("foreign json code mismatch. expected: %d, got: %d", CodeNotFound, got.Code())
+	}
+	if got.Message() != `{"error":"dataset foo/bar not found"}` {
+		t.Errorf("expected foreign json to be kept as the message. got: %s", got.Message())
+	}
+
+	w = httptest.NewRecorder()
+	w.WriteHeader(http.StatusNotFound)
+	if got := FromHTTPResponse(w.Result()); got.Message() != "Not Found" {
+		t.Errorf("message mismatch. expected: %s, got: %s", "Not Found", got.Message())
+	}
+
+	w = httptest.NewRecorder()
+	w.WriteHeader(http.StatusOK)
+	if got := FromHTTPResponse(w.Result()); got != nil {
+		t.Errorf("expected success responses to return nil. got: %s", got)
+	}
+}
+
+func TestFromHTTPResponseCodeHeader(t *testing.T) {
+	w := httptest.NewRecorder()
+	WriteHTTP(w, New(CodeConflict, "dataset exists"))
+	if got := w.Header().Get(ErrorCodeHeader); got != "conflict" {
+		t.Errorf("header mismatch. expected: %s, got: %s", "conflict", got)
+	}
+
+	// a proxy that rewrites error bodies keeps the header
+	w = httptest.NewRecorder()
+	w.Header().Set(ErrorCodeHeader, "too_many_requests")
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	w.WriteString("<h1>upstream error</h1>")
+	if got := FromHTTPResponse(w.Result()); got.Code() != CodeTooManyRequests {
+		t.Errorf("code mismatch. expected: %d, got: %d", CodeTooManyRequests, got.Code())
+	}
+}
@@ -0,0 +1,216 @@
+package errors
+
+import (
+	"encoding/binary"
+	goerrors "errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// compactVersion is the leading byte of compact encodings
+const compactVersion byte = 1
+
+// presence flags for optional fields in compact encodings
+const (
+	compactFriendly byte = 1 << iota
+	compactFix
+	compactField
+	compactData
+	compactDetails
+)
+
+// type tags for values in compact encodings
+const (
+	compactString byte = iota
+	compactInt
+	compactBool
+	compactFloat
+)
+
+// EncodeCompact writes an error in a hand-rolled varint-based binary format
+// that fits the code, message, and details of a typical error in a few dozen
+// bytes, for embedding errors in p2p control messages and other small
+// payloads. Only the default friendly & fix messages are left out, and are
+// restored on decoding. Strings, integers, bools and floats in data & details
+// keep their type, all other values are written as strings
+func EncodeCompact(e *Error) []byte {
+	reg := e.Registry()
+	buf := []byte{compactVersion, 0}
+	buf = binary.AppendVarint(buf, int64(reg.Canonical(e.code)))
+	buf = appendCompactString(buf, e.Message())
+
+	var flags byte
+	if e.friendly != "" {
+		flags |= compactFriendly
+		buf = appendCompactString(buf, e.friendly)
+	}
+	if e.fix != "" {
+		flags |= compactFix
+		buf = appendCompactString(buf, e.fix)
+	}
+	if e.field != "" {
+		flags |= compactField
+		buf = appendCompactString(buf, e.field)
+	}
+	if len(e.data) > 0 {
+		flags |= compactData
+		buf = binary.AppendUvarint(buf, uint64(len(e.data)))
+		for _, d := range e.data {
+			buf = appendCompactValue(buf, d)
+		}
+	}
+	if len(e.details) > 0 {
+		flags |= compactDetails
+		keys := make([]string, 0, len(e.details))
+		for key := range e.details {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		buf = binary.AppendUvarint(buf, uint64(len(keys)))
+		for _, key := range keys {
+			buf = appendCompactString(buf, key)
+			buf = appendCompactValue(buf, e.details[key])
+		}
+	}
+	buf[1] = flags
+	return buf
+}
+
+// DecodeCompact reads an error written by EncodeCompact
+func DecodeCompact(data []byte) (*Error, error) {
+	r := &compactReader{buf: data}
+	if v := r.byte(); v != compactVersion {
+		return nil, New(CodeInvalidSyntax, "unsupported compact error version", v)
+	}
+	flags := r.byte()
+	e := &Error{code: Code(r.varint())}
+	e.cause = goerrors.New(r.string())
+	if flags&compactFriendly != 0 {
+		e.friendly = r.string()
+	}
+	if flags&compactFix != 0 {
+		e.fix = r.string()
+	}
+	if flags&compactField != 0 {
+		e.field = r.string()
+	}
+	if flags&compactData != 0 {
+		n := r.uvarint()
+		for i := uint64(0); i < n && r.err == nil; i++ {
+			e.data = append(e.data, r.value())
+		}
+	}
+	if flags&compactDetails != 0 {
+		n := r.uvarint()
+		for i := uint64(0); i < n && r.err == nil; i++ {
+			key := r.string()
+			e.WithDetail(key, r.value())
+		}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return e, nil
+}
+
+// appendCompactString writes a length-prefixed string
+func appendCompactString(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+// appendCompactValue writes a type-tagged value
+func appendCompactValue(buf []byte, v interface{}) []byte {
+	switch x := v.(type) {
+	case string:
+		return appendCompactString(append(buf, compactString), x)
+	case int:
+		return binary.AppendVarint(append(buf, compactInt), int64(x))
+	case int64:
+		return binary.AppendVarint(append(buf, compactInt), x)
+	case int32:
+		return binary.AppendVarint(append(buf, compactInt), int64(x))
+	case bool:
+		if x {
+			return append(buf, compactBool, 1)
+		}
+		return append(buf, compactBool, 0)
+	case float64:
+		return binary.AppendUvarint(append(buf, compactFloat), math.Float64bits(x))
+	default:
+		return appendCompactString(append(buf, compactString), fmt.Sprint(x))
+	}
+}
+
+// compactReader reads compact encodings, holding the first error encountered
+type compactReader struct {
+	buf []byte
+	err error
+}
+
+func (r *compactReader) fail() {
+	if r.err == nil {
+		r.err = New(CodeInvalidSyntax, "truncated compact error")
+	}
+	r.buf = nil
+}
+
+func (r *compactReader) byte() byte {
+	if len(r.buf) == 0 {
+		r.fail()
+		return 0
+	}
+	b := r.buf[0]
+	r.buf = r.buf[1:]
+	return b
+}
+
+func (r *compactReader) varint() int64 {
+	v, n := binary.Varint(r.buf)
+	if n <= 0 {
+		r.fail()
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *compactReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		r.fail()
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *compactReader) string() string {
+	n := r.uvarint()
+	if uint64(len(r.buf)) < n {
+		r.fail()
+		return ""
+	}
+	s := string(r.buf[:n])
+	r.buf = r.buf[n:]
+	return s
+}
+
+func (r *compactReader) value() interface{} {
+	switch r.byte() {
+	case compactString:
+		return r.string()
+	case compactInt:
+		return int(r.varint())
+	case compactBool:
+		return r.byte() == 1
+	case compactFloat:
+		return math.Float64frombits(r.uvarint())
+	default:
+		if r.err == nil {
+			r.err = New(CodeInvalidSyntax, "unknown compact value type")
+		}
+		return nil
+	}
+}
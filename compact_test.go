@@ -0,0 +1,55 @@
+package errors
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompactRoundTrip(t *testing.T) {
+	e := NewFriendly(CodeNotFound, "no dataset", "couldn't find", "b5/world_bank", 3, true).
+		WithField("ref").
+		WithDetail("peer", "QmPeer").
+		WithDetail("ratio", 0.5)
+
+	data := EncodeCompact(e)
+	got, err := DecodeCompact(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Code() != CodeNotFound || got.Error() != e.Error() || got.Friendly() != e.Friendly() || got.Field() != "ref" {
+		t.Errorf("round trip mismatch. expected: %s, got: %s", e.Friendly(), got.Friendly())
+	}
+	if !reflect.DeepEqual(got.Data(), e.Data()) {
+		t.Errorf("data mismatch. expected: %v, got: %v", e.Data(), got.Data())
+	}
+	if !reflect.DeepEqual(got.Details(), e.Details()) {
+		t.Errorf("details mismatch. expected: %v, got: %v", e.Details(), got.Details())
+	}
+}
+
+func TestCompactSize(t *testing.T) {
+	e := New(CodeUnavailable, "peer unreachable").WithDetail("peer", "QmPeer")
+	data := EncodeCompact(e)
+	if len(data) > 40 {
+		t.Errorf("expected compact encoding to fit in 40 bytes. got: %d", len(data))
+	}
+	got, err := DecodeCompact(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Fix() != "please try again later" {
+		t.Errorf("expected default fix to be restored. got: %s", got.Fix())
+	}
+}
+
+func TestDecodeCompactErrors(t *testing.T) {
+	data := EncodeCompact(New(CodeNotFound, "no dataset").WithDetail("peer", "QmPeer"))
+	for i := 0; i < len(data); i++ {
+		if _, err := DecodeCompact(data[:i]); err == nil {
+			t.Errorf("expected decoding %d of %d bytes to error", i, len(data))
+		}
+	}
+	if _, err := DecodeCompact([]byte{9, 0}); err == nil {
+		t.Errorf("expected unknown version to error")
+	}
+}
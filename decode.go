@@ -0,0 +1,112 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// DecodeMode selects how decoders treat serialized errors written by services
+// that know codes or fields this one doesn't
+type DecodeMode int
+
+const (
+	// DecodeDefault keeps unknown codes as written and drops unknown fields.
+	// UnmarshalJSON decodes in this mode
+	DecodeDefault DecodeMode = iota
+	// DecodeStrict rejects errors with unknown codes or fields
+	DecodeStrict
+	// DecodeLenient maps unknown codes to CodeGeneric and keeps unknown fields
+	// in details, so gateways can tolerate newer upstream services
+	DecodeLenient
+)
+
+// wireFields lists the field names of the serialized error layout
+var wireFields = func() map[string]bool {
+	fields := map[string]bool{}
+	t := reflect.TypeOf(jsonError{})
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		fields[name] = true
+	}
+	return fields
+}()
+
+// DecodeJSON reads the output of MarshalJSON in the given mode, resolving
+// codes with the default registry
+func DecodeJSON(data []byte, mode DecodeMode) (*Error, error) {
+	return DefaultRegistry.DecodeJSON(data, mode)
+}
+
+// DecodeJSON reads the output of MarshalJSON in the given mode, resolving
+// codes with this registry. Codes are unknown if they aren't registered here.
+// Decoded errors describe their codes with this registry
+func (r *Registry) DecodeJSON(data []byte, mode DecodeMode) (*Error, error) {
+	je := jsonError{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if mode == DecodeStrict {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(&je); err != nil {
+		return nil, Wrap(CodeInvalidSyntax, err, "decoding error")
+	}
+	if err := r.resolveWireCode(&je, mode); err != nil {
+		return nil, err
+	}
+
+	if mode == DecodeLenient {
+		fields := map[string]interface{}{}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, Wrap(CodeInvalidSyntax, err, "decoding error")
+		}
+		for key, val := range fields {
+			if wireFields[key] {
+				continue
+			}
+			if je.Details == nil {
+				je.Details = map[string]interface{}{}
+			}
+			je.Details[key] = val
+		}
+	}
+
+	e := &Error{registry: r}
+	e.fromJSONError(je)
+	return e, nil
+}
+
+// resolveWireCode applies mode to the codes of a serialized error and it's
+// causes, setting codes written only as slugs
+func (r *Registry) resolveWireCode(je *jsonError, mode DecodeMode) error {
+	if je.Cause != nil {
+		if err := r.resolveWireCode(je.Cause, mode); err != nil {
+			return err
+		}
+	}
+	for i := range je.Causes {
+		if err := r.resolveWireCode(&je.Causes[i], mode); err != nil {
+			return err
+		}
+	}
+	if mode == DecodeDefault || (je.Code == CodeUnknown && je.Slug == "") {
+		return nil
+	}
+
+	known := false
+	if je.Code == CodeUnknown {
+		je.Code = r.CodeFromSlug(je.Slug)
+		known = je.Code != CodeUnknown || je.Slug == r.CodeSlug(CodeUnknown)
+	} else {
+		_, known = r.Lookup(je.Code)
+	}
+	if known {
+		return nil
+	}
+	if mode == DecodeStrict {
+		return New(CodeInvalidArgs, "unknown error code", je.Code, je.Slug)
+	}
+	je.Code = CodeGeneric
+	je.Slug = r.CodeSlug(CodeGeneric)
+	return nil
+}
@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"testing"
+)
+
+func TestDecodeJSONModes(t *testing.T) {
+	known := []byte(`{"version":1,"code":6,"slug":"not_found","message":"no dataset"}`)
+	unknownCode := []byte(`{"version":1,"code":4242,"slug":"quota_exceeded","message":"over quota"}`)
+	unknownSlug := []byte(`{"version":2,"slug":"quota_exceeded","message":"over quota"}`)
+	unknownField := []byte(`{"version":2,"code":6,"message":"no dataset","region":"eu"}`)
+
+	for _, data := range [][]byte{known, unknownCode, unknownSlug, unknownField} {
+		if _, err := DecodeJSON(data, DecodeDefault); err != nil {
+			t.Errorf("default mode error decoding %s: %s", data, err)
+		}
+	}
+
+	if _, err := DecodeJSON(known, DecodeStrict); err != nil {
+		t.Errorf("strict mode error decoding known error: %s", err)
+	}
+	for _, data := range [][]byte{unknownCode, unknownSlug, unknownField} {
+		if _, err := DecodeJSON(data, DecodeStrict); err == nil {
+			t.Errorf("expected strict mode to reject %s", data)
+		}
+	}
+
+	got, err := DecodeJSON(unknownCode, DecodeDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Code() != Code(4242) {
+		t.Errorf("default mode code mismatch. expected: %d, got: %d", 4242, got.Code())
+	}
+
+	for _, data := range [][]byte{unknownCode, unknownSlug} {
+		got, err := DecodeJSON(data, DecodeLenient)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Code() != CodeGeneric {
+			t.Errorf("lenient mode code mismatch. expected: %d, got: %d", CodeGeneric, got.Code())
+		}
+		if got.Message() != "over quota" {
+			t.Errorf("lenient mode message mismatch. expected: %s, got: %s", "over quota", got.Message())
+		}
+	}
+
+	got, err = DecodeJSON(unknownField, DecodeLenient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Details()["region"] != "eu" {
+		t.Errorf("expected lenient mode to keep unknown fields in details. got: %v", got.Details())
+	}
+}
+
+func TestRegistryDecodeJSON(t *testing.T) {
+	r := NewRegistry()
+	if err := r.RegisterSpec(Code(150), CodeSpec{HTTPStatus: 429, Name: "quota exceeded"}); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte(`{"slug":"quota_exceeded","message":"over quota"}`)
+	got, err := r.DecodeJSON(data, DecodeStrict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Code() != Code(150) {
+		t.Errorf("code mismatch. expected: %d, got: %d", 150, got.Code())
+	}
+	if got.Registry() != r {
+		t.Errorf("expected decoded error to use the decoding registry")
+	}
+}
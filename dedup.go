@@ -0,0 +1,102 @@
+package errors
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// detail keys of aggregated reports
+const (
+	CountDetail     = "count"
+	FirstSeenDetail = "first_seen"
+	LastSeenDetail  = "last_seen"
+)
+
+// DedupReporter buffers reports for a time window, grouping them by
+// Fingerprint, and forwards one aggregated report per group when the window
+// closes. The first error of a group is forwarded with CountDetail,
+// FirstSeenDetail & LastSeenDetail details, which cuts the noise of errors
+// raised in hot loops. Call Flush before exiting to forward buffered reports
+type DedupReporter struct {
+	next   Reporter
+	window time.Duration
+	now    func() time.Time
+
+	lk     sync.Mutex
+	groups map[string]*dedupGroup
+}
+
+// dedupGroup is a buffered group of reports
+type dedupGroup struct {
+	ctx       context.Context
+	e         *Error
+	count     int
+	firstSeen time.Time
+	lastSeen  time.Time
+	timer     *time.Timer
+}
+
+// NewDedupReporter wraps a reporter with deduplication over window
+func NewDedupReporter(next Reporter, window time.Duration) *DedupReporter {
+	return &DedupReporter{
+		next:   next,
+		window: window,
+		now:    time.Now,
+		groups: map[string]*dedupGroup{},
+	}
+}
+
+// Report implements the Reporter interface
+func (d *DedupReporter) Report(ctx context.Context, e *Error) {
+	fp := Fingerprint(e)
+	now := d.now()
+	d.lk.Lock()
+	defer d.lk.Unlock()
+
+	if g, ok := d.groups[fp]; ok {
+		g.count++
+		g.lastSeen = now
+		return
+	}
+	g := &dedupGroup{
+		ctx:       context.WithoutCancel(ctx),
+		e:         e,
+		count:     1,
+		firstSeen: now,
+		lastSeen:  now,
+	}
+	g.timer = time.AfterFunc(d.window, func() { d.flush(fp) })
+	d.groups[fp] = g
+}
+
+// Flush forwards every buffered group right away
+func (d *DedupReporter) Flush() {
+	d.lk.Lock()
+	fps := make([]string, 0, len(d.groups))
+	for fp := range d.groups {
+		fps = append(fps, fp)
+	}
+	d.lk.Unlock()
+
+	for _, fp := range fps {
+		d.flush(fp)
+	}
+}
+
+// flush forwards the group for a fingerprint, if it's still buffered
+func (d *DedupReporter) flush(fp string) {
+	d.lk.Lock()
+	g, ok := d.groups[fp]
+	delete(d.groups, fp)
+	d.lk.Unlock()
+	if !ok {
+		return
+	}
+	g.timer.Stop()
+
+	e := g.e.withDetailCopy(CountDetail, g.count)
+	e.details[FirstSeenDetail] = g.firstSeen
+	e.details[LastSeenDetail] = g.lastSeen
+	d.next.Report(g.ctx, e)
+}
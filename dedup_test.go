@@ -0,0 +1,63 @@
+package errors
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDedupReporter(t *testing.T) {
+	var forwarded []*Error
+	d := NewDedupReporter(ReporterFunc(func(ctx context.Context, e *Error) {
+		forwarded = append(forwarded, e)
+	}), time.Hour)
+	start := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	now := start
+	d.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	first := New(CodeUnavailable, "peer unavailable")
+	for i := 0; i < 5; i++ {
+		d.Report(ctx, first)
+		now = now.Add(time.Second)
+	}
+	d.Report(ctx, New(CodeTimeout, "other"))
+	if len(forwarded) != 0 {
+		t.Fatalf("expected reports to be buffered. got: %d", len(forwarded))
+	}
+
+	d.Flush()
+	if len(forwarded) != 2 {
+		t.Fatalf("expected one report per fingerprint. got: %d", len(forwarded))
+	}
+	var agg *Error
+	for _, e := range forwarded {
+		if e.Code() == CodeUnavailable {
+			agg = e
+		}
+	}
+	if agg.Details()[CountDetail] != 5 {
+		t.Errorf("count mismatch. expected: %d, got: %v", 5, agg.Details()[CountDetail])
+	}
+	if agg.Details()[FirstSeenDetail] != start || agg.Details()[LastSeenDetail] != start.Add(4*time.Second) {
+		t.Errorf("seen times mismatch. got: %v - %v", agg.Details()[FirstSeenDetail], agg.Details()[LastSeenDetail])
+	}
+	if len(first.Details()) != 0 {
+		t.Errorf("expected the reported error to be left unchanged. got: %v", first.Details())
+	}
+}
+
+func TestDedupReporterWindow(t *testing.T) {
+	done := make(chan *Error, 1)
+	d := NewDedupReporter(ReporterFunc(func(ctx context.Context, e *Error) { done <- e }), 10*time.Millisecond)
+	d.Report(context.Background(), New(CodeNotFound, "no dataset"))
+
+	select {
+	case e := <-done:
+		if e.Details()[CountDetail] != 1 {
+			t.Errorf("count mismatch. expected: %d, got: %v", 1, e.Details()[CountDetail])
+		}
+	case <-time.After(time.Second):
+		t.Errorf("expected the report to be forwarded when the window closed")
+	}
+}
@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// diagnosticsEnv summarizes the environment a diagnostic bundle was written
+// in. It deliberately leaves out environment variables & program arguments,
+// which often hold secrets
+type diagnosticsEnv struct {
+	Time         time.Time `json:"time"`
+	GoVersion    string    `json:"go_version"`
+	OS           string    `json:"os"`
+	Arch         string    `json:"arch"`
+	NumCPU       int       `json:"num_cpu"`
+	NumGoroutine int       `json:"num_goroutine"`
+	Executable   string    `json:"executable,omitempty"`
+}
+
+// WriteDiagnostics writes a zip archive to w holding everything needed to
+// investigate a hard failure, so users can attach a single file to a support
+// ticket. The archive holds:
+//
+//	error.json       the full cause chain & stacks, as MarshalJSONDebug
+//	error.md         the error as ToMarkdown
+//	build.txt        module build info
+//	environment.json the time, go version, platform & executable name
+//
+// Diagnostics reveal the internals of a program, and should only be sent to
+// trusted parties
+func WriteDiagnostics(w io.Writer, err error) error {
+	zw := zip.NewWriter(w)
+
+	debugJSON, merr := MarshalJSONDebug(err)
+	if merr != nil {
+		return Wrap(CodeInternal, merr, "marshaling error")
+	}
+	build := "build info unavailable\n"
+	if info, ok := debug.ReadBuildInfo(); ok {
+		build = info.String()
+	}
+	env := diagnosticsEnv{
+		Time:         time.Now().UTC(),
+		GoVersion:    runtime.Version(),
+		OS:           runtime.GOOS,
+		Arch:         runtime.GOARCH,
+		NumCPU:       runtime.NumCPU(),
+		NumGoroutine: runtime.NumGoroutine(),
+	}
+	if exe, eerr := os.Executable(); eerr == nil {
+		env.Executable = filepath.Base(exe)
+	}
+	envJSON, merr := json.MarshalIndent(env, "", "  ")
+	if merr != nil {
+		return Wrap(CodeInternal, merr, "marshaling environment")
+	}
+
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{"error.json", debugJSON},
+		{"error.md", []byte(ToMarkdown(err))},
+		{"build.txt", []byte(build)},
+		{"environment.json", envJSON},
+	}
+	for _, f := range files {
+		fw, zerr := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: env.Time})
+		if zerr != nil {
+			return Wrap(CodeInternal, zerr, "writing diagnostics", f.name)
+		}
+		if _, zerr := fw.Write(f.data); zerr != nil {
+			return Wrap(CodeInternal, zerr, "writing diagnostics", f.name)
+		}
+	}
+	if zerr := zw.Close(); zerr != nil {
+		return Wrap(CodeInternal, zerr, "writing diagnostics")
+	}
+	return nil
+}
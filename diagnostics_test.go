@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestWriteDiagnostics(t *testing.T) {
+	cause := New(CodeUnavailable, "peer unavailable")
+	err := Wrap(CodeGeneric, cause, "loading dataset")
+
+	buf := &bytes.Buffer{}
+	if werr := WriteDiagnostics(buf, err); werr != nil {
+		t.Fatal(werr)
+	}
+	zr, zerr := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if zerr != nil {
+		t.Fatal(zerr)
+	}
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, oerr := f.Open()
+		if oerr != nil {
+			t.Fatal(oerr)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+
+	for _, name := range []string{"error.json", "error.md", "build.txt", "environment.json"} {
+		if files[name] == "" {
+			t.Errorf("expected %s in the archive", name)
+		}
+	}
+	if !strings.Contains(files["error.json"], "peer unavailable") || !strings.Contains(files["error.json"], `"stack"`) {
+		t.Errorf("expected error.json to hold the chain & stacks. got: %s", files["error.json"])
+	}
+	env := diagnosticsEnv{}
+	if jerr := json.Unmarshal([]byte(files["environment.json"]), &env); jerr != nil {
+		t.Fatal(jerr)
+	}
+	if env.GoVersion != runtime.Version() || env.OS != runtime.GOOS {
+		t.Errorf("environment mismatch. got: %+v", env)
+	}
+}
@@ -6,20 +6,27 @@
 // an application stack, only adding context & detail when handling the error
 // is in a position to provide additional error details for the end user
 //
-// any lower level error would be better off using github.com/pkg/errors, which
-// will interoperate nicely with this package
+// any lower level error would be better off using the standard library's
+// error wrapping, or github.com/pkg/errors, both of which will interoperate
+// nicely with this package
 package errors
 
 import (
+	"context"
+	goerrors "errors"
 	"fmt"
-
-	"github.com/pkg/errors"
+	"strings"
 )
 
 // Code assigns numeric values to different categories of error
-// Users are encouraged to define their own application-specific errors
+// Users are encouraged to define their own application-specific errors.
+// Codes from zero through MaxReservedCode are reserved for this package,
+// application codes must be greater than MaxReservedCode
 type Code int
 
+// MaxReservedCode is the highest code reserved for built-in codes
+const MaxReservedCode Code = 99
+
 const (
 	// CodeUnknown should never be used, indicates unspecified default
 	CodeUnknown Code = iota
@@ -40,48 +47,44 @@ const (
 	// CodeUnavailable indicates something that needs to be available cannot
 	// be reached
 	CodeUnavailable
+	// CodeConflict indicates a request conflicts with the current state of
+	// the target, eg: creating something that already exists
+	CodeConflict
+	// CodeTooManyRequests indicates a rate limit has been exceeded
+	CodeTooManyRequests
+	// CodeTimeout indicates an operation didn't complete in the time allotted
+	CodeTimeout
+	// CodeCancelled indicates an operation was cancelled, usually by the caller
+	CodeCancelled
+	// CodeNotImplemented indicates requested functionality doesn't exist
+	CodeNotImplemented
+	// CodePreconditionFailed indicates a condition the request depends on
+	// wasn't met
+	CodePreconditionFailed
+	// CodeGone indicates a value existed, but has been permanently removed
+	CodeGone
+	// CodeInternal indicates a bug. something happened that should never
+	// happen
+	CodeInternal
+	// CodeUsage indicates a command line program was invoked incorrectly, eg:
+	// with an unknown flag. CodeUsage is in the CodeInvalidArgs category
+	CodeUsage
 )
 
-type codeDetails struct {
-	httpStatus int
-	str        string
-}
-
-var codePool = map[Code]codeDetails{
-	CodeUnknown:       {500, "error"},
-	CodeGeneric:       {500, "error"},
-	CodeInvalidSyntax: {400, "syntax"},
-	CodeInvalidArgs:   {400, "arguments"},
-	CodeUnauthorized:  {401, "auth"},
-	CodeForbidden:     {403, "auth"},
-	CodeNotFound:      {404, "missing"},
-	CodeUnavailable:   {503, "unavailable"},
-}
-
 // RegisterCode adds a code to error's internal code pool for extending Error with
 // custom http and string values for codes
 func RegisterCode(c Code, httpStatus int, typeStr string) error {
-	if _, ok := codePool[c]; ok {
-		return New(CodeInvalidArgs, "already registered", c)
-	}
-	codePool[c] = codeDetails{httpStatus, typeStr}
-	return nil
+	return DefaultRegistry.Register(c, httpStatus, typeStr)
 }
 
 // CodeString returns a string representation of a code, defaulting to "error"
 func CodeString(c Code) string {
-	if s, ok := codePool[c]; ok {
-		return s.str
-	}
-	return "error"
+	return DefaultRegistry.CodeString(c)
 }
 
 // CodeHTTPStatus converts a Code to an http status code, defaulting to 500
 func CodeHTTPStatus(c Code) int {
-	if s, ok := codePool[c]; ok {
-		return s.httpStatus
-	}
-	return 500
+	return DefaultRegistry.CodeHTTPStatus(c)
 }
 
 // Error decorates an error with additional fields for user feedback
@@ -94,13 +97,33 @@ type Error struct {
 	code     Code
 	friendly string
 	fix      string
-	data     []interface{}
-	cause    error
+	// fixCommands are shell commands that fix the error
+	fixCommands []string
+	data        []interface{}
+	details     map[string]interface{}
+	field       string
+	cause       error
+	registry    *Registry
+	stack       stack
+	// headers are http headers to write with the error
+	headers map[string][]string
+	// rateLimit describes the limit a rate limited request exceeded
+	rateLimit *RateLimit
+	// origin identifies where an error received from another service was
+	// first raised
+	origin *Origin
+	// timeout & temporary override code-derived values when set
+	timeout   *bool
+	temporary *bool
+	// noHooks opts the error out of hooks registered with RegisterHook
+	noHooks bool
+	// noDefaultFix leaves out the code's default fix when fix is empty
+	noDefaultFix bool
 }
 
 // Error satisfies the error interface, printing just top-level error
 func (e Error) Error() string {
-	return fmt.Sprintf("%s: %s", CodeString(e.code), e.cause.Error())
+	return fmt.Sprintf("%s: %s", e.Registry().CodeString(e.code), e.cause.Error())
 }
 
 // Cause implements the causer interface from the errors standard package
@@ -108,79 +131,453 @@ func (e Error) Cause() error {
 	return e.cause
 }
 
+// Unwrap returns the errors e wraps, allowing errors.Is and errors.As from the
+// standard library to find any error in the chain, including every member of
+// a joined error
+func (e Error) Unwrap() []error {
+	if e.cause == nil {
+		return nil
+	}
+	return []error{e.cause}
+}
+
 // Code gives the type of error
 func (e Error) Code() Code {
 	return e.code
 }
 
-// Fix returns the internal message on how to fix the error
+// Message returns the developer-focused error message, without the code
+// prefix Error adds
+func (e Error) Message() string {
+	if e.cause == nil {
+		return ""
+	}
+	return e.cause.Error()
+}
+
+// Data returns the values given to the error as data params
+func (e Error) Data() []interface{} {
+	return e.data
+}
+
+// Details returns structured key-value details attached to the error
+func (e Error) Details() map[string]interface{} {
+	return e.details
+}
+
+// WithDetail attaches a structured key-value detail to the error. Details
+// should be serializable values
+func (e *Error) WithDetail(key string, value interface{}) *Error {
+	if e.details == nil {
+		e.details = map[string]interface{}{}
+	}
+	e.details[key] = value
+	return e
+}
+
+// Field returns the name of the input field the error applies to, if any
+func (e Error) Field() string {
+	return e.field
+}
+
+// WithField sets the input field the error applies to, for validation errors
+// about a specific field. Nested fields are separated with periods, eg:
+// "meta.title". A batch of field errors can be grouped with WrapAll
+func (e *Error) WithField(field string) *Error {
+	e.field = field
+	return e
+}
+
+// StackTrace returns the call stack captured when the error was created
+func (e Error) StackTrace() []Frame {
+	return e.stack.frames()
+}
+
+// Timeout reports whether the error was caused by a timeout, satisfying the
+// net.Error interface. Errors with a code in the CodeTimeout category are
+// timeouts unless set otherwise with WithTimeout
+func (e Error) Timeout() bool {
+	if e.timeout != nil {
+		return *e.timeout
+	}
+	return e.Registry().IsInCategory(e.code, CodeTimeout)
+}
+
+// WithTimeout explicitly sets whether the error is a timeout
+func (e *Error) WithTimeout(timeout bool) *Error {
+	e.timeout = &timeout
+	return e
+}
+
+// Temporary reports whether the error is temporary, satisfying the net.Error
+// interface. Errors with a retryable code are temporary unless set otherwise
+// with WithTemporary
+func (e Error) Temporary() bool {
+	if e.temporary != nil {
+		return *e.temporary
+	}
+	return e.Registry().CodeRetryable(e.code)
+}
+
+// WithTemporary explicitly sets whether the error is temporary
+func (e *Error) WithTemporary(temporary bool) *Error {
+	e.temporary = &temporary
+	return e
+}
+
+// Registry returns the registry used to describe the error's code, defaulting
+// to DefaultRegistry
+func (e Error) Registry() *Registry {
+	if e.registry == nil {
+		return DefaultRegistry
+	}
+	return e.registry
+}
+
+// WithRegistry sets the registry used to describe the error's code. Libraries
+// that keep their own Registry should attach it to errors they create
+func (e *Error) WithRegistry(r *Registry) *Error {
+	e.registry = r
+	return e
+}
+
+// Fix returns the internal message on how to fix the error, falling back to
+// the default fix message for the error's code
 func (e Error) Fix() string {
+	if e.fix == "" && !e.noDefaultFix {
+		return e.Registry().CodeFix(e.code)
+	}
 	return e.fix
 }
 
-// Friendly returns the friendly message along
+// FixCommands returns shell commands that fix the error, if any
+func (e Error) FixCommands() []string {
+	return e.fixCommands
+}
+
+// WithFixCommands sets shell commands that fix the error, eg: "qri connect".
+// Terminal renderers print commands as a block users can copy & paste, and
+// command line frontends may offer to run them
+func (e *Error) WithFixCommands(cmds ...string) *Error {
+	e.fixCommands = cmds
+	return e
+}
+
+// Friendly returns the friendly message along with any data values and fix,
+// falling back to the default messages for the error's code. Friendly renders
+// in DefaultLanguage, use FriendlyIn for other languages
 func (e Error) Friendly() string {
-	if e.friendly == "" && e.fix == "" {
-		return ""
-	}
+	return e.FriendlyIn(DefaultLanguage)
+}
 
-	str := fmt.Sprintf("%s: %s", CodeString(e.code), e.friendly)
-	for i, d := range e.data {
-		str += fmt.Sprintf(" %v", d)
-		if i < len(e.data)-1 {
-			str += ","
-		} else {
-			str += "."
-		}
-	}
-	if e.fix != "" {
-		str += fmt.Sprintf(" %s", e.fix)
+// FriendlyMessage returns the friendly message without data or fix, falling
+// back to the default friendly message for the error's code. Use it to carry
+// friendly messages through other error formats
+func (e Error) FriendlyMessage() string {
+	return e.friendlyMessage()
+}
+
+// friendlyMessage returns the friendly message without data or fix, falling
+// back to the default friendly message for the error's code
+func (e Error) friendlyMessage() string {
+	if e.friendly == "" {
+		return e.Registry().CodeFriendly(e.code)
 	}
-	return str
+	return e.friendly
 }
 
 // New creates an Error from an error and string
 func New(c Code, message string, data ...interface{}) *Error {
-	err := errors.New(message)
-	return &Error{code: c, data: data, cause: err}
+	return hooked(newError(c, message, data))
 }
 
 // NewFriendly creates an error with a user-friendly message
 func NewFriendly(c Code, message, friendly string, data ...interface{}) *Error {
-	err := New(c, message, data...)
+	err := newError(c, message, data)
 	err.friendly = friendly
-	return err
+	return hooked(err)
 }
 
 // NewFriendlyFix creates an error with a message and a fix
 func NewFriendlyFix(c Code, message, friendly, fix string, data ...interface{}) *Error {
-	err := NewFriendly(c, message, friendly, data...)
+	err := newError(c, message, data)
+	err.friendly = friendly
 	err.fix = fix
-	return err
+	return hooked(err)
 }
 
 // Wrap returns an error annotating err with a stack trace
 // at the point Wrap is called, and the supplied message.
-// If err is nil, Wrap returns nil.
+// If err is nil, Wrap returns nil without calling hooks.
+// Wrapping an error caused by context cancellation with CodeUnknown classifies
+// the error as CodeCancelled or CodeTimeout. This applies to all Wrap variants
 func Wrap(c Code, err error, message string, data ...interface{}) *Error {
-	return &Error{code: c, data: data, cause: errors.Wrap(err, message)}
+	if err == nil {
+		return nil
+	}
+	return hooked(wrapError(c, err, message, data))
+}
+
+// newError creates an Error, capturing the stack of the caller's caller.
+// exported constructors must call newError directly
+func newError(c Code, message string, data []interface{}) *Error {
+	data, skip := stripNoHooks(data)
+	return &Error{code: c, data: data, cause: goerrors.New(message), stack: callers(4), noHooks: skip}
+}
+
+// wrapError wraps err in an Error, capturing the stack of the caller's caller.
+// exported constructors must call wrapError directly, and return nil for a nil
+// err instead of calling it
+func wrapError(c Code, err error, message string, data []interface{}) *Error {
+	if c == CodeUnknown {
+		c = contextCode(err)
+	}
+	data, skip := stripNoHooks(data)
+	return &Error{code: c, data: data, cause: &withMessage{msg: message, cause: err}, stack: callers(4), noHooks: skip}
+}
+
+// contextCode classifies errors caused by context cancellation, returning
+// CodeUnknown for all other errors
+func contextCode(err error) Code {
+	for ; err != nil; err = causeOf(err) {
+		if goerrors.Is(err, context.DeadlineExceeded) {
+			return CodeTimeout
+		}
+		if goerrors.Is(err, context.Canceled) {
+			return CodeCancelled
+		}
+	}
+	return CodeUnknown
+}
+
+// withMessage annotates an error with a message
+type withMessage struct {
+	msg   string
+	cause error
+}
+
+// Error prefixes the wrapped error string with the message
+func (w *withMessage) Error() string {
+	return w.msg + ": " + w.cause.Error()
+}
+
+// Cause returns the wrapped error
+func (w *withMessage) Cause() error {
+	return w.cause
+}
+
+// Unwrap returns the wrapped error
+func (w *withMessage) Unwrap() error {
+	return w.cause
+}
+
+// Is reports whether any error e wraps matches target, so errors.Is can find
+// low-level sentinel errors like io.EOF beneath any number of wraps. Is walks
+// Cause as well as Unwrap methods, matching errors wrapped by packages that
+// predate the standard library's Unwrap convention
+func (e Error) Is(target error) bool {
+	for err := e.cause; err != nil; err = causeOf(err) {
+		if goerrors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error e wraps that matches target, for use with
+// errors.As. Like Is, As walks both Cause and Unwrap methods
+func (e Error) As(target interface{}) bool {
+	for err := e.cause; err != nil; err = causeOf(err) {
+		if goerrors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// causeOf returns the result of calling err's Cause method, if it has one
+func causeOf(err error) error {
+	if c, ok := err.(interface{ Cause() error }); ok {
+		return c.Cause()
+	}
+	return nil
+}
+
+// WrapAll returns an error annotating a group of errors with a stack trace at
+// the point WrapAll is called, and the supplied message. nil errors are
+// dropped. If all errors are nil, WrapAll returns nil
+func WrapAll(c Code, errs []error, message string, data ...interface{}) *Error {
+	j := joinError{}
+	for _, err := range errs {
+		if err != nil {
+			j.errs = append(j.errs, err)
+		}
+	}
+	if len(j.errs) == 0 {
+		return nil
+	}
+	return hooked(wrapError(c, j, message, data))
+}
+
+// joinError holds a group of errors as a single error
+type joinError struct {
+	errs []error
+}
+
+// Error joins all error strings with semicolons
+func (j joinError) Error() string {
+	strs := make([]string, len(j.errs))
+	for i, err := range j.errs {
+		strs[i] = err.Error()
+	}
+	return strings.Join(strs, "; ")
+}
+
+// Unwrap returns all joined errors
+func (j joinError) Unwrap() []error {
+	return j.errs
 }
 
 // WrapFriendly calls wrap and adds a friendly, user-facing message describing the problem
 func WrapFriendly(c Code, err error, message, friendly string, data ...interface{}) *Error {
-	e := Wrap(c, err, message, data...)
+	if err == nil {
+		return nil
+	}
+	e := wrapError(c, err, message, data)
 	e.friendly = friendly
-	return e
+	return hooked(e)
 }
 
 // WrapFriendlyFix calls wrap and adds a friendly, a user-facing message describing the problem
 func WrapFriendlyFix(c Code, err error, message, friendly, fix string, data ...interface{}) *Error {
-	e := WrapFriendly(c, err, message, friendly, data...)
+	if err == nil {
+		return nil
+	}
+	e := wrapError(c, err, message, data)
+	e.friendly = friendly
 	e.fix = fix
-	return e
+	return hooked(e)
 }
 
-// Cause proxies the pkg/errors Cause function
+// Cause returns the underlying cause of an error by calling Cause methods until
+// reaching an error that doesn't implement Cause. It's a drop-in replacement
+// for the pkg/errors Cause function
 func Cause(err error) error {
-	return errors.Cause(err)
+	for err != nil {
+		cause := causeOf(err)
+		if cause == nil {
+			break
+		}
+		err = cause
+	}
+	return err
+}
+
+// AsError finds the nearest *Error in err's chain. Use AsError instead of a
+// type assertion, which fails when another package wraps an *Error
+func AsError(err error) (*Error, bool) {
+	for ; err != nil; err = causeOf(err) {
+		var e *Error
+		if goerrors.As(err, &e) {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+// IsCode reports whether any Error in err's chain has code c. Deprecated
+// aliases match their replacement
+func IsCode(err error, c Code) bool {
+	return MatchAnyCode(err, c)
+}
+
+// MatchAnyCode reports whether any Error in err's chain has one of codes.
+// Deprecated aliases match their replacement
+func MatchAnyCode(err error, codes ...Code) bool {
+	return walk(err, func(err error) bool {
+		e, ok := err.(*Error)
+		if !ok {
+			return false
+		}
+		reg := e.Registry()
+		for _, c := range codes {
+			if reg.Canonical(e.code) == reg.Canonical(c) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// ResolveCode picks a single representative code for err. Errors that wrap
+// many errors, like validation batches or parallel fetches, can hold many
+// codes. ResolveCode ranks every code in err's chain by the precedence of the
+// outermost Error's registry, returning the highest ranked code. Ties go to the
+// outermost error. Errors without any codes resolve to CodeUnknown
+func ResolveCode(err error) Code {
+	outer, ok := AsError(err)
+	if !ok {
+		return CodeUnknown
+	}
+	reg := outer.Registry()
+
+	code, best := CodeUnknown, -1
+	walk(err, func(err error) bool {
+		if e, ok := err.(*Error); ok && e.code != CodeUnknown {
+			if rank := reg.rank(e.code); best == -1 || rank < best {
+				code, best = e.code, rank
+			}
+		}
+		return false
+	})
+	return code
+}
+
+// walk calls fn for err and every error it wraps, depth first, stopping when
+// fn returns true. walk follows Unwrap() []error, Unwrap() error, and Cause()
+// methods, in that order of preference
+func walk(err error, fn func(error) bool) bool {
+	if err == nil {
+		return false
+	}
+	if fn(err) {
+		return true
+	}
+	switch x := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, child := range x.Unwrap() {
+			if walk(child, fn) {
+				return true
+			}
+		}
+		return false
+	case interface{ Unwrap() error }:
+		return walk(x.Unwrap(), fn)
+	default:
+		return walk(causeOf(err), fn)
+	}
+}
+
+// IsInCategory reports whether err is an Error with a code that is parent, or
+// descends from parent. Handlers can use categories to match broad classes of
+// error while errors still record precise codes
+func IsInCategory(err error, parent Code) bool {
+	e, ok := AsError(err)
+	if !ok {
+		return false
+	}
+	return e.Registry().IsInCategory(e.code, parent)
+}
+
+// ExitCode returns the process exit status for err, using the code of the
+// first Error in err's chain. nil errors exit 0, errors without a code exit 1
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	e, ok := AsError(err)
+	if !ok {
+		return 1
+	}
+	return e.Registry().CodeExitStatus(e.code)
 }
@@ -1,11 +1,16 @@
 package errors
 
 import (
+	"context"
+	goerrors "errors"
 	"fmt"
+	"io"
+	"net"
+	"os"
 	"testing"
 )
 
-func ExampleNewWithMessage() {
+func ExampleWrapFriendly() {
 	// an example function that returns an error
 	errFunc := func(id int) error {
 		return fmt.Errorf("not found")
@@ -21,7 +26,7 @@ func ExampleNewWithMessage() {
 	// qri error to get access to special error methods
 	// the else condition won't happen in this example, but we have to handle
 	// normal errors in the real world
-	if qriErr, ok := err.(*Error); ok {
+	if qriErr, ok := AsError(err); ok {
 		fmt.Println(qriErr.Friendly())
 	} else {
 		fmt.Println(err.Error())
@@ -103,3 +108,283 @@ func TestCodeVals(t *testing.T) {
 		t.Errorf("http code mismatch. expected: %d, got: %d", expectHTTP, gotHTTP)
 	}
 }
+
+func TestDefaultMessages(t *testing.T) {
+	e := New(CodeUnauthorized, "no token")
+	expect := "auth: you need to be logged in to do that please log in and try again"
+	if e.Friendly() != expect {
+		t.Errorf("friendly mismatch. expected: %s, got: %s", expect, e.Friendly())
+	}
+
+	e = NewFriendlyFix(CodeUnauthorized, "no token", "your session expired", "log in again")
+	expect = "auth: your session expired log in again"
+	if e.Friendly() != expect {
+		t.Errorf("friendly mismatch. expected: %s, got: %s", expect, e.Friendly())
+	}
+
+	r := NewRegistry()
+	CodeQuota := Code(203)
+	r.RegisterSpec(CodeQuota, CodeSpec{HTTPStatus: 402, Name: "quota", Friendly: "you've used up your storage quota"})
+	if err := r.SetDefaultMessages(CodeQuota, "you're out of space", "delete some datasets"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SetDefaultMessages(Code(-1), "", ""); err == nil {
+		t.Errorf("expected setting messages for an unregistered code to error")
+	}
+
+	e = New(CodeQuota, "quota exceeded").WithRegistry(r)
+	expect = "quota: you're out of space delete some datasets"
+	if e.Friendly() != expect {
+		t.Errorf("friendly mismatch. expected: %s, got: %s", expect, e.Friendly())
+	}
+}
+
+func TestIsInCategory(t *testing.T) {
+	r := NewRegistry()
+	CodeTokenExpired := Code(100)
+	CodeTokenRevoked := Code(101)
+	r.RegisterSpec(CodeTokenExpired, CodeSpec{HTTPStatus: 401, Name: "token expired", Parent: CodeUnauthorized})
+	r.RegisterSpec(CodeTokenRevoked, CodeSpec{HTTPStatus: 401, Name: "token revoked", Parent: CodeTokenExpired})
+
+	err := New(CodeTokenRevoked, "token revoked").WithRegistry(r)
+	if !IsInCategory(err, CodeUnauthorized) {
+		t.Errorf("expected revoked token error to be in the unauthorized category")
+	}
+	if !IsInCategory(err, CodeTokenRevoked) {
+		t.Errorf("expected error to be in it's own category")
+	}
+	if IsInCategory(err, CodeForbidden) {
+		t.Errorf("expected error not to be in the forbidden category")
+	}
+	if IsInCategory(fmt.Errorf("plain"), CodeUnauthorized) {
+		t.Errorf("expected plain errors not to be in any category")
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterSpec(Code(100), CodeSpec{HTTPStatus: 504, Name: "database", ExitStatus: 69})
+	r.Register(Code(101), 500, "other")
+
+	cases := []struct {
+		err    error
+		expect int
+	}{
+		{nil, 0},
+		{fmt.Errorf("plain"), 1},
+		{New(CodeNotFound, "missing"), 4},
+		{New(CodeInvalidArgs, "bad"), 3},
+		{New(Code(100), "no db").WithRegistry(r), 69},
+		{New(Code(101), "other").WithRegistry(r), 1},
+	}
+
+	for i, c := range cases {
+		if got := ExitCode(c.err); got != c.expect {
+			t.Errorf("case %d exit code mismatch. expected: %d, got: %d", i, c.expect, got)
+		}
+	}
+}
+
+func TestWrapAll(t *testing.T) {
+	e := WrapAll(CodeUnavailable, []error{io.EOF, nil, os.ErrNotExist}, "fetching peers")
+	expect := "unavailable: fetching peers: EOF; file does not exist"
+	if e.Error() != expect {
+		t.Errorf("error mismatch. expected: %s, got: %s", expect, e.Error())
+	}
+	if !goerrors.Is(e, io.EOF) || !goerrors.Is(e, os.ErrNotExist) {
+		t.Errorf("expected errors.Is to find every joined error")
+	}
+	if goerrors.Is(e, io.ErrUnexpectedEOF) {
+		t.Errorf("expected errors.Is not to match an error that wasn't joined")
+	}
+	if WrapAll(CodeGeneric, []error{nil}, "nothing") != nil {
+		t.Errorf("expected wrapping only nil errors to return nil")
+	}
+
+	joined := goerrors.Join(io.EOF, os.ErrClosed)
+	if !goerrors.Is(Wrap(CodeGeneric, joined, "closing"), os.ErrClosed) {
+		t.Errorf("expected errors.Is to find members of a wrapped errors.Join error")
+	}
+}
+
+func TestWrapNil(t *testing.T) {
+	called := 0
+	remove := RegisterHook(func(*Error) { called++ })
+	defer remove()
+
+	wrapped := map[string]*Error{
+		"Wrap":            Wrap(CodeGeneric, nil, "loading"),
+		"WrapFriendly":    WrapFriendly(CodeGeneric, nil, "loading", "couldn't load"),
+		"WrapFriendlyFix": WrapFriendlyFix(CodeGeneric, nil, "loading", "couldn't load", "try again"),
+		"WrapCtx":         WrapCtx(context.Background(), CodeGeneric, nil, "loading"),
+	}
+	for name, e := range wrapped {
+		if e != nil {
+			t.Errorf("expected %s of a nil error to return nil. got: %#v", name, e)
+		}
+	}
+	if called != 0 {
+		t.Errorf("expected wrapping nil errors not to call hooks. got: %d calls", called)
+	}
+}
+
+// causeOnly wraps an error exposing only a Cause method, like errors from
+// older versions of github.com/pkg/errors
+type causeOnly struct{ cause error }
+
+func (c causeOnly) Error() string { return "wrapped: " + c.cause.Error() }
+func (c causeOnly) Cause() error  { return c.cause }
+
+func TestIsAs(t *testing.T) {
+	pathErr := &os.PathError{Op: "open", Path: "/nope", Err: os.ErrNotExist}
+	e := Wrap(CodeNotFound, Wrap(CodeGeneric, causeOnly{pathErr}, "loading"), "reading config")
+
+	if !goerrors.Is(e, os.ErrNotExist) {
+		t.Errorf("expected errors.Is to find a sentinel beneath multiple wraps")
+	}
+	if goerrors.Is(e, io.EOF) {
+		t.Errorf("expected errors.Is not to match an unrelated sentinel")
+	}
+
+	var got *os.PathError
+	if !goerrors.As(e, &got) {
+		t.Fatal("expected errors.As to find *os.PathError")
+	}
+	if got.Path != "/nope" {
+		t.Errorf("path mismatch. expected: %s, got: %s", "/nope", got.Path)
+	}
+}
+
+func TestAsError(t *testing.T) {
+	e := New(CodeNotFound, "missing")
+	cases := []struct {
+		err    error
+		expect *Error
+	}{
+		{nil, nil},
+		{io.EOF, nil},
+		{e, e},
+		{fmt.Errorf("loading: %w", e), e},
+		{causeOnly{fmt.Errorf("loading: %w", e)}, e},
+	}
+
+	for i, c := range cases {
+		got, ok := AsError(c.err)
+		if ok != (c.expect != nil) || got != c.expect {
+			t.Errorf("case %d mismatch. expected: %v, got: %v", i, c.expect, got)
+		}
+	}
+}
+
+func TestNetError(t *testing.T) {
+	var _ net.Error = New(CodeTimeout, "")
+
+	cases := []struct {
+		err       *Error
+		timeout   bool
+		temporary bool
+	}{
+		{New(CodeTimeout, "too slow"), true, true},
+		{New(CodeUnavailable, "down"), false, true},
+		{New(CodeNotFound, "missing"), false, false},
+		{New(CodeNotFound, "missing").WithTemporary(true), false, true},
+		{New(CodeTimeout, "too slow").WithTimeout(false).WithTemporary(false), false, false},
+	}
+
+	for i, c := range cases {
+		if c.err.Timeout() != c.timeout {
+			t.Errorf("case %d timeout mismatch. expected: %t, got: %t", i, c.timeout, c.err.Timeout())
+		}
+		if c.err.Temporary() != c.temporary {
+			t.Errorf("case %d temporary mismatch. expected: %t, got: %t", i, c.temporary, c.err.Temporary())
+		}
+	}
+}
+
+func TestWrapContextErrors(t *testing.T) {
+	cases := []struct {
+		code   Code
+		err    error
+		expect Code
+	}{
+		{CodeUnknown, context.Canceled, CodeCancelled},
+		{CodeUnknown, context.DeadlineExceeded, CodeTimeout},
+		{CodeUnknown, fmt.Errorf("fetching: %w", context.DeadlineExceeded), CodeTimeout},
+		{CodeUnknown, causeOnly{context.Canceled}, CodeCancelled},
+		{CodeUnavailable, context.DeadlineExceeded, CodeUnavailable},
+		{CodeUnknown, io.EOF, CodeUnknown},
+	}
+
+	for i, c := range cases {
+		if got := Wrap(c.code, c.err, "wrapped").Code(); got != c.expect {
+			t.Errorf("case %d code mismatch. expected: %d, got: %d", i, c.expect, got)
+		}
+	}
+}
+
+func TestIsCode(t *testing.T) {
+	notFound := New(CodeNotFound, "missing")
+	err := fmt.Errorf("loading: %w", Wrap(CodeUnavailable, causeOnly{notFound}, "fetching"))
+
+	if !IsCode(err, CodeUnavailable) || !IsCode(err, CodeNotFound) {
+		t.Errorf("expected IsCode to match codes anywhere in the chain")
+	}
+	if IsCode(err, CodeForbidden) {
+		t.Errorf("expected IsCode not to match a missing code")
+	}
+	if !MatchAnyCode(err, CodeForbidden, CodeNotFound) {
+		t.Errorf("expected MatchAnyCode to match one of many codes")
+	}
+	if MatchAnyCode(err) || MatchAnyCode(nil, CodeNotFound) || IsCode(io.EOF, CodeUnknown) {
+		t.Errorf("expected no match")
+	}
+
+	joined := WrapAll(CodeGeneric, []error{io.EOF, New(CodeTimeout, "slow")}, "many")
+	if !IsCode(joined, CodeTimeout) {
+		t.Errorf("expected IsCode to match members of joined errors")
+	}
+
+	r := NewRegistry()
+	r.Register(Code(110), 409, "conflict")
+	r.RegisterAlias(Code(100), Code(110))
+	if !IsCode(New(Code(100), "old").WithRegistry(r), Code(110)) {
+		t.Errorf("expected deprecated codes to match their replacement")
+	}
+}
+
+func TestResolveCode(t *testing.T) {
+	batch := WrapAll(CodeGeneric, []error{
+		New(CodeNotFound, "no dataset"),
+		New(CodeUnavailable, "peer down"),
+		New(CodeUnauthorized, "no token"),
+	}, "fetching")
+	if got := ResolveCode(batch); got != CodeUnauthorized {
+		t.Errorf("code mismatch. expected: %d, got: %d", CodeUnauthorized, got)
+	}
+
+	if got := ResolveCode(New(CodeTimeout, "slow")); got != CodeTimeout {
+		t.Errorf("code mismatch. expected: %d, got: %d", CodeTimeout, got)
+	}
+	if got := ResolveCode(io.EOF); got != CodeUnknown {
+		t.Errorf("code mismatch. expected: %d, got: %d", CodeUnknown, got)
+	}
+
+	r := NewRegistry()
+	CodeTokenExpired := Code(100)
+	CodeDiskFull := Code(101)
+	r.RegisterSpec(CodeTokenExpired, CodeSpec{HTTPStatus: 401, Name: "expired", Parent: CodeUnauthorized})
+	r.Register(CodeDiskFull, 507, "disk")
+	r.SetPrecedence(CodeNotFound, CodeUnauthorized)
+	batch = WrapAll(CodeGeneric, []error{
+		New(CodeDiskFull, "full"),
+		New(CodeTokenExpired, "expired"),
+		New(CodeNotFound, "no dataset"),
+	}, "fetching").WithRegistry(r)
+	if got := ResolveCode(batch); got != CodeNotFound {
+		t.Errorf("code mismatch. expected: %d, got: %d", CodeNotFound, got)
+	}
+	r.SetPrecedence(CodeUnauthorized, CodeNotFound)
+	if got := ResolveCode(batch); got != CodeTokenExpired {
+		t.Errorf("expected sub-code to rank with it's parent. got: %d", got)
+	}
+}
@@ -0,0 +1,107 @@
+package errorsbugsnag
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bugsnag/bugsnag-go"
+	bugsnagerrors "github.com/bugsnag/bugsnag-go/errors"
+	"github.com/qri-io/errors"
+)
+
+// Reporter is an errors.Reporter notifying Bugsnag. Errors are sent with the
+// stack captured when they were created, the slug of their code as error
+// class, and a severity from the severity of the code. The code, slug, op &
+// fingerprint are added to an "error" metadata tab, and details to a
+// "details" tab
+type Reporter struct {
+	// Notifier sends notifications. nil uses bugsnag's default notifier
+	Notifier *bugsnag.Notifier
+}
+
+// Report implements the errors.Reporter interface
+func (r Reporter) Report(ctx context.Context, e *errors.Error) {
+	err, rawData := Notification(ctx, e)
+	if r.Notifier != nil {
+		r.Notifier.Notify(err, rawData...)
+		return
+	}
+	bugsnag.Notify(err, rawData...)
+}
+
+// Notification returns the error & raw data to notify bugsnag of an error
+// with
+func Notification(ctx context.Context, e *errors.Error) (error, []interface{}) {
+	reg := e.Registry()
+	code := reg.Canonical(errors.ResolveCode(e))
+	slug := reg.CodeSlug(code)
+
+	meta := bugsnag.MetaData{}
+	meta.Add("error", "code", int(code))
+	meta.Add("error", "slug", slug)
+	meta.Add("error", "fingerprint", errors.Fingerprint(e))
+	if op := errors.OriginOf(e).Op; op != "" {
+		meta.Add("error", "op", op)
+	}
+	if friendly := e.FriendlyMessage(); friendly != "" {
+		meta.Add("error", "friendly", friendly)
+	}
+	for key, val := range e.Details() {
+		meta.Add("details", key, val)
+	}
+
+	rawData := []interface{}{severity(reg.CodeSeverity(code)), bugsnag.ErrorClass{Name: slug}, meta}
+	if ctx != nil {
+		rawData = append(rawData, ctx)
+	}
+	return stackError{e}, rawData
+}
+
+// severity converts a severity to a bugsnag severity
+func severity(s errors.Severity) interface{} {
+	switch s {
+	case errors.SeverityDebug, errors.SeverityInfo:
+		return bugsnag.SeverityInfo
+	case errors.SeverityWarning:
+		return bugsnag.SeverityWarning
+	default:
+		return bugsnag.SeverityError
+	}
+}
+
+// stackError gives bugsnag the stack captured when an error was created
+type stackError struct {
+	e *errors.Error
+}
+
+// Error implements the error interface
+func (se stackError) Error() string {
+	return se.e.Error()
+}
+
+// Unwrap returns the wrapped error
+func (se stackError) Unwrap() error {
+	return se.e
+}
+
+// StackFrames implements bugsnag's ErrorWithStackFrames interface
+func (se stackError) StackFrames() []bugsnagerrors.StackFrame {
+	frames := se.e.StackTrace()
+	stack := make([]bugsnagerrors.StackFrame, len(frames))
+	for i, f := range frames {
+		pkg, name := splitFunction(f.Function)
+		stack[i] = bugsnagerrors.StackFrame{File: f.File, LineNumber: f.Line, Name: name, Package: pkg}
+	}
+	return stack
+}
+
+// splitFunction splits a qualified function name into it's package path and
+// name, eg: "github.com/qri-io/dsfs.(*Loader).Load" splits into
+// "github.com/qri-io/dsfs" and "(*Loader).Load"
+func splitFunction(fn string) (pkg, name string) {
+	slash := strings.LastIndex(fn, "/") + 1
+	if dot := strings.Index(fn[slash:], "."); dot >= 0 {
+		return fn[:slash+dot], fn[slash+dot+1:]
+	}
+	return "", fn
+}
@@ -0,0 +1,65 @@
+package errorsbugsnag
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bugsnag/bugsnag-go"
+	bugsnagerrors "github.com/bugsnag/bugsnag-go/errors"
+	"github.com/qri-io/errors"
+)
+
+func TestNotification(t *testing.T) {
+	e := errors.New(errors.CodeTimeout, "peer took too long").
+		WithDetail(errors.OpDetail, "p2p.Fetch").
+		WithDetail("peer", "QmPeer")
+	err, rawData := Notification(context.Background(), e)
+
+	frames := bugsnagerrors.New(err, 0).StackFrames()
+	if len(frames) == 0 || frames[0].Name != "TestNotification" || !strings.HasSuffix(frames[0].Package, "errorsbugsnag") {
+		t.Errorf("expected stack to start where the error was created. got: %v", frames)
+	}
+
+	var (
+		class bugsnag.ErrorClass
+		meta  bugsnag.MetaData
+		sev   interface{}
+	)
+	for _, d := range rawData {
+		switch x := d.(type) {
+		case bugsnag.ErrorClass:
+			class = x
+		case bugsnag.MetaData:
+			meta = x
+		case context.Context:
+		default:
+			sev = x
+		}
+	}
+	if class.Name != "timeout" {
+		t.Errorf("error class mismatch. expected: %s, got: %s", "timeout", class.Name)
+	}
+	if sev != bugsnag.SeverityError {
+		t.Errorf("severity mismatch. expected: %v, got: %v", bugsnag.SeverityError, sev)
+	}
+	if meta["error"]["op"] != "p2p.Fetch" || meta["details"]["peer"] != "QmPeer" {
+		t.Errorf("metadata mismatch. got: %v", meta)
+	}
+}
+
+func TestSplitFunction(t *testing.T) {
+	cases := []struct {
+		fn, pkg, name string
+	}{
+		{"github.com/qri-io/dsfs.(*Loader).Load", "github.com/qri-io/dsfs", "(*Loader).Load"},
+		{"main.main", "main", "main"},
+		{"anonymous", "", "anonymous"},
+	}
+	for _, c := range cases {
+		pkg, name := splitFunction(c.fn)
+		if pkg != c.pkg || name != c.name {
+			t.Errorf("split mismatch for %s. expected: %s %s, got: %s %s", c.fn, c.pkg, c.name, pkg, name)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+// Package errorsbugsnag reports github.com/qri-io/errors values to Bugsnag
+package errorsbugsnag
@@ -0,0 +1,18 @@
+module github.com/qri-io/errors/errorsbugsnag
+
+go 1.21
+
+require (
+	github.com/bugsnag/bugsnag-go v1.5.3
+	github.com/qri-io/errors v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/bugsnag/panicwrap v1.3.4 // indirect
+	github.com/gofrs/uuid v4.4.0+incompatible // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace (
+	github.com/qri-io/errors => ../
+)
@@ -0,0 +1,43 @@
+package errorscbor
+
+import (
+	"reflect"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/qri-io/errors"
+)
+
+// ContentType is the media type of CBOR errors
+const ContentType = "application/cbor"
+
+var (
+	// encMode sorts map keys, so an error always encodes to the same bytes
+	encMode, _ = cbor.EncOptions{Sort: cbor.SortCanonical}.EncMode()
+	// decMode decodes nested maps as map[string]interface{}, matching the
+	// types encoding/json produces
+	decMode, _ = cbor.DecOptions{
+		DefaultMapType: reflect.TypeOf(map[string]interface{}{}),
+	}.DecMode()
+)
+
+// Marshal writes an error as CBOR, with the same fields as MarshalJSON. The
+// serialized error is encoded directly, so data & detail values keep their
+// CBOR types
+func Marshal(e *errors.Error) ([]byte, error) {
+	return encMode.Marshal(e.WireValue())
+}
+
+// Unmarshal reads an error written by Marshal
+func Unmarshal(data []byte) (*errors.Error, error) {
+	e := &errors.Error{}
+	return e, e.DecodeWire(func(v interface{}) error {
+		return decMode.Unmarshal(data, v)
+	})
+}
+
+func init() {
+	errors.RegisterSerializer(ContentType, errors.SerializerFuncs{
+		MarshalFunc:   Marshal,
+		UnmarshalFunc: Unmarshal,
+	})
+}
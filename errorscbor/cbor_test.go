@@ -0,0 +1,50 @@
+package errorscbor
+
+import (
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/qri-io/errors"
+)
+
+func TestErrorCBOR(t *testing.T) {
+	e := errors.NewFriendlyFix(errors.CodeUnavailable, "peer unreachable", "couldn't reach peer", "try again", "QmPeer").
+		WithDetail("attempts", map[string]interface{}{"count": 3}).
+		WithDetail("key", []byte{0xde, 0xad})
+
+	envelope := struct {
+		ID  int
+		Err *errors.Error
+	}{}
+	data, err := cbor.Marshal(struct {
+		ID  int
+		Err *errors.Error
+	}{ID: 1, Err: e})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cbor.Unmarshal(data, &envelope); err != nil {
+		t.Fatal(err)
+	}
+
+	got := envelope.Err
+	if got.Code() != errors.CodeUnavailable || got.Error() != e.Error() || got.Friendly() != e.Friendly() {
+		t.Errorf("round trip mismatch. expected: %s, got: %s", e.Friendly(), got.Friendly())
+	}
+	attempts, ok := got.Details()["attempts"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected nested details to decode as a string map. got: %T", got.Details()["attempts"])
+	}
+	if attempts["count"] != uint64(3) {
+		t.Errorf("detail mismatch. expected: uint64(3), got: %T(%v)", attempts["count"], attempts["count"])
+	}
+	if key, ok := got.Details()["key"].([]byte); !ok || string(key) != "\xde\xad" {
+		t.Errorf("expected byte strings to round trip without a JSON detour. got: %#v", got.Details()["key"])
+	}
+
+	a, _ := Marshal(e)
+	b, _ := Marshal(e)
+	if string(a) != string(b) {
+		t.Errorf("expected encoding to be deterministic")
+	}
+}
@@ -0,0 +1,8 @@
+// Package errorscbor adds CBOR support to github.com/qri-io/errors, keeping
+// github.com/fxamacker/cbor out of the errors package's dependencies.
+// Importing it registers an "application/cbor" serializer, which
+// Error.MarshalCBOR & Error.UnmarshalCBOR use to embed errors in CBOR
+// envelopes:
+//
+//	import _ "github.com/qri-io/errors/errorscbor"
+package errorscbor
@@ -0,0 +1,17 @@
+module github.com/qri-io/errors/errorscbor
+
+go 1.21
+
+require (
+	github.com/fxamacker/cbor/v2 v2.9.1
+	github.com/qri-io/errors v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/x448/float16 v0.8.4 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace (
+	github.com/qri-io/errors => ../
+)
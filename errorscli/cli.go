@@ -0,0 +1,107 @@
+package errorscli
+
+import (
+	"io"
+	"os"
+
+	"github.com/qri-io/errors"
+	"github.com/qri-io/errors/errorsterm"
+	"github.com/urfave/cli/v2"
+)
+
+// VerboseFlag is a flag ExitErrHandler reads to set the verbosity errors are
+// printed at. Repeating the flag increases verbosity, eg: -vv, which requires
+// the app to set UseShortOptionHandling
+var VerboseFlag = &cli.BoolFlag{
+	Name:    "verbose",
+	Aliases: []string{"v"},
+	Usage:   "print error details, repeat for more",
+}
+
+// exitError pairs an error with the exit status errors.ExitCode maps it to
+type exitError struct {
+	err error
+}
+
+// Error returns the wrapped error's message
+func (e exitError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e exitError) Unwrap() error {
+	return e.err
+}
+
+// ExitCode implements the cli.ExitCoder interface
+func (e exitError) ExitCode() int {
+	return errors.ExitCode(e.err)
+}
+
+// ExitCoder wraps an error as a cli.ExitCoder, so the app exits with the
+// status errors.ExitCode maps err to. nil errors return nil
+func ExitCoder(err error) cli.ExitCoder {
+	if err == nil {
+		return nil
+	}
+	if ec, ok := err.(cli.ExitCoder); ok {
+		return ec
+	}
+	return exitError{err: err}
+}
+
+// Action wraps an action, converting returned errors with ExitCoder
+func Action(fn cli.ActionFunc) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		if err := fn(c); err != nil {
+			return ExitCoder(err)
+		}
+		return nil
+	}
+}
+
+// ExitErrHandler is a cli.ExitErrHandlerFunc that prints errors with an
+// errorsterm.Renderer to the app's error writer, then exits with the status
+// errors.ExitCode maps the error to. Verbosity is the number of times
+// VerboseFlag is set
+func ExitErrHandler(c *cli.Context, err error) {
+	if err == nil {
+		return
+	}
+	var w io.Writer = os.Stderr
+	if c != nil && c.App != nil && c.App.ErrWriter != nil {
+		w = c.App.ErrWriter
+	}
+	r := errorsterm.NewRenderer(w)
+	if c != nil {
+		r.Verbosity = c.Count(VerboseFlag.Name)
+	}
+	r.Print(err)
+	cli.OsExiter(errors.ExitCode(err))
+}
+
+// Apply sets ExitErrHandler as the app's error handler, converts usage
+// errors with errors.FlagError, and wraps the actions of the app and every
+// command with Action
+func Apply(app *cli.App) {
+	app.ExitErrHandler = ExitErrHandler
+	if app.OnUsageError == nil {
+		app.OnUsageError = func(c *cli.Context, err error, isSubcommand bool) error {
+			return ExitCoder(errors.FlagError(err))
+		}
+	}
+	if app.Action != nil {
+		app.Action = Action(app.Action)
+	}
+	applyCommands(app.Commands)
+}
+
+// applyCommands wraps the actions of a list of commands & their subcommands
+func applyCommands(cmds []*cli.Command) {
+	for _, cmd := range cmds {
+		if cmd.Action != nil {
+			cmd.Action = Action(cmd.Action)
+		}
+		applyCommands(cmd.Subcommands)
+	}
+}
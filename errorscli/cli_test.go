@@ -0,0 +1,111 @@
+package errorscli
+
+import (
+	"bytes"
+	goerrors "errors"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/errors"
+	"github.com/urfave/cli/v2"
+)
+
+func TestApply(t *testing.T) {
+	status := -1
+	prevExiter := cli.OsExiter
+	cli.OsExiter = func(code int) { status = code }
+	defer func() { cli.OsExiter = prevExiter }()
+
+	buf := &bytes.Buffer{}
+	app := &cli.App{
+		Name:      "qri",
+		ErrWriter: buf,
+		Commands: []*cli.Command{{
+			Name: "dataset",
+			Subcommands: []*cli.Command{{
+				Name: "get",
+				Action: func(c *cli.Context) error {
+					return errors.NewFriendly(errors.CodeNotFound, "no dataset", "couldn't find that dataset")
+				},
+			}},
+		}},
+	}
+	Apply(app)
+
+	err := app.Run([]string{"qri", "dataset", "get"})
+	if status != 4 {
+		t.Errorf("status mismatch. expected: %d, got: %d", 4, status)
+	}
+	if expect := "Error: couldn't find that dataset\n"; buf.String() != expect {
+		t.Errorf("output mismatch. expected: %q, got: %q", expect, buf.String())
+	}
+	if ec, ok := err.(cli.ExitCoder); !ok || ec.ExitCode() != 4 {
+		t.Errorf("expected an exit coder with status 4. got: %v", err)
+	}
+	if errors.ResolveCode(err) != errors.CodeNotFound {
+		t.Errorf("expected the returned error to wrap the action's error. got: %v", err)
+	}
+}
+
+func TestExitCoder(t *testing.T) {
+	if ExitCoder(nil) != nil {
+		t.Errorf("expected nil errors to return nil")
+	}
+	if got := ExitCoder(goerrors.New("oh no")).ExitCode(); got != 1 {
+		t.Errorf("status mismatch. expected: %d, got: %d", 1, got)
+	}
+	ec := cli.Exit("usage", 2)
+	if got := ExitCoder(ec); got != ec {
+		t.Errorf("expected exit coders to be returned as is")
+	}
+}
+
+func TestApplyUsageError(t *testing.T) {
+	status := -1
+	prevExiter := cli.OsExiter
+	cli.OsExiter = func(code int) { status = code }
+	defer func() { cli.OsExiter = prevExiter }()
+
+	buf := &bytes.Buffer{}
+	app := &cli.App{
+		Name:      "qri",
+		Writer:    buf,
+		ErrWriter: buf,
+		Action:    func(c *cli.Context) error { return nil },
+	}
+	Apply(app)
+
+	err := app.Run([]string{"qri", "--nope"})
+	if errors.ResolveCode(err) != errors.CodeUsage {
+		t.Errorf("expected usage errors to be converted. got: %v", err)
+	}
+	if status != 2 {
+		t.Errorf("status mismatch. expected: %d, got: %d", 2, status)
+	}
+}
+
+func TestExitErrHandlerVerbosity(t *testing.T) {
+	prevExiter := cli.OsExiter
+	cli.OsExiter = func(code int) {}
+	defer func() { cli.OsExiter = prevExiter }()
+
+	buf := &bytes.Buffer{}
+	app := &cli.App{
+		Name:                   "qri",
+		ErrWriter:              buf,
+		UseShortOptionHandling: true,
+		Flags:                  []cli.Flag{VerboseFlag},
+		Commands: []*cli.Command{{
+			Name: "get",
+			Action: func(c *cli.Context) error {
+				return errors.New(errors.CodeNotFound, "no dataset")
+			},
+		}},
+	}
+	Apply(app)
+
+	app.Run([]string{"qri", "-vv", "get"})
+	if !strings.Contains(buf.String(), "message: missing: no dataset") || !strings.Contains(buf.String(), "stack:") {
+		t.Errorf("expected -vv to print the message & stack. got: %q", buf.String())
+	}
+}
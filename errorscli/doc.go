@@ -0,0 +1,3 @@
+// Package errorscli renders github.com/qri-io/errors from
+// github.com/urfave/cli/v2 apps, and exits with mapped exit statuses
+package errorscli
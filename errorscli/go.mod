@@ -0,0 +1,23 @@
+module github.com/qri-io/errors/errorscli
+
+go 1.21
+
+require (
+	github.com/qri-io/errors v0.0.0-00010101000000-000000000000
+	github.com/qri-io/errors/errorsterm v0.0.0-00010101000000-000000000000
+	github.com/urfave/cli/v2 v2.27.5
+)
+
+require (
+	github.com/cpuguy83/go-md2man/v2 v2.0.5 // indirect
+	github.com/russross/blackfriday/v2 v2.1.0 // indirect
+	github.com/xrash/smetrics v0.0.0-20240521201337-686a1a2994c1 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	golang.org/x/term v0.21.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace (
+	github.com/qri-io/errors => ../
+	github.com/qri-io/errors/errorsterm => ../errorsterm
+)
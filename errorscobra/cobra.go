@@ -0,0 +1,80 @@
+package errorscobra
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/qri-io/errors"
+	"github.com/qri-io/errors/errorsterm"
+	"github.com/spf13/cobra"
+)
+
+// names of the flags HandleError honors
+var (
+	JSONFlag    = "json"
+	VerboseFlag = "verbose"
+)
+
+// AddFlags adds persistent --json and --verbose flags to a command, usually
+// the root command. --verbose can be repeated, eg: -vv
+func AddFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().Bool(JSONFlag, false, "print errors as JSON")
+	cmd.PersistentFlags().CountP(VerboseFlag, "v", "print error details, repeat for more")
+}
+
+// RunE wraps a command function, silencing cobra's own error & usage output
+// so errors are only printed by HandleError. Panics in fn are returned as
+// errors with CodeInternal
+func RunE(fn func(cmd *cobra.Command, args []string) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) (err error) {
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if perr, ok := v.(error); ok {
+				err = errors.Wrap(errors.CodeInternal, perr, "panic")
+			} else {
+				err = errors.New(errors.CodeInternal, fmt.Sprintf("panic: %v", v))
+			}
+		}()
+		return fn(cmd, args)
+	}
+}
+
+// HandleError prints an error returned by executing cmd to cmd's error
+// output, returning the exit status for the error. Errors are printed with an
+// errorsterm.Renderer at the verbosity set by repeating the --verbose flag,
+// or as a line of JSON with errors.RenderJSONLine when the --json flag is
+// set
+func HandleError(cmd *cobra.Command, err error) int {
+	if err == nil {
+		return 0
+	}
+	w := cmd.ErrOrStderr()
+	if on, _ := cmd.Flags().GetBool(JSONFlag); on {
+		errors.RenderJSONLine(w, err)
+		return errors.ExitCode(err)
+	}
+
+	r := errorsterm.NewRenderer(w)
+	r.Verbosity, _ = cmd.Flags().GetCount(VerboseFlag)
+	r.Print(err)
+	return errors.ExitCode(err)
+}
+
+// Execute runs a root command, printing any error with HandleError and
+// exiting with the mapped exit status. Flag parsing errors are converted with
+// errors.FlagError. Execute doesn't return on error
+func Execute(root *cobra.Command) {
+	root.SilenceErrors = true
+	root.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return errors.FlagError(err)
+	})
+	cmd, err := root.ExecuteC()
+	if err != nil {
+		os.Exit(HandleError(cmd, err))
+	}
+}
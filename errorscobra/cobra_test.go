@@ -0,0 +1,86 @@
+package errorscobra
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/errors"
+	"github.com/spf13/cobra"
+)
+
+func newTestCommand(err error) (*cobra.Command, *bytes.Buffer) {
+	root := &cobra.Command{Use: "qri"}
+	AddFlags(root)
+	root.AddCommand(&cobra.Command{
+		Use: "get",
+		RunE: RunE(func(cmd *cobra.Command, args []string) error {
+			if err == nil {
+				panic("oh no")
+			}
+			return err
+		}),
+	})
+	buf := &bytes.Buffer{}
+	root.SetOut(buf)
+	root.SetErr(buf)
+	return root, buf
+}
+
+func TestHandleError(t *testing.T) {
+	notFound := errors.NewFriendly(errors.CodeNotFound, "no dataset", "couldn't find that dataset")
+
+	root, buf := newTestCommand(notFound)
+	root.SetArgs([]string{"get"})
+	cmd, err := root.ExecuteC()
+	if status := HandleError(cmd, err); status != 4 {
+		t.Errorf("status mismatch. expected: %d, got: %d", 4, status)
+	}
+	if expect := "Error: couldn't find that dataset\n"; buf.String() != expect {
+		t.Errorf("output mismatch. expected: %q, got: %q", expect, buf.String())
+	}
+
+	root, buf = newTestCommand(notFound)
+	root.SetArgs([]string{"get", "--json"})
+	cmd, err = root.ExecuteC()
+	HandleError(cmd, err)
+	got := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("expected JSON output. got: %q", buf.String())
+	}
+	if got["slug"] != "not_found" {
+		t.Errorf("slug mismatch. expected: %s, got: %v", "not_found", got["slug"])
+	}
+
+	root, buf = newTestCommand(notFound)
+	root.SetArgs([]string{"get", "-v"})
+	cmd, err = root.ExecuteC()
+	HandleError(cmd, err)
+	if !strings.Contains(buf.String(), "message: missing: no dataset") || strings.Contains(buf.String(), "TestHandleError") {
+		t.Errorf("expected -v output to include the message & not the stack. got: %q", buf.String())
+	}
+
+	root, buf = newTestCommand(notFound)
+	root.SetArgs([]string{"get", "-vv"})
+	cmd, err = root.ExecuteC()
+	HandleError(cmd, err)
+	if !strings.Contains(buf.String(), "TestHandleError") {
+		t.Errorf("expected -vv output to include the stack. got: %q", buf.String())
+	}
+}
+
+func TestRunEPanic(t *testing.T) {
+	root, buf := newTestCommand(nil)
+	root.SetArgs([]string{"get"})
+	cmd, err := root.ExecuteC()
+	if errors.ResolveCode(err) != errors.CodeInternal {
+		t.Errorf("expected panics to be returned as internal errors. got: %v", err)
+	}
+	if status := HandleError(cmd, err); status != errors.ExitCode(err) {
+		t.Errorf("status mismatch. expected: %d, got: %d", errors.ExitCode(err), status)
+	}
+	if !strings.HasPrefix(buf.String(), "Error:") {
+		t.Errorf("expected cobra's own error output to be silenced. got: %q", buf.String())
+	}
+}
@@ -0,0 +1,3 @@
+// Package errorscobra renders github.com/qri-io/errors from
+// github.com/spf13/cobra commands, and exits with mapped exit statuses
+package errorscobra
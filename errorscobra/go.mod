@@ -0,0 +1,22 @@
+module github.com/qri-io/errors/errorscobra
+
+go 1.21
+
+require (
+	github.com/qri-io/errors v0.0.0-00010101000000-000000000000
+	github.com/qri-io/errors/errorsterm v0.0.0-00010101000000-000000000000
+	github.com/spf13/cobra v1.8.1
+)
+
+require (
+	github.com/inconshreveable/mousetrap v1.1.0 // indirect
+	github.com/spf13/pflag v1.0.5 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	golang.org/x/term v0.21.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace (
+	github.com/qri-io/errors => ../
+	github.com/qri-io/errors/errorsterm => ../errorsterm
+)
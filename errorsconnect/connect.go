@@ -0,0 +1,131 @@
+package errorsconnect
+
+import (
+	"context"
+	goerrors "errors"
+
+	"connectrpc.com/connect"
+	"github.com/qri-io/errors"
+	"github.com/qri-io/errors/errorspb"
+	"github.com/qri-io/errors/errorsproto"
+)
+
+// ToConnectError converts an error to a connect error. The connect code comes
+// from the registry's grpc mapping for the code errors.ResolveCode picks, and
+// the message is the machine message. The complete error is attached as an
+// errorspb.Error detail for FromConnectError to read. When
+// errors.DefaultOutputMode is errors.OutputExternal the message is the
+// friendly message, and the attached error is redacted. Errors that aren't an
+// *errors.Error keep any connect error they wrap, and otherwise become
+// unknown errors. nil errors convert to nil
+func ToConnectError(err error) *connect.Error {
+	if err == nil {
+		return nil
+	}
+	e, ok := errors.AsError(err)
+	if !ok {
+		var cerr *connect.Error
+		if goerrors.As(err, &cerr) {
+			return cerr
+		}
+		return connect.NewError(connect.CodeOf(err), err)
+	}
+
+	reg := e.Registry()
+	code := reg.Canonical(errors.ResolveCode(err))
+	msg, full := e.Message(), err
+	if errors.DefaultOutputMode == errors.OutputExternal {
+		e = e.Redact()
+		msg, full = e.FriendlyMessage(), e
+	}
+	cerr := connect.NewError(connect.Code(reg.CodeGRPCStatus(code)), goerrors.New(msg))
+	if pb, perr := errorsproto.ToProto(full); perr == nil {
+		if detail, derr := connect.NewErrorDetail(pb); derr == nil {
+			cerr.AddDetail(detail)
+		}
+	}
+	return cerr
+}
+
+// FromConnectError converts a connect error to an *errors.Error. Errors
+// created by ToConnectError are decoded from their errorspb.Error detail.
+// Other errors become an error with the code for the connect code and the
+// connect message. nil errors convert to nil
+func FromConnectError(cerr *connect.Error) *errors.Error {
+	if cerr == nil {
+		return nil
+	}
+	for _, detail := range cerr.Details() {
+		msg, err := detail.Value()
+		if err != nil {
+			continue
+		}
+		if pb, ok := msg.(*errorspb.Error); ok {
+			return errorsproto.FromProto(pb)
+		}
+	}
+	return errors.New(errors.CodeFromGRPCStatus(uint32(cerr.Code())), cerr.Message())
+}
+
+// Interceptor is a connect interceptor converting errors on both sides of a
+// call. Handlers return *errors.Error values that are sent as connect errors,
+// and clients receive *errors.Error values rebuilt from connect errors
+type Interceptor struct{}
+
+// NewInterceptor creates an Interceptor
+func NewInterceptor() *Interceptor {
+	return &Interceptor{}
+}
+
+// convert applies the conversion for one side of a call
+func convert(isClient bool, err error) error {
+	if err == nil {
+		return nil
+	}
+	if !isClient {
+		return ToConnectError(err)
+	}
+	var cerr *connect.Error
+	if goerrors.As(err, &cerr) {
+		return FromConnectError(cerr)
+	}
+	return err
+}
+
+// WrapUnary implements the connect.Interceptor interface
+func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		resp, err := next(ctx, req)
+		return resp, convert(req.Spec().IsClient, err)
+	}
+}
+
+// WrapStreamingClient implements the connect.Interceptor interface
+func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		return &clientConn{StreamingClientConn: next(ctx, spec)}
+	}
+}
+
+// WrapStreamingHandler implements the connect.Interceptor interface
+func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		return convert(false, next(ctx, conn))
+	}
+}
+
+// clientConn converts errors received by a streaming client
+type clientConn struct {
+	connect.StreamingClientConn
+}
+
+// Receive implements the connect.StreamingClientConn interface. io.EOF is
+// passed through untouched
+func (c *clientConn) Receive(msg interface{}) error {
+	return convert(true, c.StreamingClientConn.Receive(msg))
+}
+
+// CloseResponse implements the connect.StreamingClientConn interface
+func (c *clientConn) CloseResponse() error {
+	return convert(true, c.StreamingClientConn.CloseResponse())
+}
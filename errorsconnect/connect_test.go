@@ -0,0 +1,79 @@
+package errorsconnect
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/qri-io/errors"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestConnectErrorRoundTrip(t *testing.T) {
+	e := errors.NewFriendlyFix(errors.CodeNotFound, "no dataset", "couldn't find that dataset", "check the name").
+		WithDetail("ref", "b5/world_bank")
+	cerr := ToConnectError(e)
+	if cerr.Code() != connect.CodeNotFound {
+		t.Errorf("connect code mismatch. expected: %s, got: %s", connect.CodeNotFound, cerr.Code())
+	}
+	if cerr.Message() != "no dataset" {
+		t.Errorf("message mismatch. expected: %s, got: %s", "no dataset", cerr.Message())
+	}
+	got := FromConnectError(cerr)
+	if got.Code() != errors.CodeNotFound || got.Friendly() != e.Friendly() || got.Details()["ref"] != "b5/world_bank" {
+		t.Errorf("round trip mismatch. expected: %s, got: %s", e.Friendly(), got.Friendly())
+	}
+
+	plain := FromConnectError(connect.NewError(connect.CodePermissionDenied, nil))
+	if plain.Code() != errors.CodeForbidden {
+		t.Errorf("code mismatch. expected: %d, got: %d", errors.CodeForbidden, plain.Code())
+	}
+	if ToConnectError(nil) != nil {
+		t.Errorf("expected nil error to convert to nil")
+	}
+}
+
+func TestInterceptor(t *testing.T) {
+	const procedure = "/qri.Datasets/Get"
+	mux := http.NewServeMux()
+	mux.Handle(procedure, connect.NewUnaryHandler(procedure,
+		func(ctx context.Context, req *connect.Request[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+			return nil, errors.NewFriendly(errors.CodeForbidden, "private dataset", "you can't see that dataset", req.Msg.Value)
+		},
+		connect.WithInterceptors(NewInterceptor()),
+	))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](srv.Client(), srv.URL+procedure,
+		connect.WithInterceptors(NewInterceptor()),
+	)
+	_, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("b5/world_bank")))
+	e, ok := err.(*errors.Error)
+	if !ok {
+		t.Fatalf("expected client to receive an *errors.Error. got: %T %v", err, err)
+	}
+	if e.Code() != errors.CodeForbidden || e.FriendlyMessage() != "you can't see that dataset" {
+		t.Errorf("error mismatch. got: %d %q", e.Code(), e.FriendlyMessage())
+	}
+}
+
+func TestToConnectErrorExternal(t *testing.T) {
+	prev := errors.DefaultOutputMode
+	errors.DefaultOutputMode = errors.OutputExternal
+	defer func() { errors.DefaultOutputMode = prev }()
+
+	e := errors.NewFriendly(errors.CodeNotFound, "no row with id 42", "couldn't find that dataset").
+		WithDetail("table", "datasets")
+
+	cerr := ToConnectError(e)
+	if cerr.Message() != "couldn't find that dataset" {
+		t.Errorf("expected the friendly message. got: %s", cerr.Message())
+	}
+	got := FromConnectError(cerr)
+	if got.Code() != errors.CodeNotFound || got.Message() != "" || len(got.Details()) > 0 {
+		t.Errorf("expected the attached error to be redacted. got: %q %v", got.Message(), got.Details())
+	}
+}
@@ -0,0 +1,4 @@
+// Package errorsconnect converts between github.com/qri-io/errors and
+// connect errors, and provides a connect interceptor that applies the
+// conversion on both sides of a call
+package errorsconnect
@@ -0,0 +1,22 @@
+module github.com/qri-io/errors/errorsconnect
+
+go 1.21
+
+require (
+	connectrpc.com/connect v1.16.1
+	github.com/qri-io/errors v0.0.0-00010101000000-000000000000
+	github.com/qri-io/errors/errorspb v0.0.0-00010101000000-000000000000
+	github.com/qri-io/errors/errorsproto v0.0.0-00010101000000-000000000000
+	google.golang.org/protobuf v1.36.5
+)
+
+require (
+	golang.org/x/net v0.23.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace (
+	github.com/qri-io/errors => ../
+	github.com/qri-io/errors/errorspb => ../errorspb
+	github.com/qri-io/errors/errorsproto => ../errorsproto
+)
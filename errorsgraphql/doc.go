@@ -0,0 +1,5 @@
+// Package errorsgraphql presents github.com/qri-io/errors as GraphQL errors,
+// exposing the code, slug, friendly message, fix & details of an error as
+// extensions. Presenter matches gqlgen's ErrorPresenterFunc, so it can be
+// installed on a gqlgen server with SetErrorPresenter
+package errorsgraphql
@@ -0,0 +1,16 @@
+module github.com/qri-io/errors/errorsgraphql
+
+go 1.21
+
+require (
+	github.com/qri-io/errors v0.0.0-00010101000000-000000000000
+	github.com/vektah/gqlparser/v2 v2.5.16
+)
+
+require (
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace (
+	github.com/qri-io/errors => ../
+)
@@ -0,0 +1,99 @@
+package errorsgraphql
+
+import (
+	"context"
+	goerrors "errors"
+	"strings"
+
+	"github.com/qri-io/errors"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// ToGraphQLError converts an error to a GraphQL error. The message is the
+// machine message, and the extensions hold the upper-cased slug as "code",
+// along with the slug, friendly message, fix & details of the error. When
+// errors.DefaultOutputMode is errors.OutputExternal the message is the
+// friendly message, and details are left out. Errors that aren't an
+// *errors.Error become a generic internal error. nil errors convert to nil
+func ToGraphQLError(err error) *gqlerror.Error {
+	if err == nil {
+		return nil
+	}
+	e, ok := errors.AsError(err)
+	if !ok {
+		return &gqlerror.Error{
+			Err:        err,
+			Message:    "internal error",
+			Extensions: map[string]interface{}{"code": "INTERNAL"},
+		}
+	}
+
+	reg := e.Registry()
+	code := reg.Canonical(errors.ResolveCode(err))
+	slug := reg.CodeSlug(code)
+	msg := e.Message()
+	if errors.DefaultOutputMode == errors.OutputExternal {
+		e = e.Redact()
+		msg = e.FriendlyMessage()
+	}
+	ext := map[string]interface{}{
+		"code": strings.ToUpper(slug),
+		"slug": slug,
+	}
+	if friendly := e.FriendlyMessage(); friendly != "" {
+		ext["friendly"] = friendly
+	}
+	if fix := e.Fix(); fix != "" {
+		ext["fix"] = fix
+	}
+	if details := e.Details(); len(details) > 0 {
+		ext["details"] = details
+	}
+	return &gqlerror.Error{Err: err, Message: msg, Extensions: ext}
+}
+
+// FromGraphQLError converts a GraphQL error to an *errors.Error, reading the
+// code from the "slug" or "code" extensions. friendly, fix & details
+// extensions are restored. nil errors convert to nil
+func FromGraphQLError(gerr *gqlerror.Error) *errors.Error {
+	if gerr == nil {
+		return nil
+	}
+	code := errors.CodeGeneric
+	if slug, ok := gerr.Extensions["slug"].(string); ok {
+		code = errors.CodeFromSlug(slug)
+	} else if slug, ok := gerr.Extensions["code"].(string); ok {
+		code = errors.CodeFromSlug(strings.ToLower(slug))
+	}
+	if code == errors.CodeUnknown {
+		code = errors.CodeGeneric
+	}
+
+	friendly, _ := gerr.Extensions["friendly"].(string)
+	fix, _ := gerr.Extensions["fix"].(string)
+	e := errors.NewFriendlyFix(code, gerr.Message, friendly, fix)
+	if details, ok := gerr.Extensions["details"].(map[string]interface{}); ok {
+		for key, val := range details {
+			e.WithDetail(key, val)
+		}
+	}
+	return e
+}
+
+// Presenter is a gqlgen ErrorPresenterFunc converting resolver errors with
+// ToGraphQLError. The path & locations of GraphQL errors that wrap an
+// *errors.Error are kept, and GraphQL errors that don't are returned as-is
+func Presenter(ctx context.Context, err error) *gqlerror.Error {
+	var gerr *gqlerror.Error
+	if goerrors.As(err, &gerr) {
+		if _, ok := errors.AsError(gerr.Err); !ok {
+			return gerr
+		}
+		presented := ToGraphQLError(gerr.Err)
+		presented.Path = gerr.Path
+		presented.Locations = gerr.Locations
+		presented.Rule = gerr.Rule
+		return presented
+	}
+	return ToGraphQLError(err)
+}
@@ -0,0 +1,82 @@
+package errorsgraphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/qri-io/errors"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestGraphQLErrorRoundTrip(t *testing.T) {
+	e := errors.NewFriendlyFix(errors.CodeNotFound, "no dataset", "couldn't find that dataset", "check the name").
+		WithDetail("ref", "b5/world_bank")
+
+	gerr := ToGraphQLError(e)
+	if gerr.Message != "no dataset" {
+		t.Errorf("message mismatch. expected: %s, got: %s", "no dataset", gerr.Message)
+	}
+	if gerr.Extensions["code"] != "NOT_FOUND" || gerr.Extensions["slug"] != "not_found" {
+		t.Errorf("extensions mismatch. got: %v", gerr.Extensions)
+	}
+
+	// round trip through json as a client would receive the error
+	data, err := json.Marshal(gerr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &gqlerror.Error{}
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	got := FromGraphQLError(decoded)
+	if got.Code() != errors.CodeNotFound || got.Friendly() != e.Friendly() || got.Details()["ref"] != "b5/world_bank" {
+		t.Errorf("round trip mismatch. expected: %s, got: %s", e.Friendly(), got.Friendly())
+	}
+
+	internal := ToGraphQLError(fmt.Errorf("secret database password"))
+	if internal.Message != "internal error" {
+		t.Errorf("internal error message mismatch. expected: %s, got: %s", "internal error", internal.Message)
+	}
+}
+
+func TestPresenter(t *testing.T) {
+	e := errors.New(errors.CodeForbidden, "private dataset")
+	wrapped := gqlerror.WrapPath(ast.Path{ast.PathName("dataset")}, e)
+
+	got := Presenter(context.Background(), wrapped)
+	if got.Extensions["code"] != "FORBIDDEN" {
+		t.Errorf("code mismatch. expected: %s, got: %v", "FORBIDDEN", got.Extensions["code"])
+	}
+	if got.Path.String() != "dataset" {
+		t.Errorf("path mismatch. expected: %s, got: %s", "dataset", got.Path.String())
+	}
+
+	validation := gqlerror.Errorf("unknown field")
+	if got := Presenter(context.Background(), validation); got != validation {
+		t.Errorf("expected graphql errors that don't wrap an *errors.Error to pass through")
+	}
+}
+
+func TestToGraphQLErrorExternal(t *testing.T) {
+	prev := errors.DefaultOutputMode
+	errors.DefaultOutputMode = errors.OutputExternal
+	defer func() { errors.DefaultOutputMode = prev }()
+
+	e := errors.NewFriendlyFix(errors.CodeNotFound, "no row with id 42", "couldn't find that dataset", "check the name").
+		WithDetail("table", "datasets")
+
+	gerr := ToGraphQLError(e)
+	if gerr.Message != "couldn't find that dataset" {
+		t.Errorf("expected the friendly message. got: %s", gerr.Message)
+	}
+	if gerr.Extensions["fix"] != "check the name" || gerr.Extensions["slug"] != "not_found" {
+		t.Errorf("extensions mismatch. got: %v", gerr.Extensions)
+	}
+	if _, ok := gerr.Extensions["details"]; ok {
+		t.Errorf("expected details to be left out. got: %v", gerr.Extensions)
+	}
+}
@@ -0,0 +1,80 @@
+package errorsgrpc
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/qri-io/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Domain is the ErrorInfo domain attached to statuses, usually the name of
+// the service
+var Domain = ""
+
+// standardDetails creates the standard grpc detail messages for an error:
+// ErrorInfo with the code's slug as reason and the error's details as
+// metadata, BadRequest with a field violation for every error in the chain
+// that applies to a field, and RetryInfo when the chain holds rate limit
+// metadata. external errors leave out metadata & field violations, which
+// carry details & machine messages
+func standardDetails(e *errors.Error, code errors.Code, err error, external bool) []protoadapt.MessageV1 {
+	reg := e.Registry()
+	info := &errdetails.ErrorInfo{
+		Reason: strings.ToUpper(reg.CodeSlug(code)),
+		Domain: Domain,
+	}
+	for key, val := range e.Details() {
+		if info.Metadata == nil {
+			info.Metadata = map[string]string{}
+		}
+		info.Metadata[key] = fmt.Sprint(val)
+	}
+	details := []protoadapt.MessageV1{info}
+
+	badRequest := &errdetails.BadRequest{}
+	var rateLimit *errors.RateLimit
+	each(err, func(e *errors.Error) {
+		if field := e.Field(); field != "" && !external {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       field,
+				Description: e.Message(),
+			})
+		}
+		if rl, ok := e.RateLimit(); ok && rateLimit == nil {
+			rateLimit = &rl
+		}
+	})
+	if len(badRequest.FieldViolations) > 0 {
+		details = append(details, badRequest)
+	}
+	if rateLimit != nil {
+		details = append(details, &errdetails.RetryInfo{
+			RetryDelay: durationpb.New(rateLimit.RetryAfter(time.Now())),
+		})
+	}
+	return details
+}
+
+// each calls fn for every *errors.Error in err's chain, outermost first
+func each(err error, fn func(*errors.Error)) {
+	if err == nil {
+		return
+	}
+	if e, ok := err.(*errors.Error); ok {
+		fn(e)
+	}
+	switch x := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, child := range x.Unwrap() {
+			each(child, fn)
+		}
+	case interface{ Unwrap() error }:
+		each(x.Unwrap(), fn)
+	case interface{ Cause() error }:
+		each(x.Cause(), fn)
+	}
+}
@@ -0,0 +1,50 @@
+package errorsgrpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qri-io/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func TestStandardDetails(t *testing.T) {
+	err := errors.WrapAll(errors.CodeInvalidArgs, []error{
+		errors.New(errors.CodeInvalidArgs, "name is required").WithField("name"),
+		errors.New(errors.CodeInvalidArgs, "bad email").WithField("email"),
+	}, "invalid profile").WithDetail("user", "b5")
+
+	st := ToGRPCStatus(err)
+	var info *errdetails.ErrorInfo
+	var badRequest *errdetails.BadRequest
+	for _, d := range st.Details() {
+		switch x := d.(type) {
+		case *errdetails.ErrorInfo:
+			info = x
+		case *errdetails.BadRequest:
+			badRequest = x
+		case *errdetails.RetryInfo:
+			t.Errorf("expected no retry info without rate limit metadata")
+		}
+	}
+	if info == nil || info.Reason != "INVALID_ARGS" || info.Metadata["user"] != "b5" {
+		t.Errorf("error info mismatch. got: %v", info)
+	}
+	if badRequest == nil || len(badRequest.FieldViolations) != 2 {
+		t.Fatalf("expected two field violations. got: %v", badRequest)
+	}
+	if fv := badRequest.FieldViolations[1]; fv.Field != "email" || fv.Description != "bad email" {
+		t.Errorf("field violation mismatch. got: %v", fv)
+	}
+
+	limited := errors.NewRateLimited(10, 0, time.Now().Add(5*time.Second), "slow down")
+	var retry *errdetails.RetryInfo
+	for _, d := range ToGRPCStatus(limited).Details() {
+		if x, ok := d.(*errdetails.RetryInfo); ok {
+			retry = x
+		}
+	}
+	if retry == nil || retry.RetryDelay.AsDuration() != 5*time.Second {
+		t.Errorf("retry info mismatch. got: %v", retry)
+	}
+}
@@ -0,0 +1,4 @@
+// Package errorsgrpc converts between github.com/qri-io/errors and grpc
+// statuses, so grpc services built on qri errors don't hand-roll the
+// translation
+package errorsgrpc
@@ -0,0 +1,24 @@
+module github.com/qri-io/errors/errorsgrpc
+
+go 1.21
+
+require (
+	github.com/qri-io/errors v0.0.0-00010101000000-000000000000
+	github.com/qri-io/errors/errorspb v0.0.0-00010101000000-000000000000
+	github.com/qri-io/errors/errorsproto v0.0.0-00010101000000-000000000000
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240318140521-94a12d6c2237
+	google.golang.org/grpc v1.64.0
+	google.golang.org/protobuf v1.36.5
+)
+
+require (
+	golang.org/x/net v0.23.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace (
+	github.com/qri-io/errors => ../
+	github.com/qri-io/errors/errorspb => ../errorspb
+	github.com/qri-io/errors/errorsproto => ../errorsproto
+)
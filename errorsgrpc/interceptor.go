@@ -0,0 +1,141 @@
+package errorsgrpc
+
+import (
+	"context"
+
+	"github.com/qri-io/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// LogFunc is called with every error an interceptor converts, along with the
+// full method name of the call and the code errors.ResolveCode picks
+type LogFunc func(ctx context.Context, method string, code errors.Code, err error)
+
+// ServerOptions configures server interceptors
+type ServerOptions struct {
+	// Log is called for every error a handler returns. nil disables logging
+	Log LogFunc
+	// LogCodes limits logging to the listed codes. An empty list logs every
+	// code
+	LogCodes []errors.Code
+	// Origin sends the origin of errors as trailers, so clients using the
+	// client interceptors can report where errors were first raised. Only
+	// enable it for services called by other internal services
+	Origin bool
+}
+
+// shouldLog reports whether errors with code are logged
+func (o ServerOptions) shouldLog(code errors.Code) bool {
+	if o.Log == nil {
+		return false
+	}
+	if len(o.LogCodes) == 0 {
+		return true
+	}
+	for _, c := range o.LogCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// serverError logs & converts an error returned by a handler, sending the
+// origin of the error with setTrailer when enabled
+func (o ServerOptions) serverError(ctx context.Context, method string, err error, setTrailer func(metadata.MD) error) error {
+	if err == nil {
+		return nil
+	}
+	code := errors.ResolveCode(err)
+	if o.shouldLog(code) {
+		o.Log(ctx, method, code, err)
+	}
+	if o.Origin {
+		setTrailer(OriginMetadata(err))
+	}
+	return ToGRPCStatus(err).Err()
+}
+
+// UnaryServerInterceptor converts errors returned by unary handlers to grpc
+// statuses with ToGRPCStatus
+func UnaryServerInterceptor(opts ServerOptions) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		return resp, opts.serverError(ctx, info.FullMethod, err, func(md metadata.MD) error {
+			return grpc.SetTrailer(ctx, md)
+		})
+	}
+}
+
+// StreamServerInterceptor converts errors returned by streaming handlers to
+// grpc statuses with ToGRPCStatus
+func StreamServerInterceptor(opts ServerOptions) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return opts.serverError(ss.Context(), info.FullMethod, handler(srv, ss), func(md metadata.MD) error {
+			ss.SetTrailer(md)
+			return nil
+		})
+	}
+}
+
+// clientError converts an error returned by a call to an *errors.Error.
+// Errors that don't carry a status are returned unchanged
+func clientError(err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	if e := FromGRPCStatus(st); e != nil {
+		return e
+	}
+	return nil
+}
+
+// UnaryClientInterceptor reconstructs *errors.Error values from the statuses
+// of unary calls with FromGRPCStatus, attaching any origin sent in trailers
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		var trailer metadata.MD
+		err := clientError(invoker(ctx, method, req, reply, cc, append(opts, grpc.Trailer(&trailer))...))
+		return withOrigin(err, trailer)
+	}
+}
+
+// StreamClientInterceptor reconstructs *errors.Error values from the
+// statuses of streaming calls with FromGRPCStatus, both when opening a stream
+// and when receiving messages. Origins sent in trailers are attached to
+// errors received from the stream
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, clientError(err)
+		}
+		return &clientStream{ClientStream: cs}, nil
+	}
+}
+
+// clientStream converts errors from a client stream
+type clientStream struct {
+	grpc.ClientStream
+}
+
+// RecvMsg implements the grpc.ClientStream interface. io.EOF is passed
+// through untouched
+func (cs *clientStream) RecvMsg(m interface{}) error {
+	err := clientError(cs.ClientStream.RecvMsg(m))
+	if err == nil {
+		return nil
+	}
+	return withOrigin(err, cs.ClientStream.Trailer())
+}
+
+// SendMsg implements the grpc.ClientStream interface
+func (cs *clientStream) SendMsg(m interface{}) error {
+	return clientError(cs.ClientStream.SendMsg(m))
+}
@@ -0,0 +1,88 @@
+package errorsgrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/qri-io/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// healthServer fails every call with a qri error
+type healthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+}
+
+func (healthServer) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	return nil, errors.NewFriendly(errors.CodeNotFound, "no service", "that service doesn't exist", req.Service).
+		WithDetail(errors.OpDetail, "health.Check")
+}
+
+func (healthServer) Watch(req *grpc_health_v1.HealthCheckRequest, ss grpc_health_v1.Health_WatchServer) error {
+	return errors.New(errors.CodeUnavailable, "watch unavailable")
+}
+
+func TestInterceptors(t *testing.T) {
+	var logged []errors.Code
+	opts := ServerOptions{
+		Log: func(ctx context.Context, method string, code errors.Code, err error) {
+			logged = append(logged, code)
+		},
+		LogCodes: []errors.Code{errors.CodeUnavailable},
+		Origin:   true,
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(opts)),
+		grpc.StreamInterceptor(StreamServerInterceptor(opts)),
+	)
+	grpc_health_v1.RegisterHealthServer(srv, healthServer{})
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(StreamClientInterceptor()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := grpc_health_v1.NewHealthClient(conn)
+	ctx := context.Background()
+
+	_, err = client.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "datasets"})
+	e, ok := err.(*errors.Error)
+	if !ok {
+		t.Fatalf("expected client to receive an *errors.Error. got: %T %v", err, err)
+	}
+	if e.Code() != errors.CodeNotFound || e.Friendly() != "missing: that service doesn't exist datasets." {
+		t.Errorf("error mismatch. got: %d %q", e.Code(), e.Friendly())
+	}
+	if o := errors.OriginOf(e); o.Code != errors.CodeNotFound || o.Op != "health.Check" {
+		t.Errorf("origin mismatch. expected: not_found health.Check, got: %v", o)
+	}
+
+	stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = stream.Recv()
+	if !errors.IsCode(err, errors.CodeUnavailable) {
+		t.Errorf("expected stream error to be unavailable. got: %v", err)
+	}
+	if o := errors.OriginOf(err); o.Code != errors.CodeUnavailable {
+		t.Errorf("stream origin code mismatch. expected: %d, got: %d", errors.CodeUnavailable, o.Code)
+	}
+
+	if len(logged) != 1 || logged[0] != errors.CodeUnavailable {
+		t.Errorf("expected only unavailable errors to be logged. got: %v", logged)
+	}
+}
@@ -0,0 +1,64 @@
+package errorsgrpc
+
+import (
+	"github.com/qri-io/errors"
+	"google.golang.org/grpc/metadata"
+)
+
+// metadata keys carrying origins, matching the errors origin headers
+const (
+	originCodeKey = "x-error-origin-code"
+	originOpKey   = "x-error-origin-op"
+	originIDKey   = "x-error-origin-id"
+)
+
+// OriginMetadata returns the origin of an error as grpc metadata, for
+// sending as trailers to other internal services
+func OriginMetadata(err error) metadata.MD {
+	md := metadata.MD{}
+	if err == nil {
+		return md
+	}
+	o := errors.OriginOf(err)
+	if o.Code != errors.CodeUnknown {
+		md.Set(originCodeKey, errors.CodeSlug(o.Code))
+	}
+	if o.Op != "" {
+		md.Set(originOpKey, o.Op)
+	}
+	if o.ID != "" {
+		md.Set(originIDKey, o.ID)
+	}
+	return md
+}
+
+// OriginFromMetadata reads an origin written by OriginMetadata
+func OriginFromMetadata(md metadata.MD) (errors.Origin, bool) {
+	slug := first(md, originCodeKey)
+	if slug == "" {
+		return errors.Origin{}, false
+	}
+	return errors.Origin{
+		Code: errors.CodeFromSlug(slug),
+		Op:   first(md, originOpKey),
+		ID:   first(md, originIDKey),
+	}, true
+}
+
+// first returns the first value for a metadata key
+func first(md metadata.MD, key string) string {
+	if vals := md.Get(key); len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
+
+// withOrigin attaches the origin in md to a converted client error
+func withOrigin(err error, md metadata.MD) error {
+	if e, ok := err.(*errors.Error); ok {
+		if o, ok := OriginFromMetadata(md); ok {
+			e.WithOrigin(o)
+		}
+	}
+	return err
+}
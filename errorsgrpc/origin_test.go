@@ -0,0 +1,24 @@
+package errorsgrpc
+
+import (
+	"testing"
+
+	"github.com/qri-io/errors"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestOriginMetadata(t *testing.T) {
+	err := errors.New(errors.CodeTimeout, "peer took too long").
+		WithOrigin(errors.Origin{Code: errors.CodeTimeout, Op: "p2p.Fetch", ID: "err-789"})
+	md := OriginMetadata(err)
+	got, ok := OriginFromMetadata(md)
+	if !ok {
+		t.Fatal("expected origin metadata to be set")
+	}
+	if got != (errors.Origin{Code: errors.CodeTimeout, Op: "p2p.Fetch", ID: "err-789"}) {
+		t.Errorf("origin mismatch. got: %v", got)
+	}
+	if _, ok := OriginFromMetadata(metadata.MD{}); ok {
+		t.Errorf("expected no origin from empty metadata")
+	}
+}
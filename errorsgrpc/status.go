@@ -0,0 +1,70 @@
+package errorsgrpc
+
+import (
+	"github.com/qri-io/errors"
+	"github.com/qri-io/errors/errorspb"
+	"github.com/qri-io/errors/errorsproto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// ToGRPCStatus converts an error to a grpc status. The status code comes from
+// the registry's grpc mapping for the code errors.ResolveCode picks, and the
+// status message is the machine message. The complete error, including it's
+// friendly message, fix, details and causes, is attached as an
+// errorspb.Error detail for FromGRPCStatus to read. Standard ErrorInfo,
+// BadRequest and RetryInfo details follow, for clients in other languages.
+// When errors.DefaultOutputMode is errors.OutputExternal statuses hold what
+// WriteHTTP writes to external audiences: the status message is the friendly
+// message, the attached error is redacted, and ErrorInfo metadata & BadRequest
+// details are left out.
+// Errors that aren't an *errors.Error keep any status they carry, with
+// context errors converted to their grpc codes. nil errors convert to a nil
+// status, which grpc treats as OK
+func ToGRPCStatus(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+	e, ok := errors.AsError(err)
+	if !ok {
+		if st, ok := status.FromError(err); ok {
+			return st
+		}
+		return status.FromContextError(err)
+	}
+
+	reg := e.Registry()
+	code := reg.Canonical(errors.ResolveCode(err))
+	external := errors.DefaultOutputMode == errors.OutputExternal
+	msg, full := e.Message(), err
+	if external {
+		e = e.Redact()
+		msg, full = e.FriendlyMessage(), e
+	}
+	st := status.New(codes.Code(reg.CodeGRPCStatus(code)), msg)
+	details := standardDetails(e, code, err, external)
+	if pb, perr := errorsproto.ToProto(full); perr == nil {
+		details = append([]protoadapt.MessageV1{pb}, details...)
+	}
+	if withDetails, derr := st.WithDetails(details...); derr == nil {
+		return withDetails
+	}
+	return st
+}
+
+// FromGRPCStatus converts a grpc status to an *errors.Error, returning nil for
+// OK statuses. Statuses created by ToGRPCStatus are decoded from their
+// errorspb.Error detail. Other statuses become an error with the code for the
+// status code and the status message
+func FromGRPCStatus(st *status.Status) *errors.Error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+	for _, d := range st.Details() {
+		if pb, ok := d.(*errorspb.Error); ok {
+			return errorsproto.FromProto(pb)
+		}
+	}
+	return errors.New(errors.CodeFromGRPCStatus(uint32(st.Code())), st.Message())
+}
@@ -0,0 +1,93 @@
+package errorsgrpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qri-io/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToGRPCStatus(t *testing.T) {
+	e := errors.NewFriendlyFix(errors.CodeNotFound, "no dataset", "couldn't find that dataset", "check the name").
+		WithDetail("ref", "b5/world_bank")
+
+	st := ToGRPCStatus(e)
+	if st.Code() != codes.NotFound {
+		t.Errorf("status code mismatch. expected: %s, got: %s", codes.NotFound, st.Code())
+	}
+	if st.Message() != "no dataset" {
+		t.Errorf("status message mismatch. expected: %s, got: %s", "no dataset", st.Message())
+	}
+
+	// round trip through the wire format
+	got := FromGRPCStatus(status.FromProto(st.Proto()))
+	if got.Code() != errors.CodeNotFound {
+		t.Errorf("code mismatch. expected: %d, got: %d", errors.CodeNotFound, got.Code())
+	}
+	if got.Friendly() != e.Friendly() || got.Fix() != e.Fix() {
+		t.Errorf("friendly mismatch. expected: %s, got: %s", e.Friendly(), got.Friendly())
+	}
+	if got.Details()["ref"] != "b5/world_bank" {
+		t.Errorf("expected details to survive. got: %v", got.Details())
+	}
+
+	if st := ToGRPCStatus(nil); st.Code() != codes.OK {
+		t.Errorf("expected nil error to convert to OK. got: %s", st.Code())
+	}
+	if st := ToGRPCStatus(context.DeadlineExceeded); st.Code() != codes.DeadlineExceeded {
+		t.Errorf("status code mismatch. expected: %s, got: %s", codes.DeadlineExceeded, st.Code())
+	}
+	wrapped := errors.Wrap(errors.CodeGeneric, status.Error(codes.Aborted, "aborted"), "writing")
+	if st := ToGRPCStatus(wrapped); st.Code() != codes.Unknown {
+		t.Errorf("status code mismatch. expected: %s, got: %s", codes.Unknown, st.Code())
+	}
+}
+
+func TestFromGRPCStatus(t *testing.T) {
+	got := FromGRPCStatus(status.New(codes.Unauthenticated, "missing token"))
+	if got.Code() != errors.CodeUnauthorized {
+		t.Errorf("code mismatch. expected: %d, got: %d", errors.CodeUnauthorized, got.Code())
+	}
+	if got.Message() != "missing token" {
+		t.Errorf("message mismatch. expected: %s, got: %s", "missing token", got.Message())
+	}
+	if FromGRPCStatus(status.New(codes.OK, "")) != nil {
+		t.Errorf("expected OK status to convert to nil")
+	}
+}
+
+func TestToGRPCStatusExternal(t *testing.T) {
+	prev := errors.DefaultOutputMode
+	errors.DefaultOutputMode = errors.OutputExternal
+	defer func() { errors.DefaultOutputMode = prev }()
+
+	cause := errors.New(errors.CodeInternal, "connecting to postgres://admin@10.0.0.4")
+	e := errors.WrapFriendly(errors.CodeNotFound, cause, "no row with id 42", "couldn't find that dataset").
+		WithField("ref").
+		WithDetail("table", "datasets")
+
+	st := ToGRPCStatus(e)
+	if st.Message() != "couldn't find that dataset" {
+		t.Errorf("expected the friendly message. got: %s", st.Message())
+	}
+	got := FromGRPCStatus(status.FromProto(st.Proto()))
+	if got.Code() != errors.CodeNotFound || got.FriendlyMessage() != "couldn't find that dataset" {
+		t.Errorf("round trip mismatch. got: %d %s", got.Code(), got.FriendlyMessage())
+	}
+	if got.Message() != "" || got.Field() != "" || len(got.Details()) > 0 || errors.IsCode(got, errors.CodeInternal) {
+		t.Errorf("expected the attached error to be redacted. got: %q %q %v", got.Message(), got.Field(), got.Details())
+	}
+	for _, d := range st.Details() {
+		switch x := d.(type) {
+		case *errdetails.ErrorInfo:
+			if len(x.Metadata) > 0 {
+				t.Errorf("expected error info metadata to be left out. got: %v", x.Metadata)
+			}
+		case *errdetails.BadRequest:
+			t.Errorf("expected bad request details to be left out. got: %v", x)
+		}
+	}
+}
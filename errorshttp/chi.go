@@ -0,0 +1,19 @@
+package errorshttp
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qri-io/errors"
+)
+
+// Chi configures a chi router to answer unmatched routes with a CodeNotFound
+// error written by hw, and returns it for chaining. Handlers that return
+// errors can be mounted with Handler, and panics recovered with
+// errors.Recover
+func Chi(r chi.Router, hw *errors.HTTPWriter) chi.Router {
+	r.NotFound(func(w http.ResponseWriter, req *http.Request) {
+		writer(hw).WriteRequest(w, req, errors.New(errors.CodeNotFound, "no route", req.Method, req.URL.Path))
+	})
+	return r
+}
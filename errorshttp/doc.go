@@ -0,0 +1,6 @@
+// Package errorshttp adapts the http writers in github.com/qri-io/errors to
+// popular router frameworks. Errors returned or raised in chi, gin and echo
+// handlers are written with an *errors.HTTPWriter, so every router answers
+// with the same status codes & bodies. A nil writer uses
+// errors.DefaultHTTPWriter
+package errorshttp
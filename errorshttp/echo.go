@@ -0,0 +1,36 @@
+package errorshttp
+
+import (
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+	"github.com/qri-io/errors"
+)
+
+// Echo returns an echo.HTTPErrorHandler that writes errors with hw. Set it as
+// the HTTPErrorHandler of an echo instance. Errors echo raises itself, like
+// unmatched routes, are converted to the code for their status
+func Echo(hw *errors.HTTPWriter) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+		writer(hw).WriteRequest(c.Response(), c.Request(), FromEcho(err))
+	}
+}
+
+// FromEcho converts an *echo.HTTPError to an *errors.Error with the code for
+// it's status, wrapping the internal error echo holds. Other errors are
+// returned unchanged
+func FromEcho(err error) error {
+	he, ok := err.(*echo.HTTPError)
+	if !ok {
+		return err
+	}
+	code := errors.CodeFromHTTPStatus(he.Code)
+	msg := fmt.Sprint(he.Message)
+	if he.Internal != nil {
+		return errors.Wrap(code, he.Internal, msg)
+	}
+	return errors.New(code, msg)
+}
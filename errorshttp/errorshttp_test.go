@@ -0,0 +1,106 @@
+package errorshttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-chi/chi/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/qri-io/errors"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	errors.LogHTTPError = nil
+}
+
+// responseCode reads the code from an error response
+func responseCode(t *testing.T, w *httptest.ResponseRecorder) errors.Code {
+	t.Helper()
+	body := struct {
+		Code errors.Code `json:"code"`
+	}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid error body %q: %s", w.Body.String(), err)
+	}
+	return body.Code
+}
+
+func TestChi(t *testing.T) {
+	r := Chi(chi.NewRouter(), nil)
+	r.Method("GET", "/datasets/{name}", Handler(nil, func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New(errors.CodeForbidden, "private dataset", chi.URLParam(r, "name"))
+	}))
+
+	cases := []struct {
+		path   string
+		status int
+		code   errors.Code
+	}{
+		{"/datasets/world_bank", http.StatusForbidden, errors.CodeForbidden},
+		{"/nope", http.StatusNotFound, errors.CodeNotFound},
+	}
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", c.path, nil))
+		if w.Code != c.status {
+			t.Errorf("%s status mismatch. expected: %d, got: %d", c.path, c.status, w.Code)
+		}
+		if got := responseCode(t, w); got != c.code {
+			t.Errorf("%s code mismatch. expected: %d, got: %d", c.path, c.code, got)
+		}
+	}
+}
+
+func TestGin(t *testing.T) {
+	r := gin.New()
+	r.Use(Gin(nil))
+	r.GET("/datasets/:name", func(c *gin.Context) {
+		GinAbort(c, errors.New(errors.CodeNotFound, "no dataset", c.Param("name")))
+	})
+	r.GET("/written", func(c *gin.Context) {
+		c.String(http.StatusAccepted, "ok")
+		c.Error(errors.New(errors.CodeInternal, "late failure"))
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/datasets/world_bank", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status mismatch. expected: %d, got: %d", http.StatusNotFound, w.Code)
+	}
+	if got := responseCode(t, w); got != errors.CodeNotFound {
+		t.Errorf("code mismatch. expected: %d, got: %d", errors.CodeNotFound, got)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/written", nil))
+	if w.Code != http.StatusAccepted || w.Body.String() != "ok" {
+		t.Errorf("expected written responses to be left alone. got: %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestEcho(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = Echo(&errors.HTTPWriter{Mode: errors.OutputExternal})
+	e.GET("/datasets/:name", func(c echo.Context) error {
+		return errors.New(errors.CodeConflict, "dataset exists", c.Param("name"))
+	})
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/datasets/world_bank", nil))
+	if w.Code != http.StatusConflict {
+		t.Errorf("status mismatch. expected: %d, got: %d", http.StatusConflict, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/nope", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status mismatch. expected: %d, got: %d", http.StatusNotFound, w.Code)
+	}
+	if got := responseCode(t, w); got != errors.CodeNotFound {
+		t.Errorf("code mismatch. expected: %d, got: %d", errors.CodeNotFound, got)
+	}
+}
@@ -0,0 +1,27 @@
+package errorshttp
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/qri-io/errors"
+)
+
+// Gin is gin middleware that writes the last error attached to a request
+// with hw, once every handler has run. Handlers should report errors with
+// GinAbort, or c.Error followed by c.Abort. Responses that have already been
+// written are left alone
+func Gin(hw *errors.HTTPWriter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		writer(hw).WriteRequest(c.Writer, c.Request, c.Errors.Last().Err)
+	}
+}
+
+// GinAbort attaches err to c and stops the handler chain, leaving the response
+// for the Gin middleware to write
+func GinAbort(c *gin.Context, err error) {
+	c.Error(err)
+	c.Abort()
+}
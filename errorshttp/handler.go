@@ -0,0 +1,58 @@
+package errorshttp
+
+import (
+	"net/http"
+
+	"github.com/qri-io/errors"
+)
+
+// writer returns hw, or the default writer if hw is nil
+func writer(hw *errors.HTTPWriter) *errors.HTTPWriter {
+	if hw == nil {
+		return errors.DefaultHTTPWriter
+	}
+	return hw
+}
+
+// Handler adapts a handler that returns an error to an http.Handler, writing
+// returned errors with hw. It works with chi and any other router built on
+// net/http. Errors are logged with errors.LogHTTPError, and only logged if the
+// response has already started
+func Handler(hw *errors.HTTPWriter, fn errors.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &responseWriter{ResponseWriter: w}
+		err := fn(rw, r)
+		if err == nil {
+			return
+		}
+		if errors.LogHTTPError != nil {
+			errors.LogHTTPError(r, err)
+		}
+		if !rw.wroteHeader {
+			writer(hw).WriteRequest(w, r, err)
+		}
+	})
+}
+
+// responseWriter records whether a response has been started
+type responseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+// WriteHeader implements the http.ResponseWriter interface
+func (rw *responseWriter) WriteHeader(status int) {
+	rw.wroteHeader = true
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements the http.ResponseWriter interface
+func (rw *responseWriter) Write(p []byte) (int, error) {
+	rw.wroteHeader = true
+	return rw.ResponseWriter.Write(p)
+}
+
+// Unwrap returns the underlying writer for http.ResponseController
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,3 @@
+// Package errorslogrus attaches github.com/qri-io/errors values to
+// github.com/sirupsen/logrus entries as structured fields
+package errorslogrus
@@ -0,0 +1,18 @@
+module github.com/qri-io/errors/errorslogrus
+
+go 1.21
+
+require (
+	github.com/qri-io/errors v0.0.0-00010101000000-000000000000
+	github.com/sirupsen/logrus v1.9.3
+)
+
+require (
+	github.com/stretchr/testify v1.9.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace (
+	github.com/qri-io/errors => ../
+)
@@ -0,0 +1,43 @@
+package errorslogrus
+
+import (
+	"github.com/qri-io/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Fields returns the fields errors.LogFields flattens an error into, for use
+// with logger.WithFields(errorslogrus.Fields(err))
+func Fields(err error) logrus.Fields {
+	return logrus.Fields(errors.LogFields(err))
+}
+
+// Hook is a logrus hook expanding errors attached with WithError into
+// fields, so existing log calls gain structured error fields without changes
+type Hook struct {
+	// LogLevels limits the hook to the listed levels. An empty list fires on
+	// every level
+	LogLevels []logrus.Level
+}
+
+// Levels implements the logrus.Hook interface
+func (h Hook) Levels() []logrus.Level {
+	if len(h.LogLevels) == 0 {
+		return logrus.AllLevels
+	}
+	return h.LogLevels
+}
+
+// Fire implements the logrus.Hook interface. Fields already set on the entry
+// are left in place
+func (h Hook) Fire(entry *logrus.Entry) error {
+	err, ok := entry.Data[logrus.ErrorKey].(error)
+	if !ok {
+		return nil
+	}
+	for key, val := range errors.LogFields(err) {
+		if _, ok := entry.Data[key]; !ok || key == logrus.ErrorKey {
+			entry.Data[key] = val
+		}
+	}
+	return nil
+}
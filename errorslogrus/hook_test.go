@@ -0,0 +1,39 @@
+package errorslogrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/qri-io/errors"
+	"github.com/sirupsen/logrus"
+)
+
+func TestHook(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := logrus.New()
+	logger.SetOutput(buf)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.AddHook(Hook{})
+
+	err := errors.New(errors.CodeNotFound, "no dataset").WithDetail("ref", "b5/world_bank")
+	logger.WithError(err).WithField("error_ref", "kept").Error("failed")
+
+	entry := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["error_slug"] != "not_found" || entry["error"] != "missing: no dataset" {
+		t.Errorf("entry mismatch. got: %v", entry)
+	}
+	if entry["error_ref"] != "kept" {
+		t.Errorf("expected existing fields to be left in place. got: %v", entry["error_ref"])
+	}
+}
+
+func TestFields(t *testing.T) {
+	fields := Fields(errors.New(errors.CodeTimeout, "peer took too long"))
+	if fields["error_slug"] != "timeout" {
+		t.Errorf("slug mismatch. expected: %s, got: %v", "timeout", fields["error_slug"])
+	}
+}
@@ -0,0 +1,8 @@
+// Package errorsmsgpack adds MessagePack support to github.com/qri-io/errors,
+// keeping github.com/vmihailenco/msgpack out of the errors package's
+// dependencies. Importing it registers an "application/msgpack" serializer,
+// which Error.MarshalMsgpack & Error.UnmarshalMsgpack use to embed errors in
+// MessagePack envelopes:
+//
+//	import _ "github.com/qri-io/errors/errorsmsgpack"
+package errorsmsgpack
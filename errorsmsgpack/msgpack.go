@@ -0,0 +1,43 @@
+package errorsmsgpack
+
+import (
+	"bytes"
+
+	"github.com/qri-io/errors"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// ContentType is the media type of MessagePack errors
+const ContentType = "application/msgpack"
+
+// Marshal writes an error as MessagePack, with the same fields as
+// MarshalJSON. The serialized error is encoded directly with it's json field
+// names. Map keys are sorted, and whole floats are written as integers
+func Marshal(e *errors.Error) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc := msgpack.NewEncoder(buf)
+	enc.SetCustomStructTag("json")
+	enc.SetSortMapKeys(true)
+	enc.UseCompactFloats(true)
+	if err := enc.Encode(e.WireValue()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal reads an error written by Marshal. Integers in data & details
+// decode as int64 or uint64, and binary values as strings
+func Unmarshal(data []byte) (*errors.Error, error) {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	dec.UseLooseInterfaceDecoding(true)
+	e := &errors.Error{}
+	return e, e.DecodeWire(dec.Decode)
+}
+
+func init() {
+	errors.RegisterSerializer(ContentType, errors.SerializerFuncs{
+		MarshalFunc:   Marshal,
+		UnmarshalFunc: Unmarshal,
+	})
+}
@@ -0,0 +1,50 @@
+package errorsmsgpack
+
+import (
+	"testing"
+
+	"github.com/qri-io/errors"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestErrorMsgpack(t *testing.T) {
+	e := errors.NewFriendlyFix(errors.CodeTooManyRequests, "rate limited", "slow down", "wait a minute", "QmPeer").
+		WithDetail("limit", 100).
+		WithDetail("key", []byte{0xde, 0xad})
+
+	type reply struct {
+		ID  int
+		Err *errors.Error
+	}
+	data, err := msgpack.Marshal(reply{ID: 1, Err: e})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := reply{}
+	if err := msgpack.Unmarshal(data, &r); err != nil {
+		t.Fatal(err)
+	}
+
+	got := r.Err
+	if got.Code() != errors.CodeTooManyRequests || got.Error() != e.Error() || got.Friendly() != e.Friendly() {
+		t.Errorf("round trip mismatch. expected: %s, got: %s", e.Friendly(), got.Friendly())
+	}
+	if got.Details()["limit"] != int64(100) {
+		t.Errorf("detail mismatch. expected: int64(100), got: %#v", got.Details()["limit"])
+	}
+	if got.Details()["key"] != "\xde\xad" {
+		t.Errorf("expected binary values to be written as-is, not base64 encoded. got: %#v", got.Details()["key"])
+	}
+
+	data, err = Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := map[string]interface{}{}
+	if err := msgpack.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if raw["code"] != int8(errors.CodeTooManyRequests) {
+		t.Errorf("expected whole numbers to encode as integers. got: %#v", raw["code"])
+	}
+}
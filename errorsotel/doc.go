@@ -0,0 +1,4 @@
+// Package errorsotel records metrics for github.com/qri-io/errors values
+// with an OpenTelemetry meter: error counts by code, op & severity, and the
+// time from the start of an op to it's failure
+package errorsotel
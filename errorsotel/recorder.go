@@ -0,0 +1,107 @@
+package errorsotel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/qri-io/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// startKey is the context key for op start times
+type startKey struct{}
+
+// StartOp returns a copy of ctx recording the start of an op, so errors
+// recorded with the context report their time to error
+func StartOp(ctx context.Context) context.Context {
+	return context.WithValue(ctx, startKey{}, time.Now())
+}
+
+// Recorder records error metrics with a meter. The "errors" counter counts
+// errors, and the "errors.time_to_error" histogram records seconds from the
+// start of an op to it's failure, for contexts created with StartOp. Both are
+// recorded with code, op & severity attributes
+type Recorder struct {
+	count       metric.Int64Counter
+	timeToError metric.Float64Histogram
+
+	lk sync.Mutex
+	// remove removes the hook Instrument registers, nil when the recorder
+	// isn't instrumented
+	remove func()
+}
+
+// NewRecorder creates a recorder, creating instruments with meter
+func NewRecorder(meter metric.Meter) (*Recorder, error) {
+	count, err := meter.Int64Counter("errors",
+		metric.WithDescription("Number of errors by code, op & severity"),
+		metric.WithUnit("{error}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	timeToError, err := meter.Float64Histogram("errors.time_to_error",
+		metric.WithDescription("Time from the start of an op to it's failure"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{count: count, timeToError: timeToError}, nil
+}
+
+// Record records an error. The code attribute is the slug of the code
+// errors.ResolveCode picks, and the op comes from errors.OriginOf. nil errors
+// aren't recorded
+func (r *Recorder) Record(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	code := errors.CodeGeneric
+	reg := errors.DefaultRegistry
+	if e, ok := errors.AsError(err); ok {
+		reg = e.Registry()
+		code = reg.Canonical(errors.ResolveCode(err))
+	}
+	attrs := metric.WithAttributes(
+		attribute.String("code", reg.CodeSlug(code)),
+		attribute.String("op", errors.OriginOf(err).Op),
+		attribute.String("severity", reg.CodeSeverity(code).String()),
+	)
+	r.count.Add(ctx, 1, attrs)
+	if start, ok := ctx.Value(startKey{}).(time.Time); ok {
+		r.timeToError.Record(ctx, time.Since(start).Seconds(), attrs)
+	}
+}
+
+// Instrument records every error created by errors.New, errors.Wrap and their
+// variants with a hook registered with errors.RegisterHook. Hooks don't get a
+// context, so time to error is only recorded by calling Record directly.
+// Each failure is recorded once, when it's first created: wrapping an error
+// that already has an *errors.Error in it's chain doesn't record it again.
+// Instrumenting a recorder more than once records each error once.
+// Instrument returns a function that removes the hook
+func (r *Recorder) Instrument() (remove func()) {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	if r.remove != nil {
+		return r.remove
+	}
+
+	removeHook := errors.RegisterHook(func(e *errors.Error) {
+		if _, wrapped := errors.AsError(e.Cause()); !wrapped {
+			r.Record(context.Background(), e)
+		}
+	})
+	r.remove = func() {
+		r.lk.Lock()
+		defer r.lk.Unlock()
+		if r.remove != nil {
+			removeHook()
+			r.remove = nil
+		}
+	}
+	return r.remove
+}
@@ -0,0 +1,87 @@
+package errorsotel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qri-io/errors"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// collect gathers the metrics recorded by a reader
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Aggregation {
+	t.Helper()
+	rm := metricdata.ResourceMetrics{}
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	metrics := map[string]metricdata.Aggregation{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+	return metrics
+}
+
+func TestRecorder(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("errorsotel")
+	r, err := NewRecorder(meter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := StartOp(context.Background())
+	r.Record(ctx, errors.New(errors.CodeNotFound, "no dataset").WithDetail(errors.OpDetail, "dsfs.LoadDataset"))
+	r.Record(context.Background(), errors.New(errors.CodeNotFound, "no dataset").WithDetail(errors.OpDetail, "dsfs.LoadDataset"))
+	r.Record(ctx, nil)
+
+	metrics := collect(t, reader)
+	count, ok := metrics["errors"].(metricdata.Sum[int64])
+	if !ok || len(count.DataPoints) != 1 {
+		t.Fatalf("expected one errors data point. got: %v", metrics["errors"])
+	}
+	dp := count.DataPoints[0]
+	if dp.Value != 2 {
+		t.Errorf("count mismatch. expected: %d, got: %d", 2, dp.Value)
+	}
+	if v, _ := dp.Attributes.Value(attribute.Key("code")); v.AsString() != "not_found" {
+		t.Errorf("code attribute mismatch. expected: %s, got: %s", "not_found", v.AsString())
+	}
+	if v, _ := dp.Attributes.Value(attribute.Key("op")); v.AsString() != "dsfs.LoadDataset" {
+		t.Errorf("op attribute mismatch. expected: %s, got: %s", "dsfs.LoadDataset", v.AsString())
+	}
+
+	hist, ok := metrics["errors.time_to_error"].(metricdata.Histogram[float64])
+	if !ok || len(hist.DataPoints) != 1 || hist.DataPoints[0].Count != 1 {
+		t.Errorf("expected time to error to be recorded once, for the started op. got: %v", metrics["errors.time_to_error"])
+	}
+}
+
+func TestInstrument(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	r, err := NewRecorder(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("errorsotel"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	remove := r.Instrument()
+	defer remove()
+	r.Instrument()
+
+	err = errors.New(errors.CodeTimeout, "peer took too long")
+	errors.Wrap(errors.CodeUnavailable, errors.Wrap(errors.CodeTimeout, err, "syncing"), "pulling")
+	count, ok := collect(t, reader)["errors"].(metricdata.Sum[int64])
+	if !ok || len(count.DataPoints) != 1 || count.DataPoints[0].Value != 1 {
+		t.Errorf("expected the error to be recorded once. got: %v", count)
+	}
+
+	remove()
+	errors.New(errors.CodeTimeout, "peer took too long")
+	count, _ = collect(t, reader)["errors"].(metricdata.Sum[int64])
+	if len(count.DataPoints) != 1 || count.DataPoints[0].Value != 1 {
+		t.Errorf("expected removing the hook to stop recording. got: %v", count)
+	}
+}
@@ -0,0 +1,6 @@
+// Package errorspb holds the protobuf definition of a qri error. Use the
+// ToProto and FromProto functions in github.com/qri-io/errors/errorsproto to
+// convert between protobuf messages and *errors.Error
+package errorspb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative errors.proto
@@ -0,0 +1,224 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v3.21.12
+// source: errors.proto
+
+package errorspb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Error is the protobuf form of a qri error, for exchanging errors with
+// services written in other languages
+type Error struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// code is the numeric error code
+	Code int64 `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	// slug is the stable string identifier of the code
+	Slug string `protobuf:"bytes,2,opt,name=slug,proto3" json:"slug,omitempty"`
+	// message is the developer-focused error message
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	// friendly is the user-facing error message
+	Friendly string `protobuf:"bytes,4,opt,name=friendly,proto3" json:"friendly,omitempty"`
+	// fix is a message on how to fix the error
+	Fix string `protobuf:"bytes,5,opt,name=fix,proto3" json:"fix,omitempty"`
+	// field is the input field the error applies to, if any
+	Field string `protobuf:"bytes,6,opt,name=field,proto3" json:"field,omitempty"`
+	// data are the values that caused the error
+	Data []*structpb.Value `protobuf:"bytes,7,rep,name=data,proto3" json:"data,omitempty"`
+	// details are structured key-value details
+	Details *structpb.Struct `protobuf:"bytes,8,opt,name=details,proto3" json:"details,omitempty"`
+	// cause is the error this error wraps
+	Cause *Error `protobuf:"bytes,9,opt,name=cause,proto3" json:"cause,omitempty"`
+	// causes are the errors this error joins
+	Causes        []*Error `protobuf:"bytes,10,rep,name=causes,proto3" json:"causes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_errors_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_errors_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_errors_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Error) GetCode() int64 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *Error) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Error) GetFriendly() string {
+	if x != nil {
+		return x.Friendly
+	}
+	return ""
+}
+
+func (x *Error) GetFix() string {
+	if x != nil {
+		return x.Fix
+	}
+	return ""
+}
+
+func (x *Error) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *Error) GetData() []*structpb.Value {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *Error) GetDetails() *structpb.Struct {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+func (x *Error) GetCause() *Error {
+	if x != nil {
+		return x.Cause
+	}
+	return nil
+}
+
+func (x *Error) GetCauses() []*Error {
+	if x != nil {
+		return x.Causes
+	}
+	return nil
+}
+
+var File_errors_proto protoreflect.FileDescriptor
+
+const file_errors_proto_rawDesc = "" +
+	"\n" +
+	"\ferrors.proto\x12\n" +
+	"qri.errors\x1a\x1cgoogle/protobuf/struct.proto\"\xc0\x02\n" +
+	"\x05Error\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\x03R\x04code\x12\x12\n" +
+	"\x04slug\x18\x02 \x01(\tR\x04slug\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12\x1a\n" +
+	"\bfriendly\x18\x04 \x01(\tR\bfriendly\x12\x10\n" +
+	"\x03fix\x18\x05 \x01(\tR\x03fix\x12\x14\n" +
+	"\x05field\x18\x06 \x01(\tR\x05field\x12*\n" +
+	"\x04data\x18\a \x03(\v2\x16.google.protobuf.ValueR\x04data\x121\n" +
+	"\adetails\x18\b \x01(\v2\x17.google.protobuf.StructR\adetails\x12'\n" +
+	"\x05cause\x18\t \x01(\v2\x11.qri.errors.ErrorR\x05cause\x12)\n" +
+	"\x06causes\x18\n" +
+	" \x03(\v2\x11.qri.errors.ErrorR\x06causesB#Z!github.com/qri-io/errors/errorspbb\x06proto3"
+
+var (
+	file_errors_proto_rawDescOnce sync.Once
+	file_errors_proto_rawDescData []byte
+)
+
+func file_errors_proto_rawDescGZIP() []byte {
+	file_errors_proto_rawDescOnce.Do(func() {
+		file_errors_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_errors_proto_rawDesc), len(file_errors_proto_rawDesc)))
+	})
+	return file_errors_proto_rawDescData
+}
+
+var file_errors_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_errors_proto_goTypes = []any{
+	(*Error)(nil),           // 0: qri.errors.Error
+	(*structpb.Value)(nil),  // 1: google.protobuf.Value
+	(*structpb.Struct)(nil), // 2: google.protobuf.Struct
+}
+var file_errors_proto_depIdxs = []int32{
+	1, // 0: qri.errors.Error.data:type_name -> google.protobuf.Value
+	2, // 1: qri.errors.Error.details:type_name -> google.protobuf.Struct
+	0, // 2: qri.errors.Error.cause:type_name -> qri.errors.Error
+	0, // 3: qri.errors.Error.causes:type_name -> qri.errors.Error
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_errors_proto_init() }
+func file_errors_proto_init() {
+	if File_errors_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_errors_proto_rawDesc), len(file_errors_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_errors_proto_goTypes,
+		DependencyIndexes: file_errors_proto_depIdxs,
+		MessageInfos:      file_errors_proto_msgTypes,
+	}.Build()
+	File_errors_proto = out.File
+	file_errors_proto_goTypes = nil
+	file_errors_proto_depIdxs = nil
+}
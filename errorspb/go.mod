@@ -0,0 +1,15 @@
+module github.com/qri-io/errors/errorspb
+
+go 1.21
+
+require (
+	google.golang.org/protobuf v1.36.5
+)
+
+require (
+	github.com/google/go-cmp v0.6.0 // indirect
+)
+
+replace (
+	github.com/qri-io/errors => ../
+)
@@ -0,0 +1,109 @@
+package errorsprom
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/qri-io/errors"
+)
+
+// Labels are the label names of error counters
+var Labels = []string{"code", "op", "severity"}
+
+// Counter counts errors by the slug of their code, their op & severity.
+// Counter is a prometheus.Collector, register it to export counts
+type Counter struct {
+	vec *prometheus.CounterVec
+
+	lk sync.Mutex
+	// remove removes the hook Instrument registers, nil when the counter
+	// isn't instrumented
+	remove func()
+}
+
+// NewCounter creates a counter. opts.Name defaults to "errors_total"
+func NewCounter(opts prometheus.CounterOpts) *Counter {
+	if opts.Name == "" {
+		opts.Name = "errors_total"
+	}
+	if opts.Help == "" {
+		opts.Help = "Number of errors by code, op & severity"
+	}
+	return &Counter{vec: prometheus.NewCounterVec(opts, Labels)}
+}
+
+// DefaultCounter is the counter used by Count and Instrument. Register it
+// with prometheus.MustRegister(errorsprom.DefaultCounter)
+var DefaultCounter = NewCounter(prometheus.CounterOpts{})
+
+// Count increments the default counter for an error
+func Count(err error) {
+	DefaultCounter.Count(err)
+}
+
+// Instrument counts errors with the default counter automatically,
+// returning a function that stops counting
+func Instrument() (remove func()) {
+	return DefaultCounter.Instrument()
+}
+
+// Count increments the counter for an error. The code label is the slug of
+// the code errors.ResolveCode picks, and the op comes from errors.OriginOf.
+// Ops become label values, so keep them to a small set of operation names.
+// nil errors aren't counted
+func (c *Counter) Count(err error) {
+	if err == nil {
+		return
+	}
+	code := errors.CodeGeneric
+	reg := errors.DefaultRegistry
+	if e, ok := errors.AsError(err); ok {
+		reg = e.Registry()
+		code = reg.Canonical(errors.ResolveCode(err))
+	}
+	c.vec.WithLabelValues(reg.CodeSlug(code), errors.OriginOf(err).Op, reg.CodeSeverity(code).String()).Inc()
+}
+
+// Instrument counts every error created by errors.New, errors.Wrap and their
+// variants with a hook registered with errors.RegisterHook. Each failure is
+// counted once, when it's first created: wrapping an error that already has
+// an *errors.Error in it's chain doesn't count it again. Series for every
+// registered code are created at zero, so rates are charted from the first
+// occurrence of an error. Instrumenting a counter more than once counts each
+// error once. Instrument returns a function that removes the hooks
+func (c *Counter) Instrument() (remove func()) {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	if c.remove != nil {
+		return c.remove
+	}
+
+	removeHook := errors.RegisterHook(func(e *errors.Error) {
+		if _, wrapped := errors.AsError(e.Cause()); !wrapped {
+			c.Count(e)
+		}
+	})
+	removeOnRegister := errors.OnRegister(func(code errors.Code, spec errors.CodeSpec) {
+		c.vec.WithLabelValues(errors.CodeSlug(code), "", spec.Severity.String())
+	})
+	c.remove = func() {
+		c.lk.Lock()
+		defer c.lk.Unlock()
+		if c.remove != nil {
+			removeHook()
+			removeOnRegister()
+			c.remove = nil
+		}
+	}
+	return c.remove
+}
+
+// Describe implements the prometheus.Collector interface
+func (c *Counter) Describe(ch chan<- *prometheus.Desc) {
+	c.vec.Describe(ch)
+}
+
+// Collect implements the prometheus.Collector interface
+func (c *Counter) Collect(ch chan<- prometheus.Metric) {
+	c.vec.Collect(ch)
+}
@@ -0,0 +1,61 @@
+package errorsprom
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/qri-io/errors"
+)
+
+func TestCount(t *testing.T) {
+	c := NewCounter(prometheus.CounterOpts{})
+	err := errors.New(errors.CodeNotFound, "no dataset").WithDetail(errors.OpDetail, "dsfs.LoadDataset")
+	c.Count(err)
+	c.Count(fmt.Errorf("wrapped: %w", err))
+	c.Count(fmt.Errorf("oh no"))
+	c.Count(nil)
+
+	if got := testutil.ToFloat64(c.vec.WithLabelValues("not_found", "dsfs.LoadDataset", "info")); got != 2 {
+		t.Errorf("not found count mismatch. expected: %d, got: %f", 2, got)
+	}
+	if got := testutil.ToFloat64(c.vec.WithLabelValues("generic", "", "error")); got != 1 {
+		t.Errorf("generic count mismatch. expected: %d, got: %f", 1, got)
+	}
+	if got := testutil.CollectAndCount(c); got != 2 {
+		t.Errorf("series count mismatch. expected: %d, got: %d", 2, got)
+	}
+}
+
+func TestInstrument(t *testing.T) {
+	c := NewCounter(prometheus.CounterOpts{})
+	remove := c.Instrument()
+	defer remove()
+	if again := c.Instrument(); again == nil {
+		t.Fatal("expected instrumenting twice to return a remove function")
+	}
+
+	if got := testutil.ToFloat64(c.vec.WithLabelValues("timeout", "", "error")); got != 0 {
+		t.Errorf("expected series for registered codes to start at zero. got: %f", got)
+	}
+
+	err := errors.New(errors.CodeTimeout, "peer took too long")
+	errors.Wrap(errors.CodeUnavailable, errors.Wrap(errors.CodeTimeout, err, "syncing"), "pulling")
+	if got := testutil.ToFloat64(c.vec.WithLabelValues("timeout", "", "error")); got != 1 {
+		t.Errorf("timeout count mismatch. expected: %d, got: %f", 1, got)
+	}
+	series := testutil.CollectAndCount(c)
+
+	remove()
+	if err := errors.RegisterCode(errors.Code(9100), 500, "errorsprom_removed"); err != nil {
+		t.Fatal(err)
+	}
+	if got := testutil.CollectAndCount(c); got != series {
+		t.Errorf("expected removing the hooks to stop creating series. expected: %d, got: %d", series, got)
+	}
+	errors.New(errors.CodeTimeout, "peer took too long")
+	if got := testutil.ToFloat64(c.vec.WithLabelValues("timeout", "", "error")); got != 1 {
+		t.Errorf("expected removing the hook to stop counting. got: %f", got)
+	}
+}
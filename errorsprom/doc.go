@@ -0,0 +1,4 @@
+// Package errorsprom counts github.com/qri-io/errors values with a
+// Prometheus counter labelled by code slug, op & severity, so dashboards can
+// chart error rates by category
+package errorsprom
@@ -0,0 +1,6 @@
+// Package errorsproto converts between github.com/qri-io/errors and the
+// protobuf messages in errorspb, keeping protobuf out of the errors package's
+// dependencies. Importing it registers an "application/protobuf" serializer.
+// Codes can also be declared as a proto enum, and registered or generated
+// from the enum's descriptor
+package errorsproto
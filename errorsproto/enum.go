@@ -0,0 +1,251 @@
+package errorsproto
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/qri-io/errors"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// EnumOptions configures how proto enum values map to codes
+type EnumOptions struct {
+	// Offset is added to enum numbers to form codes, and must place every
+	// value above MaxReservedCode
+	Offset errors.Code
+	// Prefix is trimmed from value names. Prefix defaults to the enum name in
+	// upper snake case followed by an underscore, eg: "ERROR_CODE_" for an
+	// enum named ErrorCode
+	Prefix string
+}
+
+// builtinCodeNames are the identifiers of built-in codes, used when
+// generating code
+var builtinCodeNames = map[errors.Code]string{
+	errors.CodeGeneric:            "CodeGeneric",
+	errors.CodeInvalidSyntax:      "CodeInvalidSyntax",
+	errors.CodeInvalidArgs:        "CodeInvalidArgs",
+	errors.CodeUnauthorized:       "CodeUnauthorized",
+	errors.CodeForbidden:          "CodeForbidden",
+	errors.CodeNotFound:           "CodeNotFound",
+	errors.CodeUnavailable:        "CodeUnavailable",
+	errors.CodeConflict:           "CodeConflict",
+	errors.CodeTooManyRequests:    "CodeTooManyRequests",
+	errors.CodeTimeout:            "CodeTimeout",
+	errors.CodeCancelled:          "CodeCancelled",
+	errors.CodeNotImplemented:     "CodeNotImplemented",
+	errors.CodePreconditionFailed: "CodePreconditionFailed",
+	errors.CodeGone:               "CodeGone",
+	errors.CodeInternal:           "CodeInternal",
+	errors.CodeUsage:              "CodeUsage",
+}
+
+// builtins holds the specs of built-in codes, which enum values may
+// descend from
+var builtins = errors.NewRegistry()
+
+// EnumCodes maps the values of a proto enum to code specs, keeping
+// taxonomies defined in proto files and the registry in lockstep. The zero
+// value, which proto3 reserves for "unspecified", is skipped. Slugs are the
+// lower-cased value names without the prefix. Values with a slug that is or
+// ends with the slug of a built-in code take that code as their parent, and
+// inherit it's http status, grpc code, severity, exit status, websocket close
+// status & retryability, so DATASET_NOT_FOUND descends from CodeNotFound.
+// Other values are internal server errors
+func EnumCodes(ed protoreflect.EnumDescriptor, opts EnumOptions) (map[errors.Code]errors.CodeSpec, error) {
+	prefix := opts.Prefix
+	if prefix == "" {
+		prefix = upperSnake(string(ed.Name())) + "_"
+	}
+
+	specs := map[errors.Code]errors.CodeSpec{}
+	values := ed.Values()
+	for i := 0; i < values.Len(); i++ {
+		v := values.Get(i)
+		if v.Number() == 0 {
+			continue
+		}
+		c := errors.Code(v.Number()) + opts.Offset
+		if c >= 0 && c <= errors.MaxReservedCode {
+			return nil, errors.New(errors.CodeInvalidArgs, "proto enum value maps to a reserved code", v.FullName(), c)
+		}
+		slug := strings.ToLower(strings.TrimPrefix(string(v.Name()), prefix))
+		spec := errors.CodeSpec{HTTPStatus: 500, Severity: errors.SeverityError}
+		if parent, ok := builtinParent(slug); ok {
+			spec, _ = builtins.Lookup(parent)
+			spec.Parent = parent
+			spec.DocsURL, spec.Friendly, spec.Fix = "", "", ""
+		}
+		spec.Name = strings.Replace(slug, "_", " ", -1)
+		spec.Slug = slug
+		specs[c] = spec
+	}
+	return specs, nil
+}
+
+// builtinParent finds the built-in code with the longest slug that slug is,
+// or ends with
+func builtinParent(slug string) (errors.Code, bool) {
+	var (
+		parent errors.Code
+		length int
+	)
+	for c := range builtinCodeNames {
+		s := builtins.CodeSlug(c)
+		if (slug == s || strings.HasSuffix(slug, "_"+s)) && len(s) > length {
+			parent, length = c, len(s)
+		}
+	}
+	return parent, length > 0
+}
+
+// upperSnake converts a CamelCase name to UPPER_SNAKE case
+func upperSnake(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) && i > 0 {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+// RegisterEnum registers the codes EnumCodes maps from a proto enum with r,
+// usually errors.DefaultRegistry
+func RegisterEnum(r *errors.Registry, ed protoreflect.EnumDescriptor, opts EnumOptions) error {
+	specs, err := EnumCodes(ed, opts)
+	if err != nil {
+		return err
+	}
+	return r.RegisterSpecs(specs)
+}
+
+// enumSource is the template for GenerateEnumCodes output
+var enumSource = template.Must(template.New("protoenum").Parse(`// Code generated from the {{ .Enum }} proto enum. DO NOT EDIT.
+
+package {{ .Package }}
+
+import "github.com/qri-io/errors"
+
+// codes of the {{ .Enum }} proto enum
+const (
+{{- range .Codes }}
+	// {{ .Ident }} {{ .Comment }}
+	{{ .Ident }} errors.Code = {{ .Code }}
+{{- end }}
+)
+
+func init() {
+	if err := errors.RegisterCodes(map[errors.Code]errors.CodeSpec{
+{{- range .Codes }}
+		{{ .Ident }}: {{ .Spec }},
+{{- end }}
+	}); err != nil {
+		panic(err)
+	}
+}
+`))
+
+// enumCode is a code in GenerateEnumCodes output
+type enumCode struct {
+	Ident   string
+	Comment string
+	Code    errors.Code
+	Spec    string
+}
+
+// GenerateEnumCodes writes Go source declaring a Code constant for each value
+// of a proto enum, and registering the specs EnumCodes maps from
+// the enum in an init function. Use it from protoc plugins or go:generate
+// programs to keep code constants in sync with proto files. Leading comments
+// on enum values are carried over when the descriptor includes source info
+func GenerateEnumCodes(w io.Writer, pkg string, ed protoreflect.EnumDescriptor, opts EnumOptions) error {
+	specs, err := EnumCodes(ed, opts)
+	if err != nil {
+		return err
+	}
+
+	codes := make([]enumCode, 0, len(specs))
+	values := ed.Values()
+	locs := ed.ParentFile().SourceLocations()
+	for i := 0; i < values.Len(); i++ {
+		v := values.Get(i)
+		c := errors.Code(v.Number()) + opts.Offset
+		spec, ok := specs[c]
+		if !ok {
+			continue
+		}
+		comment := "is the " + string(v.Name()) + " enum value"
+		if lead := strings.TrimSpace(locs.ByDescriptor(v).LeadingComments); lead != "" {
+			comment = strings.Replace(lead, "\n", " ", -1)
+		}
+		codes = append(codes, enumCode{
+			Ident:   "Code" + camel(spec.Slug),
+			Comment: comment,
+			Code:    c,
+			Spec:    specLiteral(spec),
+		})
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i].Code < codes[j].Code })
+
+	buf := &bytes.Buffer{}
+	if err := enumSource.Execute(buf, map[string]interface{}{
+		"Package": pkg,
+		"Enum":    ed.FullName(),
+		"Codes":   codes,
+	}); err != nil {
+		return err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// camel converts a snake_case slug to CamelCase
+func camel(slug string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(slug, "_") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// specLiteral writes a spec as a Go composite literal
+func specLiteral(spec errors.CodeSpec) string {
+	sev := spec.Severity.String()
+	fields := []string{
+		fmt.Sprintf("HTTPStatus: %d", spec.HTTPStatus),
+		fmt.Sprintf("Name: %q", spec.Name),
+		fmt.Sprintf("Slug: %q", spec.Slug),
+		fmt.Sprintf("Severity: errors.Severity%s", strings.ToUpper(sev[:1])+sev[1:]),
+	}
+	if spec.Retryable {
+		fields = append(fields, "Retryable: true")
+	}
+	if spec.GRPCCode != 0 {
+		fields = append(fields, fmt.Sprintf("GRPCCode: %d", spec.GRPCCode))
+	}
+	if spec.ExitStatus != 0 {
+		fields = append(fields, fmt.Sprintf("ExitStatus: %d", spec.ExitStatus))
+	}
+	if spec.WSCloseStatus != 0 {
+		fields = append(fields, fmt.Sprintf("WSCloseStatus: %d", spec.WSCloseStatus))
+	}
+	if name, ok := builtinCodeNames[spec.Parent]; ok {
+		fields = append(fields, "Parent: errors."+name)
+	}
+	return "{" + strings.Join(fields, ", ") + "}"
+}
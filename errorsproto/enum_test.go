@@ -0,0 +1,96 @@
+package errorsproto
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// testErrorCodeEnum builds a proto enum descriptor for tests
+func testErrorCodeEnum(t *testing.T) protoreflect.EnumDescriptor {
+	t.Helper()
+	value := func(name string, n int32) *descriptorpb.EnumValueDescriptorProto {
+		return &descriptorpb.EnumValueDescriptorProto{Name: proto.String(name), Number: proto.Int32(n)}
+	}
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("qri/codes.proto"),
+		Package: proto.String("qri"),
+		Syntax:  proto.String("proto3"),
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("ErrorCode"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				value("ERROR_CODE_UNSPECIFIED", 0),
+				value("ERROR_CODE_DATASET_NOT_FOUND", 1),
+				value("ERROR_CODE_PEER_UNAVAILABLE", 2),
+				value("ERROR_CODE_INVALID_STRUCTURE", 3),
+			},
+		}},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fd.Enums().Get(0)
+}
+
+func TestEnumCodes(t *testing.T) {
+	specs, err := EnumCodes(testErrorCodeEnum(t), EnumOptions{Offset: 200})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(specs) != 3 {
+		t.Fatalf("expected the unspecified value to be skipped. got: %d specs", len(specs))
+	}
+	cases := []struct {
+		code   errors.Code
+		slug   string
+		status int
+		parent errors.Code
+	}{
+		{201, "dataset_not_found", 404, errors.CodeNotFound},
+		{202, "peer_unavailable", 503, errors.CodeUnavailable},
+		{203, "invalid_structure", 500, errors.CodeUnknown},
+	}
+	for _, c := range cases {
+		spec := specs[c.code]
+		if spec.Slug != c.slug || spec.HTTPStatus != c.status || spec.Parent != c.parent {
+			t.Errorf("spec mismatch for %d. expected: %s %d %d, got: %s %d %d", c.code, c.slug, c.status, c.parent, spec.Slug, spec.HTTPStatus, spec.Parent)
+		}
+	}
+
+	if _, err := EnumCodes(testErrorCodeEnum(t), EnumOptions{}); !errors.IsCode(err, errors.CodeInvalidArgs) {
+		t.Errorf("expected reserved codes to be rejected. got: %v", err)
+	}
+
+	r := errors.NewRegistry()
+	if err := RegisterEnum(r, testErrorCodeEnum(t), EnumOptions{Offset: 200}); err != nil {
+		t.Fatal(err)
+	}
+	if !r.IsInCategory(errors.Code(201), errors.CodeNotFound) {
+		t.Errorf("expected dataset_not_found to descend from not_found")
+	}
+}
+
+func TestGenerateEnumCodes(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := GenerateEnumCodes(buf, "qerr", testErrorCodeEnum(t), EnumOptions{Offset: 200}); err != nil {
+		t.Fatal(err)
+	}
+	src := buf.String()
+	expect := []string{
+		"package qerr",
+		"CodeDatasetNotFound errors.Code = 201",
+		`{HTTPStatus: 404, Name: "dataset not found", Slug: "dataset_not_found", Severity: errors.SeverityInfo, GRPCCode: 5, ExitStatus: 4, Parent: errors.CodeNotFound}`,
+		`{HTTPStatus: 500, Name: "invalid structure", Slug: "invalid_structure", Severity: errors.SeverityError}`,
+	}
+	for _, e := range expect {
+		if !strings.Contains(src, e) {
+			t.Errorf("expected generated source to contain %q. got:\n%s", e, src)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+module github.com/qri-io/errors/errorsproto
+
+go 1.21
+
+require (
+	github.com/qri-io/errors v0.0.0-00010101000000-000000000000
+	github.com/qri-io/errors/errorspb v0.0.0-00010101000000-000000000000
+	google.golang.org/protobuf v1.36.5
+)
+
+require (
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace (
+	github.com/qri-io/errors => ../
+	github.com/qri-io/errors/errorspb => ../errorspb
+)
@@ -0,0 +1,154 @@
+package errorsproto
+
+import (
+	"encoding/json"
+
+	"github.com/qri-io/errors"
+	"github.com/qri-io/errors/errorspb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ContentType is the media type of protobuf errors
+const ContentType = "application/protobuf"
+
+// maxDepth caps the number of nested causes ToProto writes
+const maxDepth = 32
+
+// wireError is the JSON form of an error and it's causes, which errors
+// convert through
+type wireError struct {
+	Code     int64                  `json:"code"`
+	Slug     string                 `json:"slug,omitempty"`
+	Message  string                 `json:"message"`
+	Friendly string                 `json:"friendly,omitempty"`
+	Fix      string                 `json:"fix,omitempty"`
+	Field    string                 `json:"field,omitempty"`
+	Data     []interface{}          `json:"data,omitempty"`
+	Details  map[string]interface{} `json:"details,omitempty"`
+	Cause    *wireError             `json:"cause,omitempty"`
+	Causes   []wireError            `json:"causes,omitempty"`
+}
+
+// ToProto converts an error and it's chain of causes to a protobuf message.
+// Data & detail values are converted through their JSON representation.
+// nil errors convert to nil
+func ToProto(err error) (*errorspb.Error, error) {
+	if err == nil {
+		return nil, nil
+	}
+	data, merr := errors.MarshalJSONChain(err, maxDepth)
+	if merr != nil {
+		return nil, merr
+	}
+	we := wireError{}
+	if err := json.Unmarshal(data, &we); err != nil {
+		return nil, err
+	}
+	return we.proto()
+}
+
+// FromProto converts a protobuf message created by ToProto back to an Error,
+// including it's chain of causes. nil messages convert to nil
+func FromProto(pb *errorspb.Error) *errors.Error {
+	if pb == nil {
+		return nil
+	}
+	e := &errors.Error{}
+	data, err := json.Marshal(fromProto(pb))
+	if err == nil {
+		err = json.Unmarshal(data, e)
+	}
+	if err != nil {
+		// values JSON can't represent, like NaN, keep the code & message
+		return errors.New(errors.Code(pb.GetCode()), pb.GetMessage())
+	}
+	return e
+}
+
+// proto converts a serialized error to a protobuf message
+func (we wireError) proto() (*errorspb.Error, error) {
+	pb := &errorspb.Error{
+		Code:     we.Code,
+		Slug:     we.Slug,
+		Message:  we.Message,
+		Friendly: we.Friendly,
+		Fix:      we.Fix,
+		Field:    we.Field,
+	}
+	for _, d := range we.Data {
+		v, err := structpb.NewValue(d)
+		if err != nil {
+			return nil, err
+		}
+		pb.Data = append(pb.Data, v)
+	}
+	if len(we.Details) > 0 {
+		s, err := structpb.NewStruct(we.Details)
+		if err != nil {
+			return nil, err
+		}
+		pb.Details = s
+	}
+
+	if we.Cause != nil {
+		cause, err := we.Cause.proto()
+		if err != nil {
+			return nil, err
+		}
+		pb.Cause = cause
+	}
+	for _, c := range we.Causes {
+		cause, err := c.proto()
+		if err != nil {
+			return nil, err
+		}
+		pb.Causes = append(pb.Causes, cause)
+	}
+	return pb, nil
+}
+
+// fromProto converts a protobuf message to a serialized error
+func fromProto(pb *errorspb.Error) wireError {
+	we := wireError{
+		Code:     pb.GetCode(),
+		Slug:     pb.GetSlug(),
+		Message:  pb.GetMessage(),
+		Friendly: pb.GetFriendly(),
+		Fix:      pb.GetFix(),
+		Field:    pb.GetField(),
+	}
+	for _, v := range pb.GetData() {
+		we.Data = append(we.Data, v.AsInterface())
+	}
+	if pb.GetDetails() != nil {
+		we.Details = pb.GetDetails().AsMap()
+	}
+	if pb.GetCause() != nil {
+		cause := fromProto(pb.GetCause())
+		we.Cause = &cause
+	}
+	for _, c := range pb.GetCauses() {
+		we.Causes = append(we.Causes, fromProto(c))
+	}
+	return we
+}
+
+func init() {
+	errors.RegisterSerializer(ContentType, errors.SerializerFuncs{
+		MarshalFunc: func(e *errors.Error) ([]byte, error) {
+			pb, err := ToProto(e)
+			if err != nil {
+				return nil, err
+			}
+			return proto.Marshal(pb)
+		},
+		UnmarshalFunc: func(data []byte) (*errors.Error, error) {
+			pb := &errorspb.Error{}
+			if err := proto.Unmarshal(data, pb); err != nil {
+				return nil, err
+			}
+			return FromProto(pb), nil
+		},
+	})
+}
@@ -0,0 +1,99 @@
+package errorsproto
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/qri-io/errors"
+	"github.com/qri-io/errors/errorspb"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestProtoRoundTrip(t *testing.T) {
+	base := errors.NewFriendly(errors.CodeNotFound, "no dataset", "couldn't find that dataset", "b5/world_bank", 2).
+		WithField("ref").
+		WithDetail("peer", map[string]interface{}{"id": "QmPeer"})
+	e := errors.Wrap(errors.CodeUnavailable, fmt.Errorf("fetching: %w", base), "loading")
+
+	pb, err := ToProto(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := proto.Marshal(pb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &errorspb.Error{}
+	if err := proto.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	got := FromProto(decoded)
+	if got.Code() != errors.CodeUnavailable || got.Error() != e.Error() {
+		t.Errorf("round trip mismatch. expected: %s, got: %s", e.Error(), got.Error())
+	}
+	if !errors.IsCode(got, errors.CodeNotFound) {
+		t.Fatal("expected decoded chain to contain CodeNotFound")
+	}
+
+	inner := findCode(got, errors.CodeNotFound)
+	if inner == nil {
+		t.Fatal("expected to find the CodeNotFound error in the decoded chain")
+	}
+	if inner.Friendly() != "missing: couldn't find that dataset b5/world_bank, 2." {
+		t.Errorf("friendly mismatch. got: %s", inner.Friendly())
+	}
+	if inner.Field() != "ref" {
+		t.Errorf("field mismatch. expected: ref, got: %s", inner.Field())
+	}
+	peer, _ := inner.Details()["peer"].(map[string]interface{})
+	if peer["id"] != "QmPeer" {
+		t.Errorf("detail mismatch. got: %v", inner.Details())
+	}
+
+	if pb, err := ToProto(nil); pb != nil || err != nil {
+		t.Errorf("expected nil error to convert to nil")
+	}
+	if FromProto(nil) != nil {
+		t.Errorf("expected nil message to convert to nil")
+	}
+}
+
+func TestProtoSerializer(t *testing.T) {
+	s, ok := errors.SerializerFor(ContentType)
+	if !ok {
+		t.Fatalf("expected importing errorsproto to register a serializer for %s", ContentType)
+	}
+	e := errors.NewFriendly(errors.CodeNotFound, "no dataset", "couldn't find that dataset").WithField("ref")
+	data, err := s.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.Unmarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Code() != e.Code() || got.Friendly() != e.Friendly() || got.Field() != e.Field() {
+		t.Errorf("round trip mismatch. expected: %s, got: %s", e.Friendly(), got.Friendly())
+	}
+}
+
+// findCode walks err's chain for an *errors.Error with code c
+func findCode(err error, c errors.Code) *errors.Error {
+	if e, ok := err.(*errors.Error); ok && e.Code() == c {
+		return e
+	}
+	switch x := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, cause := range x.Unwrap() {
+			if e := findCode(cause, c); e != nil {
+				return e
+			}
+		}
+	case interface{ Unwrap() error }:
+		if cause := x.Unwrap(); cause != nil {
+			return findCode(cause, c)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,5 @@
+// Package errorsrollbar reports github.com/qri-io/errors values to Rollbar.
+// Reporters take any client with the ErrorWithExtrasAndContext method of
+// github.com/rollbar/rollbar-go's *Client, so this package doesn't depend on
+// the rollbar SDK
+package errorsrollbar
@@ -0,0 +1,72 @@
+package errorsrollbar
+
+import (
+	"context"
+
+	"github.com/qri-io/errors"
+)
+
+// rollbar levels, matching the level constants of rollbar-go
+const (
+	LevelCritical = "critical"
+	LevelError    = "error"
+	LevelWarning  = "warning"
+	LevelInfo     = "info"
+	LevelDebug    = "debug"
+)
+
+// Client sends errors to rollbar. *rollbar.Client from
+// github.com/rollbar/rollbar-go satisfies Client
+type Client interface {
+	ErrorWithExtrasAndContext(ctx context.Context, level string, err error, extras map[string]interface{})
+}
+
+// Reporter is an errors.Reporter sending errors to rollbar with a level from
+// the severity of the code errors.ResolveCode picks. The code, slug, op &
+// fingerprint are sent as extras, along with details under a "details" key
+type Reporter struct {
+	Client Client
+}
+
+// Report implements the errors.Reporter interface
+func (r Reporter) Report(ctx context.Context, e *errors.Error) {
+	r.Client.ErrorWithExtrasAndContext(ctx, Level(e), e, Extras(e))
+}
+
+// Level returns the rollbar level for an error
+func Level(e *errors.Error) string {
+	reg := e.Registry()
+	switch reg.CodeSeverity(reg.Canonical(errors.ResolveCode(e))) {
+	case errors.SeverityCritical:
+		return LevelCritical
+	case errors.SeverityWarning:
+		return LevelWarning
+	case errors.SeverityInfo:
+		return LevelInfo
+	case errors.SeverityDebug:
+		return LevelDebug
+	default:
+		return LevelError
+	}
+}
+
+// Extras returns the rollbar extras for an error
+func Extras(e *errors.Error) map[string]interface{} {
+	reg := e.Registry()
+	code := reg.Canonical(errors.ResolveCode(e))
+	extras := map[string]interface{}{
+		"code":        int(code),
+		"slug":        reg.CodeSlug(code),
+		"fingerprint": errors.Fingerprint(e),
+	}
+	if op := errors.OriginOf(e).Op; op != "" {
+		extras["op"] = op
+	}
+	if friendly := e.FriendlyMessage(); friendly != "" {
+		extras["friendly"] = friendly
+	}
+	if details := e.Details(); len(details) > 0 {
+		extras["details"] = details
+	}
+	return extras
+}
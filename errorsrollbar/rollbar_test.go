@@ -0,0 +1,37 @@
+package errorsrollbar
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qri-io/errors"
+)
+
+// client records errors instead of sending them
+type client struct {
+	levels []string
+	extras []map[string]interface{}
+}
+
+func (c *client) ErrorWithExtrasAndContext(ctx context.Context, level string, err error, extras map[string]interface{}) {
+	c.levels = append(c.levels, level)
+	c.extras = append(c.extras, extras)
+}
+
+func TestReporter(t *testing.T) {
+	c := &client{}
+	r := Reporter{Client: c}
+	ctx := context.Background()
+	r.Report(ctx, errors.New(errors.CodeInternal, "index out of range").WithDetail(errors.OpDetail, "dsfs.LoadDataset"))
+	r.Report(ctx, errors.New(errors.CodeInvalidArgs, "bad ref").WithDetail("ref", "b5"))
+
+	if len(c.levels) != 2 || c.levels[0] != LevelCritical || c.levels[1] != LevelWarning {
+		t.Errorf("levels mismatch. expected: [%s %s], got: %v", LevelCritical, LevelWarning, c.levels)
+	}
+	if c.extras[0]["slug"] != "internal" || c.extras[0]["op"] != "dsfs.LoadDataset" {
+		t.Errorf("extras mismatch. got: %v", c.extras[0])
+	}
+	if details, ok := c.extras[1]["details"].(map[string]interface{}); !ok || details["ref"] != "b5" {
+		t.Errorf("details mismatch. got: %v", c.extras[1]["details"])
+	}
+}
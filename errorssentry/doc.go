@@ -0,0 +1,3 @@
+// Package errorssentry reports github.com/qri-io/errors values to Sentry,
+// converting errors to events grouped by errors.Fingerprint
+package errorssentry
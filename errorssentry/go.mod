@@ -0,0 +1,19 @@
+module github.com/qri-io/errors/errorssentry
+
+go 1.21
+
+require (
+	github.com/getsentry/sentry-go v0.29.0
+	github.com/qri-io/errors v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/google/go-cmp v0.6.0 // indirect
+	github.com/stretchr/testify v1.9.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace (
+	github.com/qri-io/errors => ../
+)
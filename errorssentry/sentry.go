@@ -0,0 +1,80 @@
+package errorssentry
+
+import (
+	"context"
+	"runtime"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/qri-io/errors"
+)
+
+// levels maps severities to sentry levels
+var levels = map[errors.Severity]sentry.Level{
+	errors.SeverityDebug:    sentry.LevelDebug,
+	errors.SeverityInfo:     sentry.LevelInfo,
+	errors.SeverityWarning:  sentry.LevelWarning,
+	errors.SeverityError:    sentry.LevelError,
+	errors.SeverityCritical: sentry.LevelFatal,
+}
+
+// Event converts an error to a sentry event. The fingerprint comes from
+// errors.Fingerprint, and the level from the severity of the code
+// errors.ResolveCode picks. The slug of the code & the op from
+// errors.OriginOf are set as "code" & "op" tags, details become extra data,
+// and the stack captured when the error was created becomes the exception's
+// stack trace
+func Event(e *errors.Error) *sentry.Event {
+	reg := e.Registry()
+	code := reg.Canonical(errors.ResolveCode(e))
+	slug := reg.CodeSlug(code)
+
+	ev := sentry.NewEvent()
+	ev.Level = sentry.LevelError
+	if level, ok := levels[reg.CodeSeverity(code)]; ok {
+		ev.Level = level
+	}
+	ev.Message = e.Error()
+	ev.Fingerprint = []string{errors.Fingerprint(e)}
+	ev.Tags["code"] = slug
+	if op := errors.OriginOf(e).Op; op != "" {
+		ev.Tags["op"] = op
+	}
+	for key, val := range e.Details() {
+		ev.Extra[key] = val
+	}
+	if data := e.Data(); len(data) > 0 {
+		ev.Extra["data"] = data
+	}
+
+	exception := sentry.Exception{Type: slug, Value: e.Message()}
+	if frames := e.StackTrace(); len(frames) > 0 {
+		st := &sentry.Stacktrace{Frames: make([]sentry.Frame, 0, len(frames))}
+		// sentry lists frames from the outermost call to the innermost
+		for i := len(frames) - 1; i >= 0; i-- {
+			f := frames[i]
+			st.Frames = append(st.Frames, sentry.NewFrame(runtime.Frame{Function: f.Function, File: f.File, Line: f.Line}))
+		}
+		exception.Stacktrace = st
+	}
+	ev.Exception = []sentry.Exception{exception}
+	return ev
+}
+
+// Reporter is an errors.Reporter sending errors to sentry. Errors are
+// captured with the hub of the reported context, falling back to Hub, then
+// the current hub
+type Reporter struct {
+	Hub *sentry.Hub
+}
+
+// Report implements the errors.Reporter interface
+func (r Reporter) Report(ctx context.Context, e *errors.Error) {
+	hub := sentry.GetHubFromContext(ctx)
+	if hub == nil {
+		hub = r.Hub
+	}
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+	hub.CaptureEvent(Event(e))
+}
@@ -0,0 +1,66 @@
+package errorssentry
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/qri-io/errors"
+)
+
+func TestEvent(t *testing.T) {
+	e := errors.New(errors.CodeInternal, "index out of range").
+		WithDetail(errors.OpDetail, "dsfs.LoadDataset").
+		WithDetail("ref", "b5/world_bank")
+	ev := Event(e)
+
+	if ev.Level != sentry.LevelFatal {
+		t.Errorf("level mismatch. expected: %s, got: %s", sentry.LevelFatal, ev.Level)
+	}
+	if len(ev.Fingerprint) != 1 || ev.Fingerprint[0] != errors.Fingerprint(e) {
+		t.Errorf("fingerprint mismatch. expected: %s, got: %v", errors.Fingerprint(e), ev.Fingerprint)
+	}
+	if ev.Tags["code"] != "internal" || ev.Tags["op"] != "dsfs.LoadDataset" {
+		t.Errorf("tags mismatch. got: %v", ev.Tags)
+	}
+	if ev.Extra["ref"] != "b5/world_bank" {
+		t.Errorf("extra mismatch. got: %v", ev.Extra)
+	}
+	frames := ev.Exception[0].Stacktrace.Frames
+	if last := frames[len(frames)-1]; !strings.HasSuffix(last.Function, "TestEvent") {
+		t.Errorf("expected the innermost frame to be last. got: %s", last.Function)
+	}
+}
+
+// transport records events instead of sending them
+type transport struct {
+	events []*sentry.Event
+}
+
+func (t *transport) Flush(timeout time.Duration) bool       { return true }
+func (t *transport) Configure(options sentry.ClientOptions) {}
+func (t *transport) SendEvent(event *sentry.Event)          { t.events = append(t.events, event) }
+
+func TestReporter(t *testing.T) {
+	tr := &transport{}
+	client, err := sentry.NewClient(sentry.ClientOptions{Dsn: "https://key@sentry.example.com/1", Transport: tr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hub := sentry.NewHub(client, sentry.NewScope())
+
+	Reporter{}.Report(sentry.SetHubOnContext(context.Background(), hub), errors.New(errors.CodeNotFound, "no dataset"))
+	if len(tr.events) != 1 {
+		t.Fatalf("expected one event to be sent. got: %d", len(tr.events))
+	}
+	if tr.events[0].Level != sentry.LevelInfo {
+		t.Errorf("level mismatch. expected: %s, got: %s", sentry.LevelInfo, tr.events[0].Level)
+	}
+
+	Reporter{Hub: hub}.Report(context.Background(), errors.New(errors.CodeTimeout, "peer took too long"))
+	if len(tr.events) != 2 {
+		t.Errorf("expected the reporter hub to be used without a context hub. got: %d events", len(tr.events))
+	}
+}
@@ -0,0 +1,3 @@
+// Package errorsterm renders github.com/qri-io/errors for terminals, with
+// ANSI colors when writing to a TTY
+package errorsterm
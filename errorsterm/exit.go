@@ -0,0 +1,22 @@
+package errorsterm
+
+import (
+	"io"
+	"os"
+
+	"github.com/qri-io/errors"
+)
+
+// stderr & exit are replaced in tests
+var (
+	stderr io.Writer = os.Stderr
+	exit             = os.Exit
+)
+
+// Exit prints err to stderr with a Renderer and exits with the status
+// errors.ExitCode maps err's code to, so scripts can tell failures apart by
+// exit status. Exit(nil) exits 0 without printing
+func Exit(err error) {
+	NewRenderer(stderr).Print(err)
+	exit(errors.ExitCode(err))
+}
@@ -0,0 +1,31 @@
+package errorsterm
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/qri-io/errors"
+)
+
+func TestExit(t *testing.T) {
+	buf := &bytes.Buffer{}
+	status := -1
+	prevStderr, prevExit := stderr, exit
+	stderr = buf
+	exit = func(code int) { status = code }
+	defer func() { stderr, exit = prevStderr, prevExit }()
+
+	Exit(errors.NewFriendly(errors.CodeNotFound, "no dataset", "couldn't find that dataset"))
+	if status != 4 {
+		t.Errorf("status mismatch. expected: %d, got: %d", 4, status)
+	}
+	if expect := "Error: couldn't find that dataset\n"; buf.String() != expect {
+		t.Errorf("output mismatch. expected: %q, got: %q", expect, buf.String())
+	}
+
+	buf.Reset()
+	Exit(nil)
+	if status != 0 || buf.Len() != 0 {
+		t.Errorf("expected nil errors to exit 0 silently. got status: %d, output: %q", status, buf.String())
+	}
+}
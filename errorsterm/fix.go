@@ -0,0 +1,34 @@
+package errorsterm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/qri-io/errors"
+)
+
+// ConfirmFixCommands asks the user whether to run each of an error's fix
+// commands, reading answers from in and writing prompts to out. The commands
+// the user confirms are returned in order, for the caller to run. Answers
+// other than "y" or "yes" decline. Reading stops at the end of input
+func ConfirmFixCommands(in io.Reader, out io.Writer, err error) []string {
+	e, ok := errors.AsError(err)
+	if !ok {
+		return nil
+	}
+	var confirmed []string
+	s := bufio.NewScanner(in)
+	for _, cmd := range e.FixCommands() {
+		fmt.Fprintf(out, "run %q? [y/N] ", cmd)
+		if !s.Scan() {
+			break
+		}
+		switch strings.ToLower(strings.TrimSpace(s.Text())) {
+		case "y", "yes":
+			confirmed = append(confirmed, cmd)
+		}
+	}
+	return confirmed
+}
@@ -0,0 +1,48 @@
+package errorsterm
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/errors"
+)
+
+func TestRenderFixCommands(t *testing.T) {
+	r := &Renderer{Width: 20}
+	err := errors.NewFriendly(errors.CodeUnavailable, "no connection", "you're not connected").
+		WithFixCommands("qri connect --setup --registry https://registry.qri.cloud")
+	expect := "Error: you're not\n" +
+		"       connected\n" +
+		"  please try again\n" +
+		"  later\n" +
+		"  try: qri connect --setup --registry https://registry.qri.cloud\n"
+	if got := r.Render(err); got != expect {
+		t.Errorf("output mismatch.\nexpected:\n%s\ngot:\n%s", expect, got)
+	}
+
+	err.WithFixCommands("qri setup", "qri connect")
+	expect = "Error: you're not\n" +
+		"       connected\n" +
+		"  please try again\n" +
+		"  later\n" +
+		"  try:\n" +
+		"    qri setup\n" +
+		"    qri connect\n"
+	if got := r.Render(err); got != expect {
+		t.Errorf("output mismatch.\nexpected:\n%s\ngot:\n%s", expect, got)
+	}
+}
+
+func TestConfirmFixCommands(t *testing.T) {
+	err := errors.New(errors.CodeUnavailable, "no connection").WithFixCommands("qri setup", "qri connect", "qri list")
+	out := &bytes.Buffer{}
+	got := ConfirmFixCommands(strings.NewReader("n\nYes\n"), out, err)
+	if len(got) != 1 || got[0] != "qri connect" {
+		t.Errorf("confirmed mismatch. expected: %v, got: %v", []string{"qri connect"}, got)
+	}
+	expect := `run "qri setup"? [y/N] run "qri connect"? [y/N] run "qri list"? [y/N] `
+	if out.String() != expect {
+		t.Errorf("prompt mismatch.\nexpected: %q\ngot:      %q", expect, out.String())
+	}
+}
@@ -0,0 +1,17 @@
+module github.com/qri-io/errors/errorsterm
+
+go 1.21
+
+require (
+	github.com/qri-io/errors v0.0.0-00010101000000-000000000000
+	golang.org/x/term v0.21.0
+)
+
+require (
+	golang.org/x/sys v0.21.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace (
+	github.com/qri-io/errors => ../
+)
@@ -0,0 +1,261 @@
+package errorsterm
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/qri-io/errors"
+	"golang.org/x/term"
+)
+
+// ANSI escape sequences
+const (
+	Reset  = "\x1b[0m"
+	Bold   = "\x1b[1m"
+	Dim    = "\x1b[2m"
+	Red    = "\x1b[31m"
+	Yellow = "\x1b[33m"
+	Blue   = "\x1b[34m"
+)
+
+// Colors maps code categories to the color errors in the category are
+// printed in. Codes are matched with IsInCategory in no particular order, so
+// categories shouldn't overlap. Errors outside every category are red
+var Colors = map[errors.Code]string{
+	errors.CodeUnauthorized: Yellow,
+	errors.CodeForbidden:    Yellow,
+	errors.CodeNotFound:     Blue,
+	errors.CodeGone:         Blue,
+	errors.CodeInternal:     Red,
+}
+
+// DefaultWidth is the width text is wrapped to when the terminal width can't
+// be detected
+const DefaultWidth = 80
+
+// indent prefixes the sections that follow the banner
+const indent = "  "
+
+// Renderer prints errors for people reading a terminal. Output starts with an
+// "Error:" banner in the color of the code's category, followed by the
+// friendly message in bold. The fix is dimmed and indented on the following
+// lines, followed by fix commands. Errors without a friendly message print
+// their message instead. Higher verbosity adds sections for debugging. Text
+// is wrapped to fit the terminal, except for fix commands & stack traces,
+// which are never wrapped so they can be copied
+type Renderer struct {
+	w io.Writer
+	// Color enables ANSI colors
+	Color bool
+	// Width is the column text is wrapped at. Zero disables wrapping
+	Width int
+	// Verbosity escalates the detail printed. 0 prints the friendly message &
+	// fix, 1 adds the machine message & chain of ops, and 2 adds data,
+	// details & the stack trace. Command line programs usually map -v & -vv
+	// flags to verbosity
+	Verbosity int
+}
+
+// NewRenderer creates a renderer writing to w. Colors are enabled when w is
+// a terminal, unless the NO_COLOR environment variable is set or TERM is
+// "dumb". Width is the terminal's width, falling back to the COLUMNS
+// environment variable, then DefaultWidth
+func NewRenderer(w io.Writer) *Renderer {
+	return &Renderer{w: w, Color: colorTerminal(w), Width: terminalWidth(w)}
+}
+
+// terminalWidth detects the width of the terminal w writes to
+func terminalWidth(w io.Writer) int {
+	if f, ok := w.(*os.File); ok {
+		if width, _, err := term.GetSize(int(f.Fd())); err == nil && width > 0 {
+			return width
+		}
+	}
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+	return DefaultWidth
+}
+
+// colorTerminal reports whether w is a terminal that should get colors
+func colorTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return false
+	}
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	return os.Getenv("TERM") != "dumb"
+}
+
+// Print writes an error. nil errors write nothing
+func (r *Renderer) Print(err error) error {
+	if err == nil {
+		return nil
+	}
+	_, werr := io.WriteString(r.w, r.Render(err))
+	return werr
+}
+
+// Render formats an error, ending with a newline
+func (r *Renderer) Render(err error) string {
+	if err == nil {
+		return ""
+	}
+	const banner = "Error:"
+	e, ok := errors.AsError(err)
+	if !ok {
+		return r.section(Red+Bold, banner, Bold, err.Error(), "")
+	}
+	reg := e.Registry()
+	code := reg.Canonical(errors.ResolveCode(err))
+	msg := e.FriendlyMessage()
+	if msg == "" {
+		msg = e.Message()
+	}
+
+	b := &strings.Builder{}
+	b.WriteString(r.section(CategoryColor(reg, code)+Bold, banner, Bold, msg, ""))
+	if fix := e.Fix(); fix != "" {
+		b.WriteString(r.section("", "", Dim, fix, indent))
+	}
+	b.WriteString(r.fixCommands(e.FixCommands()))
+	if r.Verbosity >= 1 {
+		b.WriteString(r.section(Dim, "message:", "", err.Error(), indent))
+		if ops := errors.Ops(err); len(ops) > 0 {
+			b.WriteString(r.section(Dim, "op:", "", strings.Join(ops, " > "), indent))
+		}
+	}
+	if r.Verbosity >= 2 {
+		b.WriteString(r.dump(e))
+	}
+	return b.String()
+}
+
+// dump formats data, details & the stack trace of an error
+func (r *Renderer) dump(e *errors.Error) string {
+	b := &strings.Builder{}
+	if data := e.Data(); len(data) > 0 {
+		b.WriteString(indent + r.style(Dim, "data:") + "\n")
+		for _, d := range data {
+			b.WriteString(r.section("", "", "", fmt.Sprintf("%+v", d), indent+indent))
+		}
+	}
+	if details := e.Details(); len(details) > 0 {
+		keys := make([]string, 0, len(details))
+		for key := range details {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		b.WriteString(indent + r.style(Dim, "details:") + "\n")
+		for _, key := range keys {
+			b.WriteString(r.section(Dim, key+":", "", fmt.Sprintf("%+v", details[key]), indent+indent))
+		}
+	}
+	if frames := e.StackTrace(); len(frames) > 0 {
+		b.WriteString(indent + r.style(Dim, "stack:") + "\n")
+		for _, f := range frames {
+			fmt.Fprintf(b, "%s%s\n%s%s\n", indent+indent, f.Function, indent+indent+indent, r.style(Dim, fmt.Sprintf("%s:%d", f.File, f.Line)))
+		}
+	}
+	return b.String()
+}
+
+// fixCommands formats fix commands under a "try:" label. A single command is
+// written on the label's line, and several as an indented block
+func (r *Renderer) fixCommands(cmds []string) string {
+	switch len(cmds) {
+	case 0:
+		return ""
+	case 1:
+		return indent + r.style(Dim, "try:") + " " + r.style(Bold, cmds[0]) + "\n"
+	}
+	b := &strings.Builder{}
+	b.WriteString(indent + r.style(Dim, "try:") + "\n")
+	for _, cmd := range cmds {
+		b.WriteString(indent + indent + r.style(Bold, cmd) + "\n")
+	}
+	return b.String()
+}
+
+// section formats a labelled block of text, wrapping it to r.Width. Wrapped
+// lines are indented to align with the first line's text
+func (r *Renderer) section(labelStyle, label, textStyle, text, prefix string) string {
+	first := prefix
+	if label != "" {
+		first += r.style(labelStyle, label) + " "
+	}
+	hang := prefix
+	if label != "" {
+		hang += strings.Repeat(" ", utf8.RuneCountInString(label)+1)
+	}
+
+	b := &strings.Builder{}
+	for i, line := range wrap(text, r.Width-len(hang)) {
+		if i == 0 {
+			b.WriteString(first)
+		} else {
+			b.WriteString(hang)
+		}
+		b.WriteString(r.style(textStyle, line))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// wrap splits text into lines no wider than width, breaking on spaces. Words
+// longer than width get a line of their own. Existing line breaks are kept.
+// A width under one disables wrapping
+func wrap(text string, width int) []string {
+	var lines []string
+	for _, para := range strings.Split(text, "\n") {
+		if width < 1 {
+			lines = append(lines, para)
+			continue
+		}
+		line, n := "", 0
+		for _, word := range strings.Fields(para) {
+			wn := utf8.RuneCountInString(word)
+			switch {
+			case n == 0:
+				line, n = word, wn
+			case n+1+wn <= width:
+				line, n = line+" "+word, n+1+wn
+			default:
+				lines = append(lines, line)
+				line, n = word, wn
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// style wraps s in an escape sequence when colors are enabled
+func (r *Renderer) style(seq, s string) string {
+	if !r.Color || seq == "" {
+		return s
+	}
+	return seq + s + Reset
+}
+
+// CategoryColor returns the color for a code from Colors, defaulting to red
+func CategoryColor(reg *errors.Registry, c errors.Code) string {
+	for parent, color := range Colors {
+		if reg.IsInCategory(c, parent) {
+			return color
+		}
+	}
+	return Red
+}
+
+// Print writes an error to stderr with a renderer for stderr
+func Print(err error) error {
+	return NewRenderer(os.Stderr).Print(err)
+}
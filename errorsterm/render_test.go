@@ -0,0 +1,117 @@
+package errorsterm
+
+import (
+	"bytes"
+	goerrors "errors"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/errors"
+)
+
+func TestRender(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := NewRenderer(buf)
+	if r.Color {
+		t.Errorf("expected colors to be disabled for non-terminals")
+	}
+	if r.Width < 1 {
+		t.Errorf("expected a default width. got: %d", r.Width)
+	}
+
+	err := errors.NewFriendlyFix(errors.CodeForbidden, "not the owner", "you can't delete this dataset", "ask the owner to delete it")
+	if err := r.Print(err); err != nil {
+		t.Fatal(err)
+	}
+	expect := "Error: you can't delete this dataset\n  ask the owner to delete it\n"
+	if buf.String() != expect {
+		t.Errorf("output mismatch.\nexpected: %q\ngot:      %q", expect, buf.String())
+	}
+
+	r.Color = true
+	cases := []struct {
+		err    error
+		expect string
+	}{
+		{err, Yellow + Bold + "Error:" + Reset + " " + Bold + "you can't delete this dataset" + Reset + "\n  " + Dim + "ask the owner to delete it" + Reset + "\n"},
+		{errors.New(errors.CodeNotFound, "no dataset"), Blue + Bold + "Error:" + Reset + " " + Bold + "no dataset" + Reset + "\n"},
+		{goerrors.New("oh no"), Red + Bold + "Error:" + Reset + " " + Bold + "oh no" + Reset + "\n"},
+	}
+	for i, c := range cases {
+		if got := r.Render(c.err); got != c.expect {
+			t.Errorf("case %d output mismatch.\nexpected: %q\ngot:      %q", i, c.expect, got)
+		}
+	}
+}
+
+func TestRenderWidth(t *testing.T) {
+	r := &Renderer{Width: 30}
+	err := errors.NewFriendlyFix(errors.CodeNotFound, "no dataset",
+		"couldn't find a dataset named b5/world_bank_population",
+		"check the dataset name and try again", "b5/world_bank_population", 2)
+	expect := "" +
+		"Error: couldn't find a dataset\n" +
+		"       named\n" +
+		"       b5/world_bank_population\n" +
+		"  check the dataset name and\n" +
+		"  try again\n"
+	if got := r.Render(err); got != expect {
+		t.Errorf("output mismatch.\nexpected:\n%s\ngot:\n%s", expect, got)
+	}
+
+	r.Width = 0
+	expect = "Error: couldn't find a dataset named b5/world_bank_population\n" +
+		"  check the dataset name and try again\n"
+	if got := r.Render(err); got != expect {
+		t.Errorf("unwrapped output mismatch.\nexpected:\n%s\ngot:\n%s", expect, got)
+	}
+}
+
+func TestRenderVerbosity(t *testing.T) {
+	inner := errors.New(errors.CodeNotFound, "no dataset", "b5/world_bank_population").
+		WithDetail(errors.OpDetail, "dsfs.load").
+		WithDetail("peer", "QmPeer")
+	err := errors.WrapFriendly(errors.CodeNotFound, inner, "getting dataset", "couldn't find that dataset").
+		WithDetail(errors.OpDetail, "lib.get")
+
+	r := &Renderer{Verbosity: 1}
+	expect := "Error: couldn't find that dataset\n" +
+		"  message: missing: getting dataset: missing: no dataset\n" +
+		"  op: lib.get > dsfs.load\n"
+	if got := r.Render(err); got != expect {
+		t.Errorf("verbosity 1 output mismatch.\nexpected:\n%s\ngot:\n%s", expect, got)
+	}
+
+	r.Verbosity = 2
+	got := r.Render(inner)
+	expectPrefix := "Error: no dataset\n" +
+		"  message: missing: no dataset\n" +
+		"  op: dsfs.load\n" +
+		"  data:\n" +
+		"    b5/world_bank_population\n" +
+		"  details:\n" +
+		"    op: dsfs.load\n" +
+		"    peer: QmPeer\n" +
+		"  stack:\n" +
+		"    github.com/qri-io/errors/errorsterm.TestRenderVerbosity\n"
+	if !strings.HasPrefix(got, expectPrefix) {
+		t.Errorf("verbosity 2 output mismatch.\nexpected prefix:\n%s\ngot:\n%s", expectPrefix, got)
+	}
+}
+
+func TestCategoryColor(t *testing.T) {
+	cases := []struct {
+		code   errors.Code
+		expect string
+	}{
+		{errors.CodeUnauthorized, Yellow},
+		{errors.CodeGone, Blue},
+		{errors.CodeInternal, Red},
+		{errors.CodeInvalidArgs, Red},
+	}
+	for _, c := range cases {
+		if got := CategoryColor(errors.DefaultRegistry, c.code); got != c.expect {
+			t.Errorf("code %d color mismatch. expected: %q, got: %q", c.code, c.expect, got)
+		}
+	}
+}
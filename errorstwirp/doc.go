@@ -0,0 +1,3 @@
+// Package errorstwirp converts between github.com/qri-io/errors and twirp
+// errors, for services exposing Twirp APIs alongside HTTP
+package errorstwirp
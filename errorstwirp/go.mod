@@ -0,0 +1,17 @@
+module github.com/qri-io/errors/errorstwirp
+
+go 1.21
+
+require (
+	github.com/qri-io/errors v0.0.0-00010101000000-000000000000
+	github.com/twitchtv/twirp v8.1.3+incompatible
+)
+
+require (
+	github.com/pkg/errors v0.9.1 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace (
+	github.com/qri-io/errors => ../
+)
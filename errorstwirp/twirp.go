@@ -0,0 +1,148 @@
+package errorstwirp
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/qri-io/errors"
+	"github.com/twitchtv/twirp"
+)
+
+// metadata keys ToTwirpError writes
+const (
+	metaCode     = "code"
+	metaSlug     = "slug"
+	metaFriendly = "friendly"
+	metaFix      = "fix"
+	metaField    = "field"
+	metaData     = "data"
+	metaDetails  = "details"
+)
+
+// grpcTwirpCodes maps numeric grpc codes to the twirp codes that share their
+// meaning
+var grpcTwirpCodes = map[uint32]twirp.ErrorCode{
+	1:  twirp.Canceled,
+	2:  twirp.Unknown,
+	3:  twirp.InvalidArgument,
+	4:  twirp.DeadlineExceeded,
+	5:  twirp.NotFound,
+	6:  twirp.AlreadyExists,
+	7:  twirp.PermissionDenied,
+	8:  twirp.ResourceExhausted,
+	9:  twirp.FailedPrecondition,
+	10: twirp.Aborted,
+	11: twirp.OutOfRange,
+	12: twirp.Unimplemented,
+	13: twirp.Internal,
+	14: twirp.Unavailable,
+	15: twirp.DataLoss,
+	16: twirp.Unauthenticated,
+}
+
+// twirpGRPCCode finds the numeric grpc code for a twirp code
+func twirpGRPCCode(tc twirp.ErrorCode) uint32 {
+	switch tc {
+	case twirp.Malformed, twirp.BadRoute:
+		return 3
+	}
+	for gc, c := range grpcTwirpCodes {
+		if c == tc {
+			return gc
+		}
+	}
+	return 2
+}
+
+// ToTwirpError converts an error to a twirp error. The twirp code comes from
+// the registry's grpc mapping for the code errors.ResolveCode picks, and the
+// message is the machine message. The code, slug, friendly message, fix,
+// field, data & details are carried in metadata, with data & details JSON
+// encoded. When errors.DefaultOutputMode is errors.OutputExternal the message
+// is the friendly message, and field, data & details are left out. Errors
+// that aren't an *errors.Error keep any twirp error they are, and otherwise
+// become internal errors. nil errors convert to nil
+func ToTwirpError(err error) twirp.Error {
+	if err == nil {
+		return nil
+	}
+	e, ok := errors.AsError(err)
+	if !ok {
+		if terr, ok := err.(twirp.Error); ok {
+			return terr
+		}
+		return twirp.InternalErrorWith(err)
+	}
+
+	reg := e.Registry()
+	code := reg.Canonical(errors.ResolveCode(err))
+	tc, ok := grpcTwirpCodes[reg.CodeGRPCStatus(code)]
+	if !ok {
+		tc = twirp.Unknown
+	}
+	msg := e.Message()
+	if errors.DefaultOutputMode == errors.OutputExternal {
+		e = e.Redact()
+		msg = e.FriendlyMessage()
+	}
+	terr := twirp.NewError(tc, msg).
+		WithMeta(metaCode, strconv.Itoa(int(code))).
+		WithMeta(metaSlug, reg.CodeSlug(code))
+	meta := map[string]string{
+		metaFriendly: e.FriendlyMessage(),
+		metaFix:      e.Fix(),
+		metaField:    e.Field(),
+	}
+	if data := e.Data(); len(data) > 0 {
+		if enc, err := json.Marshal(data); err == nil {
+			meta[metaData] = string(enc)
+		}
+	}
+	if details := e.Details(); len(details) > 0 {
+		if enc, err := json.Marshal(details); err == nil {
+			meta[metaDetails] = string(enc)
+		}
+	}
+	for key, val := range meta {
+		if val != "" {
+			terr = terr.WithMeta(key, val)
+		}
+	}
+	return terr
+}
+
+// FromTwirpError converts a twirp error to an *errors.Error, reading the
+// metadata ToTwirpError writes. Errors without a code in their metadata get
+// the code for their twirp code. nil errors convert to nil
+func FromTwirpError(terr twirp.Error) *errors.Error {
+	if terr == nil {
+		return nil
+	}
+	var code errors.Code
+	if c, err := strconv.Atoi(terr.Meta(metaCode)); err == nil {
+		code = errors.Code(c)
+	} else if slug := terr.Meta(metaSlug); slug != "" {
+		code = errors.CodeFromSlug(slug)
+	}
+	if code == errors.CodeUnknown {
+		code = errors.CodeFromGRPCStatus(twirpGRPCCode(terr.Code()))
+	}
+
+	var data []interface{}
+	if enc := terr.Meta(metaData); enc != "" {
+		json.Unmarshal([]byte(enc), &data)
+	}
+	e := errors.NewFriendlyFix(code, terr.Msg(), terr.Meta(metaFriendly), terr.Meta(metaFix), data...)
+	if field := terr.Meta(metaField); field != "" {
+		e.WithField(field)
+	}
+	if enc := terr.Meta(metaDetails); enc != "" {
+		details := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(enc), &details); err == nil {
+			for key, val := range details {
+				e.WithDetail(key, val)
+			}
+		}
+	}
+	return e
+}
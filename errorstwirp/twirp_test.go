@@ -0,0 +1,84 @@
+package errorstwirp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/qri-io/errors"
+	"github.com/twitchtv/twirp"
+)
+
+func TestToTwirpError(t *testing.T) {
+	e := errors.NewFriendlyFix(errors.CodeNotFound, "no dataset", "couldn't find that dataset", "check the name", "b5").
+		WithField("ref").
+		WithDetail("peer", "QmPeer")
+
+	terr := ToTwirpError(e)
+	if terr.Code() != twirp.NotFound {
+		t.Errorf("twirp code mismatch. expected: %s, got: %s", twirp.NotFound, terr.Code())
+	}
+	if terr.Msg() != "no dataset" {
+		t.Errorf("message mismatch. expected: %s, got: %s", "no dataset", terr.Msg())
+	}
+	if terr.Meta("slug") != "not_found" || terr.Meta("friendly") != "couldn't find that dataset" {
+		t.Errorf("metadata mismatch. got: %v", terr.MetaMap())
+	}
+
+	got := FromTwirpError(terr)
+	if got.Code() != errors.CodeNotFound {
+		t.Errorf("code mismatch. expected: %d, got: %d", errors.CodeNotFound, got.Code())
+	}
+	if got.Friendly() != e.Friendly() {
+		t.Errorf("friendly mismatch. expected: %s, got: %s", e.Friendly(), got.Friendly())
+	}
+	if got.Field() != "ref" || !reflect.DeepEqual(got.Details(), e.Details()) {
+		t.Errorf("field or details mismatch. got: %s %v", got.Field(), got.Details())
+	}
+
+	if ToTwirpError(nil) != nil {
+		t.Errorf("expected nil error to convert to nil")
+	}
+	if terr := ToTwirpError(twirp.NewError(twirp.Aborted, "aborted")); terr.Code() != twirp.Aborted {
+		t.Errorf("expected twirp errors to pass through. got: %s", terr.Code())
+	}
+}
+
+func TestFromTwirpError(t *testing.T) {
+	cases := []struct {
+		code   twirp.ErrorCode
+		expect errors.Code
+	}{
+		{twirp.Unauthenticated, errors.CodeUnauthorized},
+		{twirp.Malformed, errors.CodeInvalidSyntax},
+		{twirp.DeadlineExceeded, errors.CodeTimeout},
+		{twirp.Unavailable, errors.CodeUnavailable},
+	}
+	for _, c := range cases {
+		if got := FromTwirpError(twirp.NewError(c.code, "nope")); got.Code() != c.expect {
+			t.Errorf("%s code mismatch. expected: %d, got: %d", c.code, c.expect, got.Code())
+		}
+	}
+}
+
+func TestToTwirpErrorExternal(t *testing.T) {
+	prev := errors.DefaultOutputMode
+	errors.DefaultOutputMode = errors.OutputExternal
+	defer func() { errors.DefaultOutputMode = prev }()
+
+	e := errors.NewFriendlyFix(errors.CodeNotFound, "no row with id 42", "couldn't find that dataset", "check the name", "b5").
+		WithField("ref").
+		WithDetail("table", "datasets")
+
+	terr := ToTwirpError(e)
+	if terr.Msg() != "couldn't find that dataset" {
+		t.Errorf("expected the friendly message. got: %s", terr.Msg())
+	}
+	if terr.Meta("fix") != "check the name" || terr.Meta("slug") != "not_found" {
+		t.Errorf("metadata mismatch. got: %v", terr.MetaMap())
+	}
+	for _, key := range []string{"field", "data", "details"} {
+		if val := terr.Meta(key); val != "" {
+			t.Errorf("expected %s to be left out. got: %s", key, val)
+		}
+	}
+}
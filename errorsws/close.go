@@ -0,0 +1,50 @@
+package errorsws
+
+import (
+	"unicode/utf8"
+
+	"github.com/qri-io/errors"
+	"nhooyr.io/websocket"
+)
+
+// maxReasonLen is the longest close reason that fits in a close frame
+const maxReasonLen = 123
+
+// CloseStatus returns the close code & reason for an error. The close code
+// comes from the code errors.ResolveCode picks, and the reason is the friendly
+// message, truncated to fit in a close frame. Errors that aren't an
+// *errors.Error close with internal error (1011), and nil errors with normal
+// closure (1000)
+func CloseStatus(err error) (websocket.StatusCode, string) {
+	if err == nil {
+		return websocket.StatusNormalClosure, ""
+	}
+	e, ok := errors.AsError(err)
+	if !ok {
+		return websocket.StatusInternalError, "internal error"
+	}
+	reg := e.Registry()
+	code := reg.Canonical(errors.ResolveCode(err))
+	return websocket.StatusCode(reg.CodeWSCloseStatus(code)), truncateReason(e.FriendlyMessage())
+}
+
+// CloseWithError closes a connection with the close code & reason for an
+// error
+func CloseWithError(conn *websocket.Conn, err error) error {
+	status, reason := CloseStatus(err)
+	return conn.Close(status, reason)
+}
+
+// truncateReason shortens a reason to fit in a close frame, cutting on a
+// rune boundary
+func truncateReason(reason string) string {
+	const ellipsis = "…"
+	if len(reason) <= maxReasonLen {
+		return reason
+	}
+	n := maxReasonLen - len(ellipsis)
+	for n > 0 && !utf8.RuneStart(reason[n]) {
+		n--
+	}
+	return reason[:n] + ellipsis
+}
@@ -0,0 +1,71 @@
+package errorsws
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/errors"
+	"nhooyr.io/websocket"
+)
+
+func TestCloseStatus(t *testing.T) {
+	cases := []struct {
+		err    error
+		status websocket.StatusCode
+		reason string
+	}{
+		{nil, websocket.StatusNormalClosure, ""},
+		{fmt.Errorf("oh no"), websocket.StatusInternalError, "internal error"},
+		{errors.NewFriendly(errors.CodeForbidden, "private", "you can't subscribe to that"), websocket.StatusPolicyViolation, "you can't subscribe to that"},
+		{errors.New(errors.CodeUnavailable, "draining"), websocket.StatusTryAgainLater, "this service is currently unavailable"},
+	}
+	for _, c := range cases {
+		status, reason := CloseStatus(c.err)
+		if status != c.status {
+			t.Errorf("status mismatch. expected: %s, got: %s", c.status, status)
+		}
+		if reason != c.reason {
+			t.Errorf("reason mismatch. expected: %s, got: %s", c.reason, reason)
+		}
+	}
+
+	_, reason := CloseStatus(errors.NewFriendly(errors.CodeInvalidArgs, "bad frame", strings.Repeat("ü", 100)))
+	if len(reason) > maxReasonLen {
+		t.Errorf("expected reason to be truncated to %d bytes. got: %d", maxReasonLen, len(reason))
+	}
+	if !strings.HasSuffix(reason, "ü…") {
+		t.Errorf("expected reason to be cut on a rune boundary. got: %q", reason)
+	}
+}
+
+func TestCloseWithError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		CloseWithError(conn, errors.NewFriendly(errors.CodeUnauthorized, "token expired", "your session has expired"))
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close(websocket.StatusNormalClosure, "")
+
+	_, _, err = conn.Read(ctx)
+	var cerr websocket.CloseError
+	if !goerrors.As(err, &cerr) {
+		t.Fatalf("expected a close error. got: %v", err)
+	}
+	if cerr.Code != websocket.StatusPolicyViolation || cerr.Reason != "your session has expired" {
+		t.Errorf("close mismatch. expected: %s %q, got: %s %q", websocket.StatusPolicyViolation, "your session has expired", cerr.Code, cerr.Reason)
+	}
+}
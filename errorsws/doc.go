@@ -0,0 +1,3 @@
+// Package errorsws terminates nhooyr.io/websocket connections with close
+// codes & reasons derived from github.com/qri-io/errors
+package errorsws
@@ -0,0 +1,16 @@
+module github.com/qri-io/errors/errorsws
+
+go 1.21
+
+require (
+	github.com/qri-io/errors v0.0.0-00010101000000-000000000000
+	nhooyr.io/websocket v1.8.17
+)
+
+require (
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace (
+	github.com/qri-io/errors => ../
+)
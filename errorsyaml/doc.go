@@ -0,0 +1,7 @@
+// Package errorsyaml adds YAML support to github.com/qri-io/errors, keeping
+// gopkg.in/yaml.v2 out of the errors package's dependencies. Importing it
+// registers an "application/yaml" serializer, and YAML catalogs for
+// Registry.Load & Registry.Export:
+//
+//	import _ "github.com/qri-io/errors/errorsyaml"
+package errorsyaml
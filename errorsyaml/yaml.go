@@ -0,0 +1,31 @@
+package errorsyaml
+
+import (
+	"github.com/qri-io/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// ContentType is the media type of YAML errors
+const ContentType = "application/yaml"
+
+// Marshal writes an error as YAML, with the same fields as MarshalJSON
+func Marshal(e *errors.Error) ([]byte, error) {
+	return yaml.Marshal(e)
+}
+
+// Unmarshal reads an error written by Marshal
+func Unmarshal(data []byte) (*errors.Error, error) {
+	e := &errors.Error{}
+	return e, yaml.Unmarshal(data, e)
+}
+
+func init() {
+	errors.RegisterSerializer(ContentType, errors.SerializerFuncs{
+		MarshalFunc:   Marshal,
+		UnmarshalFunc: Unmarshal,
+	})
+	errors.RegisterCatalogFormat(errors.FormatYAML, errors.CatalogCodec{
+		Marshal:   yaml.Marshal,
+		Unmarshal: yaml.Unmarshal,
+	})
+}
@@ -0,0 +1,105 @@
+package errorsyaml
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/errors"
+	"gopkg.in/yaml.v2"
+)
+
+func TestErrorYAML(t *testing.T) {
+	e := errors.NewFriendly(errors.CodeNotFound, "dataset not found", "couldn't find that dataset", "b5/world_bank").
+		WithDetail("peer", "QmPeer")
+
+	data, err := Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `version: 1
+code: 6
+slug: not_found
+message: dataset not found
+friendly: couldn't find that dataset
+data:
+- b5/world_bank
+details:
+  peer: QmPeer
+`
+	if string(data) != expect {
+		t.Errorf("yaml mismatch.\nexpected:\n%s\ngot:\n%s", expect, string(data))
+	}
+
+	got, err := Unmarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Code() != errors.CodeNotFound || got.Friendly() != e.Friendly() || got.Details()["peer"] != "QmPeer" {
+		t.Errorf("round trip mismatch. expected: %s, got: %s", e.Friendly(), got.Friendly())
+	}
+
+	if _, ok := errors.SerializerFor(ContentType); !ok {
+		t.Errorf("expected importing errorsyaml to register a serializer for %s", ContentType)
+	}
+}
+
+func TestLoadRegistryYAML(t *testing.T) {
+	yamlCatalog := `
+codes:
+- code: 400
+  name: database
+  http_status: 504
+  severity: critical
+  retryable: true
+  friendly: the database couldn't be reached
+- code: 401
+  name: quota
+  http_status: 402
+`
+	r := errors.NewRegistry()
+	if err := r.Load(strings.NewReader(yamlCatalog)); err != nil {
+		t.Fatal(err)
+	}
+	if r.CodeSeverity(errors.Code(400)) != errors.SeverityCritical {
+		t.Errorf("severity mismatch. expected: %s, got: %s", errors.SeverityCritical, r.CodeSeverity(errors.Code(400)))
+	}
+	if r.CodeHTTPStatus(errors.Code(401)) != 402 {
+		t.Errorf("status mismatch. expected: %d, got: %d", 402, r.CodeHTTPStatus(errors.Code(401)))
+	}
+
+	jsonCatalog := `{"codes":[{"code":402,"name":"conflict","http_status":409}]}`
+	if err := r.Load(strings.NewReader(jsonCatalog)); err != nil {
+		t.Fatal(err)
+	}
+	if r.CodeHTTPStatus(errors.Code(402)) != 409 {
+		t.Errorf("status mismatch. expected: %d, got: %d", 409, r.CodeHTTPStatus(errors.Code(402)))
+	}
+
+	if err := r.Load(strings.NewReader(`codes: {`)); !errors.IsCode(err, errors.CodeInvalidSyntax) {
+		t.Errorf("expected invalid yaml to be a syntax error. got: %v", err)
+	}
+}
+
+func TestExportRegistryYAML(t *testing.T) {
+	r := errors.NewRegistry()
+	r.RegisterSpec(errors.Code(100), errors.CodeSpec{HTTPStatus: 504, Name: "database", Friendly: "no database"})
+
+	buf := &bytes.Buffer{}
+	if err := r.Export(buf, errors.FormatYAML); err != nil {
+		t.Fatal(err)
+	}
+	cat := struct {
+		Codes []struct {
+			Code     errors.Code `yaml:"code"`
+			Friendly string      `yaml:"friendly"`
+		} `yaml:"codes"`
+	}{}
+	if err := yaml.Unmarshal(buf.Bytes(), &cat); err != nil {
+		t.Fatal(err)
+	}
+	last := cat.Codes[len(cat.Codes)-1]
+	if last.Code != errors.Code(100) || last.Friendly != "no database" {
+		t.Errorf("catalog entry mismatch. got: %#v", last)
+	}
+}
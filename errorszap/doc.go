@@ -0,0 +1,4 @@
+// Package errorszap logs github.com/qri-io/errors values to go.uber.org/zap
+// as structured objects, keeping zap out of the errors package's
+// dependencies
+package errorszap
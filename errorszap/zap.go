@@ -0,0 +1,88 @@
+package errorszap
+
+import (
+	"sort"
+
+	"github.com/qri-io/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Error creates a field logging an error as a structured object under the
+// "error" key, mirroring zap.Error
+func Error(err error) zap.Field {
+	return NamedError("error", err)
+}
+
+// NamedError creates a field logging an error as a structured object under
+// key. nil errors produce a no-op field
+func NamedError(key string, err error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+	return zap.Object(key, Object(err))
+}
+
+// Object wraps an error in a zapcore.ObjectMarshaler, for use with
+// zap.Object("err", errorszap.Object(err))
+func Object(err error) zapcore.ObjectMarshaler {
+	return object{err: err}
+}
+
+// object marshals an error as a zap object
+type object struct {
+	err error
+}
+
+// MarshalLogObject implements the zapcore.ObjectMarshaler interface. Errors
+// that aren't an *errors.Error are written as just their message
+func (o object) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("message", o.err.Error())
+	e, ok := errors.AsError(o.err)
+	if !ok {
+		return nil
+	}
+
+	reg := e.Registry()
+	code := reg.Canonical(errors.ResolveCode(o.err))
+	enc.AddInt("code", int(code))
+	enc.AddString("slug", reg.CodeSlug(code))
+	enc.AddInt("http_status", reg.CodeHTTPStatus(code))
+	enc.AddString("fingerprint", errors.Fingerprint(o.err))
+	if friendly := e.FriendlyMessage(); friendly != "" {
+		enc.AddString("friendly", friendly)
+	}
+	if fix := e.Fix(); fix != "" {
+		enc.AddString("fix", fix)
+	}
+	if field := e.Field(); field != "" {
+		enc.AddString("field", field)
+	}
+	if data := e.Data(); len(data) > 0 {
+		if err := enc.AddReflected("data", data); err != nil {
+			return err
+		}
+	}
+	if details := e.Details(); len(details) > 0 {
+		return enc.AddObject("details", detailsObject(details))
+	}
+	return nil
+}
+
+// detailsObject marshals error details as a zap object with sorted keys
+type detailsObject map[string]interface{}
+
+// MarshalLogObject implements the zapcore.ObjectMarshaler interface
+func (d detailsObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	keys := make([]string, 0, len(d))
+	for key := range d {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := enc.AddReflected(key, d[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package errorszap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/qri-io/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestNamedError(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(core)
+
+	err := errors.NewFriendly(errors.CodeNotFound, "no dataset", "couldn't find that dataset", "b5/world_bank").
+		WithDetail("peer", "QmPeer")
+	logger.Error("failed", NamedError("err", err), Error(fmt.Errorf("oh no")), NamedError("none", nil))
+
+	fields := logs.All()[0].ContextMap()
+	obj, ok := fields["err"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected err to be an object. got: %v", fields["err"])
+	}
+	if obj["slug"] != "not_found" || obj["http_status"] != 404 || obj["friendly"] != "couldn't find that dataset" {
+		t.Errorf("object mismatch. got: %v", obj)
+	}
+	if details, ok := obj["details"].(map[string]interface{}); !ok || details["peer"] != "QmPeer" {
+		t.Errorf("details mismatch. got: %v", obj["details"])
+	}
+	if obj["fingerprint"] != errors.Fingerprint(err) {
+		t.Errorf("fingerprint mismatch. expected: %s, got: %v", errors.Fingerprint(err), obj["fingerprint"])
+	}
+
+	plain, ok := fields["error"].(map[string]interface{})
+	if !ok || plain["message"] != "oh no" || len(plain) != 1 {
+		t.Errorf("expected plain errors to log just their message. got: %v", fields["error"])
+	}
+	if _, ok := fields["none"]; ok {
+		t.Errorf("expected nil errors to be skipped")
+	}
+}
@@ -0,0 +1,3 @@
+// Package errorszerolog writes github.com/qri-io/errors values to
+// github.com/rs/zerolog events as structured objects
+package errorszerolog
@@ -0,0 +1,108 @@
+package errorszerolog
+
+import (
+	"sort"
+
+	"github.com/qri-io/errors"
+	"github.com/rs/zerolog"
+)
+
+// Dict returns a dictionary of an error's structured fields, for use with
+// event.Dict("err", errorszerolog.Dict(err))
+func Dict(err error) *zerolog.Event {
+	return zerolog.Dict().EmbedObject(Object(err))
+}
+
+// Object wraps an error in a zerolog.LogObjectMarshaler, for use with
+// event.Object("err", errorszerolog.Object(err))
+func Object(err error) zerolog.LogObjectMarshaler {
+	return object{err: err}
+}
+
+// MarshalError is a zerolog.ErrorMarshalFunc writing *errors.Error values as
+// objects, so event.Err(err) logs structured fields. Install it with
+// zerolog.ErrorMarshalFunc = errorszerolog.MarshalError
+func MarshalError(err error) interface{} {
+	if _, ok := errors.AsError(err); ok {
+		return Object(err)
+	}
+	return err
+}
+
+// object marshals an error as a zerolog object
+type object struct {
+	err error
+}
+
+// MarshalZerologObject implements the zerolog.LogObjectMarshaler interface.
+// Along with the error's fields, "chain" lists the slugs of every
+// *errors.Error in the chain from outermost to innermost, and "root_cause" is
+// the message of the error at the bottom of the chain. Errors that aren't an
+// *errors.Error are written as just their message
+func (o object) MarshalZerologObject(ev *zerolog.Event) {
+	if o.err == nil {
+		return
+	}
+	ev.Str("message", o.err.Error())
+	e, ok := errors.AsError(o.err)
+	if !ok {
+		return
+	}
+
+	reg := e.Registry()
+	code := reg.Canonical(errors.ResolveCode(o.err))
+	ev.Int("code", int(code)).
+		Str("slug", reg.CodeSlug(code)).
+		Int("http_status", reg.CodeHTTPStatus(code)).
+		Str("fingerprint", errors.Fingerprint(o.err))
+	if friendly := e.FriendlyMessage(); friendly != "" {
+		ev.Str("friendly", friendly)
+	}
+	if fix := e.Fix(); fix != "" {
+		ev.Str("fix", fix)
+	}
+	if field := e.Field(); field != "" {
+		ev.Str("field", field)
+	}
+	if details := e.Details(); len(details) > 0 {
+		keys := make([]string, 0, len(details))
+		for key := range details {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		dict := zerolog.Dict()
+		for _, key := range keys {
+			dict.Interface(key, details[key])
+		}
+		ev.Dict("details", dict)
+	}
+
+	var chain []string
+	each(o.err, func(e *errors.Error) {
+		chain = append(chain, e.Registry().CodeSlug(e.Code()))
+	})
+	ev.Strs("chain", chain)
+	if root := errors.Cause(o.err); root != nil {
+		ev.Str("root_cause", root.Error())
+	}
+}
+
+// each calls fn for every *errors.Error in err's chain
+func each(err error, fn func(*errors.Error)) {
+	if err == nil {
+		return
+	}
+	if e, ok := err.(*errors.Error); ok {
+		fn(e)
+	}
+	switch x := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, child := range x.Unwrap() {
+			each(child, fn)
+		}
+	case interface{ Unwrap() error }:
+		each(x.Unwrap(), fn)
+	case interface{ Cause() error }:
+		each(x.Cause(), fn)
+	}
+}
@@ -0,0 +1,69 @@
+package errorszerolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/qri-io/errors"
+	"github.com/rs/zerolog"
+)
+
+func TestDict(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := zerolog.New(buf)
+
+	_, cause := os.Open("/does/not/exist")
+	inner := errors.Wrap(errors.CodeNotFound, cause, "no dataset")
+	err := errors.Wrap(errors.CodeUnavailable, inner, "loading").WithDetail("ref", "b5/world_bank")
+	logger.Error().Dict("err", Dict(err)).Msg("failed")
+
+	entry := struct {
+		Err map[string]interface{} `json:"err"`
+	}{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	obj := entry.Err
+	if obj["slug"] != "not_found" || obj["http_status"] != float64(404) {
+		t.Errorf("object mismatch. got: %v", obj)
+	}
+	if chain := fmt.Sprint(obj["chain"]); chain != "[unavailable not_found]" {
+		t.Errorf("chain mismatch. expected: %s, got: %s", "[unavailable not_found]", chain)
+	}
+	if obj["root_cause"] != cause.Error() {
+		t.Errorf("root cause mismatch. expected: %s, got: %v", cause.Error(), obj["root_cause"])
+	}
+	if details, ok := obj["details"].(map[string]interface{}); !ok || details["ref"] != "b5/world_bank" {
+		t.Errorf("details mismatch. got: %v", obj["details"])
+	}
+}
+
+func TestMarshalError(t *testing.T) {
+	prev := zerolog.ErrorMarshalFunc
+	zerolog.ErrorMarshalFunc = MarshalError
+	defer func() { zerolog.ErrorMarshalFunc = prev }()
+
+	buf := &bytes.Buffer{}
+	logger := zerolog.New(buf)
+	logger.Error().Err(errors.New(errors.CodeTimeout, "peer took too long")).Msg("failed")
+	logger.Error().Err(fmt.Errorf("oh no")).Msg("failed")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	structured := map[string]interface{}{}
+	if err := json.Unmarshal(lines[0], &structured); err != nil {
+		t.Fatal(err)
+	}
+	if obj, ok := structured["error"].(map[string]interface{}); !ok || obj["slug"] != "timeout" {
+		t.Errorf("expected *errors.Error to be written as an object. got: %s", lines[0])
+	}
+	plain := map[string]interface{}{}
+	if err := json.Unmarshal(lines[1], &plain); err != nil {
+		t.Fatal(err)
+	}
+	if plain["error"] != "oh no" {
+		t.Errorf("expected plain errors to be written as strings. got: %s", lines[1])
+	}
+}
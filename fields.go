@@ -0,0 +1,111 @@
+package errors
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Fields returns a flat view of an error for logging & telemetry systems.
+// Keys are "code", "status", "slug", "message", "friendly", "fix", "op" and
+// "id", with other details added under "details." prefixed keys. Nested maps in
+// details are flattened with dotted keys. Every value is a string, bool, int,
+// int64 or float64, so fields serialize safely in any format: times are
+// formatted as RFC 3339, errors & stringers use their string form, and other
+// values are formatted with fmt. Errors that aren't an *Error only set
+// "message", and nil errors return nil
+func Fields(err error) map[string]interface{} {
+	if err == nil {
+		return nil
+	}
+	fields := map[string]interface{}{"message": err.Error()}
+	e, ok := AsError(err)
+	if !ok {
+		return fields
+	}
+
+	reg := e.Registry()
+	code := reg.Canonical(ResolveCode(err))
+	fields["code"] = int(code)
+	fields["status"] = reg.CodeHTTPStatus(code)
+	fields["slug"] = reg.CodeSlug(code)
+	if friendly := e.friendlyMessage(); friendly != "" {
+		fields["friendly"] = friendly
+	}
+	if fix := e.Fix(); fix != "" {
+		fields["fix"] = fix
+	}
+	o := OriginOf(err)
+	if o.Op != "" {
+		fields["op"] = o.Op
+	}
+	if o.ID != "" {
+		fields["id"] = o.ID
+	}
+	for key, val := range e.details {
+		switch key {
+		case OpDetail, ErrorIDDetail:
+		default:
+			flattenFields(fields, "details.", map[string]interface{}{key: val})
+		}
+	}
+	return fields
+}
+
+// flattenFields adds values to fields under prefixed keys, descending into
+// nested maps
+func flattenFields(fields map[string]interface{}, prefix string, values map[string]interface{}) {
+	for key, val := range values {
+		if nested, ok := val.(map[string]interface{}); ok {
+			flattenFields(fields, prefix+key+".", nested)
+			continue
+		}
+		fields[prefix+key] = safeValue(val)
+	}
+}
+
+// safeValue converts a value to a string, bool, int, int64 or float64
+func safeValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string, bool, int, int64:
+		return x
+	case int8:
+		return int64(x)
+	case int16:
+		return int64(x)
+	case int32:
+		return int64(x)
+	case uint8:
+		return int64(x)
+	case uint16:
+		return int64(x)
+	case uint32:
+		return int64(x)
+	case uint:
+		return safeValue(uint64(x))
+	case uint64:
+		if x > math.MaxInt64 {
+			return fmt.Sprint(x)
+		}
+		return int64(x)
+	case float32:
+		return safeValue(float64(x))
+	case float64:
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return fmt.Sprint(x)
+		}
+		return x
+	case time.Time:
+		return x.Format(time.RFC3339Nano)
+	case time.Duration:
+		return x.String()
+	case error:
+		return x.Error()
+	case fmt.Stringer:
+		return x.String()
+	default:
+		return fmt.Sprint(x)
+	}
+}
@@ -0,0 +1,49 @@
+package errors
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFields(t *testing.T) {
+	at := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	err := NewFriendlyFix(CodeNotFound, "no dataset", "couldn't find that dataset", "check the name").
+		WithDetail(OpDetail, "dsfs.LoadDataset").
+		WithDetail("at", at).
+		WithDetail("size", uint8(3)).
+		WithDetail("ratio", math.NaN()).
+		WithDetail("peer", map[string]interface{}{"id": "QmPeer", "addrs": []string{"a", "b"}})
+	got := Fields(err)
+	expect := map[string]interface{}{
+		"code":               int(CodeNotFound),
+		"status":             404,
+		"slug":               "not_found",
+		"message":            "missing: no dataset",
+		"friendly":           "couldn't find that dataset",
+		"fix":                "check the name",
+		"op":                 "dsfs.LoadDataset",
+		"details.at":         "2021-03-04T05:06:07Z",
+		"details.size":       int64(3),
+		"details.ratio":      "NaN",
+		"details.peer.id":    "QmPeer",
+		"details.peer.addrs": "[a b]",
+	}
+	if len(got) != len(expect) {
+		t.Errorf("field count mismatch. expected: %d, got: %d %v", len(expect), len(got), got)
+	}
+	for key, val := range expect {
+		if got[key] != val {
+			t.Errorf("field %q mismatch. expected: %v (%T), got: %v (%T)", key, val, val, got[key], got[key])
+		}
+	}
+
+	plain := Fields(fmt.Errorf("oh no"))
+	if len(plain) != 1 || plain["message"] != "oh no" {
+		t.Errorf("expected plain errors to only set message. got: %v", plain)
+	}
+	if Fields(nil) != nil {
+		t.Errorf("expected nil fields for a nil error")
+	}
+}
@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Fingerprint returns a stable identifier grouping occurrences of the same
+// error. It hashes the slug of the code ResolveCode picks, the messages
+// given to every *Error in the chain, the types of other errors in the chain,
+// and the function that created the innermost *Error with a stack. Data,
+// details, the text of wrapped errors and line numbers are left out, so the
+// fingerprint survives differing inputs and unrelated edits. Errors that
+// aren't an *Error are fingerprinted by their message
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+	h := sha256.New()
+	e, ok := AsError(err)
+	if !ok {
+		h.Write([]byte(err.Error()))
+		return hex.EncodeToString(h.Sum(nil))[:16]
+	}
+
+	h.Write([]byte(e.Registry().CodeSlug(e.Registry().Canonical(ResolveCode(err)))))
+	var origin string
+	walk(err, func(err error) bool {
+		h.Write([]byte{0})
+		switch x := err.(type) {
+		case *Error:
+			if wm, ok := x.cause.(*withMessage); ok {
+				h.Write([]byte(wm.msg))
+			} else {
+				h.Write([]byte(x.Message()))
+			}
+			if frames := x.stack.frames(); len(frames) > 0 {
+				origin = frames[0].Function
+			}
+		case *withMessage:
+		default:
+			fmt.Fprintf(h, "%T", err)
+		}
+		return false
+	})
+	h.Write([]byte{0})
+	h.Write([]byte(origin))
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
@@ -0,0 +1,37 @@
+package errors
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func loadDataset(ref string) error {
+	_, err := os.Open(ref)
+	return Wrap(CodeNotFound, err, "loading dataset", ref)
+}
+
+func TestFingerprint(t *testing.T) {
+	a := loadDataset("/tmp/does/not/exist/a")
+	b := loadDataset("/tmp/does/not/exist/b")
+	if Fingerprint(a) != Fingerprint(b) {
+		t.Errorf("expected errors differing only by input to share a fingerprint. got: %s, %s", Fingerprint(a), Fingerprint(b))
+	}
+	if len(Fingerprint(a)) != 16 {
+		t.Errorf("fingerprint length mismatch. expected: %d, got: %d", 16, len(Fingerprint(a)))
+	}
+
+	c := New(CodeNotFound, "loading dataset")
+	if Fingerprint(a) == Fingerprint(c) {
+		t.Errorf("expected errors created in different places to have different fingerprints")
+	}
+	if Fingerprint(a) == Fingerprint(New(CodeTimeout, "loading dataset")) {
+		t.Errorf("expected errors with different codes to have different fingerprints")
+	}
+	if Fingerprint(fmt.Errorf("oh no")) != Fingerprint(fmt.Errorf("oh no")) {
+		t.Errorf("expected plain errors with the same message to share a fingerprint")
+	}
+	if Fingerprint(nil) != "" {
+		t.Errorf("expected nil errors to have an empty fingerprint")
+	}
+}
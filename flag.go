@@ -0,0 +1,81 @@
+package errors
+
+import (
+	goerrors "errors"
+	"flag"
+	"regexp"
+	"strings"
+)
+
+// flagErrorPatterns match the parse errors of the standard library flag
+// package & github.com/spf13/pflag, capturing the offending flag. raw
+// patterns capture an argument that isn't a well-formed flag, which is used
+// as is
+var flagErrorPatterns = []struct {
+	re       *regexp.Regexp
+	friendly string
+	raw      bool
+}{
+	{regexp.MustCompile(`^flag provided but not defined: -+(.+)$`), "unknown flag %s", false},
+	{regexp.MustCompile(`^unknown flag: -+(.+)$`), "unknown flag %s", false},
+	{regexp.MustCompile(`^unknown shorthand flag: '(.)'`), "unknown flag %s", false},
+	{regexp.MustCompile(`^flag needs an argument: '?-*([^'\s]+)'?`), "flag %s needs a value", false},
+	{regexp.MustCompile(`^invalid (?:boolean )?value ".*" for (?:flag )?-+([^:\s]+)`), "invalid value for flag %s", false},
+	{regexp.MustCompile(`^invalid argument ".*" for "(?:-\w, )?-+([^"]+)" flag`), "invalid value for flag %s", false},
+	{regexp.MustCompile(`^invalid boolean flag -*([^:\s]+)`), "invalid value for flag %s", false},
+	{regexp.MustCompile(`^bad flag syntax: (.+)$`), "bad flag syntax: %s", true},
+}
+
+// FlagError converts an error returned by parsing flags with the standard
+// library flag package or github.com/spf13/pflag into an error with
+// CodeUsage. The offending flag is set as the error's field and named in the
+// friendly message, and the fix suggests --help. flag.ErrHelp gets a
+// CodeUsage error without a fix that still matches flag.ErrHelp with
+// errors.Is. nil errors return nil
+func FlagError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	if goerrors.Is(err, flag.ErrHelp) {
+		e := wrapError(CodeUsage, err, "help requested", nil)
+		e.friendly = "help requested"
+		// suggesting --help to someone who just asked for it doesn't help
+		e.noDefaultFix = true
+		return hooked(e)
+	}
+
+	e := wrapError(CodeUsage, err, "parsing flags", nil)
+	e.friendly = err.Error()
+	msg := err.Error()
+	for _, p := range flagErrorPatterns {
+		if m := p.re.FindStringSubmatch(msg); m != nil {
+			name, display := m[1], m[1]
+			if !p.raw {
+				name = strings.TrimLeft(name, "-")
+				display = flagName(name)
+			}
+			e.field = name
+			e.friendly = strings.Replace(p.friendly, "%s", display, 1)
+			break
+		}
+	}
+	return hooked(e)
+}
+
+// flagName formats a flag name the way users type it
+func flagName(name string) string {
+	if len(name) == 1 {
+		return "-" + name
+	}
+	return "--" + name
+}
+
+// ParseFlags parses args with fs, converting errors with FlagError. fs should
+// be created with flag.ContinueOnError, and given io.Discard as output to
+// leave printing errors to the caller
+func ParseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return FlagError(err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package errors
+
+import (
+	goerrors "errors"
+	"flag"
+	"io"
+	"testing"
+)
+
+func TestParseFlags(t *testing.T) {
+	newFlags := func() *flag.FlagSet {
+		fs := flag.NewFlagSet("qri", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		fs.Int("limit", 0, "")
+		fs.Bool("v", false, "")
+		fs.String("format", "", "")
+		return fs
+	}
+	cases := []struct {
+		args     []string
+		field    string
+		friendly string
+	}{
+		{[]string{"--nope"}, "nope", "unknown flag --nope"},
+		{[]string{"-x"}, "x", "unknown flag -x"},
+		{[]string{"--limit", "ten"}, "limit", "invalid value for flag --limit"},
+		{[]string{"-v=maybe"}, "v", "invalid value for flag -v"},
+		{[]string{"--format"}, "format", "flag --format needs a value"},
+		{[]string{"---format"}, "---format", "bad flag syntax: ---format"},
+	}
+	for i, c := range cases {
+		err := ParseFlags(newFlags(), c.args)
+		e, ok := err.(*Error)
+		if !ok {
+			t.Errorf("case %d expected an *Error. got: %v", i, err)
+			continue
+		}
+		if e.Code() != CodeUsage {
+			t.Errorf("case %d code mismatch. expected: %d, got: %d", i, CodeUsage, e.Code())
+		}
+		if e.Field() != c.field {
+			t.Errorf("case %d field mismatch. expected: %q, got: %q", i, c.field, e.Field())
+		}
+		if e.FriendlyMessage() != c.friendly {
+			t.Errorf("case %d friendly mismatch. expected: %q, got: %q", i, c.friendly, e.FriendlyMessage())
+		}
+		if e.Fix() != "run with --help to see usage" {
+			t.Errorf("case %d expected the default fix. got: %q", i, e.Fix())
+		}
+	}
+
+	if err := ParseFlags(newFlags(), []string{"--limit", "10"}); err != nil {
+		t.Errorf("expected valid flags to parse. got: %v", err)
+	}
+	err := ParseFlags(newFlags(), []string{"-h"})
+	if !goerrors.Is(err, flag.ErrHelp) || ExitCode(err) != 2 {
+		t.Errorf("expected help to match flag.ErrHelp and exit 2. got: %v", err)
+	}
+	if help := err.(*Error); help.Fix() != "" || help.Redact().Fix() != "" {
+		t.Errorf("expected help not to suggest --help. got: %q", help.Fix())
+	}
+}
+
+func TestFlagErrorPflag(t *testing.T) {
+	cases := []struct {
+		msg      string
+		field    string
+		friendly string
+	}{
+		{"unknown flag: --nope", "nope", "unknown flag --nope"},
+		{"unknown shorthand flag: 'x' in -x", "x", "unknown flag -x"},
+		{"flag needs an argument: --format", "format", "flag --format needs a value"},
+		{"flag needs an argument: 'f' in -f", "f", "flag -f needs a value"},
+		{`invalid argument "ten" for "-l, --limit" flag: strconv.ParseInt: parsing "ten": invalid syntax`, "limit", "invalid value for flag --limit"},
+	}
+	for i, c := range cases {
+		e := FlagError(goerrors.New(c.msg))
+		if e.Field() != c.field || e.FriendlyMessage() != c.friendly {
+			t.Errorf("case %d mismatch. expected: %q %q, got: %q %q", i, c.field, c.friendly, e.Field(), e.FriendlyMessage())
+		}
+	}
+	if FlagError(nil) != nil {
+		t.Errorf("expected nil errors to return nil")
+	}
+}
@@ -0,0 +1,37 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+// RegisterGob registers Error and the container types Error data & details
+// use with encoding/gob, allowing an *Error to be sent as an error or
+// interface{} value through net/rpc and other gob-based channels. Call
+// RegisterGob once in both the sending & receiving program
+func RegisterGob() {
+	gob.Register(&Error{})
+	gob.Register([]interface{}{})
+	gob.Register(map[string]interface{}{})
+}
+
+// GobEncode implements the gob.GobEncoder interface, writing the same fields
+// as MarshalJSON
+func (e Error) GobEncode() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := gob.NewEncoder(buf).Encode(e.wireError()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements the gob.GobDecoder interface, reading the output of
+// GobEncode
+func (e *Error) GobDecode(data []byte) error {
+	je := jsonError{}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&je); err != nil {
+		return err
+	}
+	e.fromJSONError(je)
+	return nil
+}
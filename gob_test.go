@@ -0,0 +1,37 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+)
+
+func TestErrorGob(t *testing.T) {
+	RegisterGob()
+	e := NewFriendly(CodeForbidden, "not allowed", "you can't edit that dataset", "b5/world_bank").
+		WithDetail("owner", map[string]interface{}{"name": "b5"})
+
+	type reply struct {
+		Err error
+	}
+	buf := &bytes.Buffer{}
+	if err := gob.NewEncoder(buf).Encode(reply{Err: e}); err != nil {
+		t.Fatal(err)
+	}
+	r := reply{}
+	if err := gob.NewDecoder(buf).Decode(&r); err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok := r.Err.(*Error)
+	if !ok {
+		t.Fatalf("expected decoded error to be an *Error. got: %T", r.Err)
+	}
+	if got.Code() != CodeForbidden || got.Error() != e.Error() || got.Friendly() != e.Friendly() {
+		t.Errorf("round trip mismatch. expected: %s, got: %s", e.Friendly(), got.Friendly())
+	}
+	owner, _ := got.Details()["owner"].(map[string]interface{})
+	if owner["name"] != "b5" {
+		t.Errorf("detail mismatch. expected: b5, got: %v", got.Details()["owner"])
+	}
+}
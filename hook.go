@@ -0,0 +1,68 @@
+package errors
+
+import "sync"
+
+// noHooks is the type of NoHooks
+type noHooks struct{}
+
+// NoHooks opts a single error out of hooks registered with RegisterHook.
+// Pass NoHooks as a data argument to any New or Wrap constructor. NoHooks is
+// removed from the error's data. Errors created within a hook must pass
+// NoHooks, or the hook will recurse
+var NoHooks = noHooks{}
+
+// hooks holds functions registered with RegisterHook
+var hooks = struct {
+	sync.RWMutex
+	fns []*func(*Error)
+}{}
+
+// RegisterHook adds a function called with every error created by New, Wrap
+// and their variants, before the error is returned to the caller. Hooks
+// handle cross-cutting concerns like metrics, or enriching errors with build
+// info, without wrapping every constructor call site. Hooks are called in
+// the order they're registered, must be safe for concurrent use, and must
+// not retain the error. RegisterHook returns a function that removes the hook
+func RegisterHook(fn func(*Error)) (remove func()) {
+	h := &fn
+	hooks.Lock()
+	hooks.fns = append(hooks.fns, h)
+	hooks.Unlock()
+	return func() {
+		hooks.Lock()
+		defer hooks.Unlock()
+		for i, fn := range hooks.fns {
+			if fn == h {
+				hooks.fns = append(hooks.fns[:i:i], hooks.fns[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// hooked calls registered hooks with e, returning e. Exported constructors
+// must return through hooked once e is fully built
+func hooked(e *Error) *Error {
+	if e == nil || e.noHooks {
+		return e
+	}
+	hooks.RLock()
+	fns := hooks.fns
+	hooks.RUnlock()
+	for _, fn := range fns {
+		(*fn)(e)
+	}
+	return e
+}
+
+// stripNoHooks removes NoHooks from data, reporting if it was present
+func stripNoHooks(data []interface{}) ([]interface{}, bool) {
+	for i, d := range data {
+		if _, ok := d.(noHooks); ok {
+			stripped := append(data[:i:i], data[i+1:]...)
+			stripped, _ = stripNoHooks(stripped)
+			return stripped, true
+		}
+	}
+	return data, false
+}
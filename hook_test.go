@@ -0,0 +1,72 @@
+package errors
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRegisterHook(t *testing.T) {
+	var seen []*Error
+	remove := RegisterHook(func(e *Error) {
+		seen = append(seen, e)
+		e.WithDetail("build", "v1.2.3")
+	})
+
+	n := New(CodeNotFound, "no dataset")
+	w := WrapFriendly(CodeUnavailable, context.DeadlineExceeded, "fetching", "try again")
+	if len(seen) != 2 || seen[0] != n || seen[1] != w {
+		t.Fatalf("expected hook to be called with every created error. got: %d", len(seen))
+	}
+	if seen[1].FriendlyMessage() != "try again" {
+		t.Errorf("expected hooks to see fully built errors. got friendly: %q", seen[1].FriendlyMessage())
+	}
+	if n.Details()["build"] != "v1.2.3" {
+		t.Errorf("expected hooks to enrich errors. got: %v", n.Details())
+	}
+
+	skipped := New(CodeNotFound, "no dataset", NoHooks, "data")
+	if len(seen) != 2 {
+		t.Errorf("expected NoHooks to skip hooks")
+	}
+	if len(skipped.data) != 1 || skipped.data[0] != "data" {
+		t.Errorf("expected NoHooks to be removed from data. got: %v", skipped.data)
+	}
+
+	remove()
+	New(CodeNotFound, "no dataset")
+	if len(seen) != 2 {
+		t.Errorf("expected removed hook not to be called")
+	}
+}
+
+func TestHookReadsRegistry(t *testing.T) {
+	r := NewRegistry()
+	var slugs []string
+	remove := RegisterHook(func(e *Error) {
+		slugs = append(slugs, e.Registry().CodeSlug(e.Code()))
+		r.Codes()
+	})
+	defer remove()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.RegisterSpec(Code(100), CodeSpec{HTTPStatus: 404, Name: "dataset"})
+		r.RegisterSpec(Code(100), CodeSpec{HTTPStatus: 404, Name: "dataset"})
+		r.RegisterAlias(Code(100), Code(100))
+		r.SetDefaultMessages(Code(200), "", "")
+		r.AllocateRange("", 0)
+		r.ReserveRange("datasets", 0, 10)
+		r.Freeze()
+		r.SetPrecedence()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("registry deadlocked calling a hook that reads the registry")
+	}
+	if len(slugs) != 6 {
+		t.Errorf("expected hooks to be called with every registry error. got: %d", len(slugs))
+	}
+}
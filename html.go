@@ -0,0 +1,144 @@
+package errors
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+	"net/http"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+// HTMLPage is the data html error page templates are executed with
+type HTMLPage struct {
+	// Status is the http status code
+	Status int
+	// Code is the code of the error
+	Code Code
+	// Slug is the slug of the code
+	Slug string
+	// Title is the name of the code
+	Title string
+	// Friendly is the user-facing message
+	Friendly string
+	// Fix is the message on how to fix the error
+	Fix string
+	// HelpURL is the docs url for the code
+	HelpURL string
+}
+
+// DefaultHTMLTemplate renders error pages for codes without a template of
+// their own
+var DefaultHTMLTemplate = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Status}} {{.Title}}</title>
+<style>
+body { margin: 0; font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; background: #f6f7f9; color: #1f2933; }
+main { max-width: 560px; margin: 12vh auto; padding: 32px; background: #fff; border-radius: 8px; box-shadow: 0 1px 4px rgba(0, 0, 0, 0.1); }
+.status { margin: 0; font-size: 14px; letter-spacing: 0.08em; text-transform: uppercase; color: #7b8794; }
+h1 { margin: 8px 0 16px; font-size: 24px; }
+.fix { padding: 12px 16px; background: #f0f4f8; border-radius: 4px; }
+a { color: #2186eb; }
+</style>
+</head>
+<body>
+<main>
+<p class="status">{{.Status}} {{.Title}}</p>
+<h1>{{.Friendly}}</h1>
+{{if .Fix}}<p class="fix">{{.Fix}}</p>{{end}}
+{{if .HelpURL}}<p><a href="{{.HelpURL}}">Learn more about this error</a></p>{{end}}
+</main>
+</body>
+</html>
+`))
+
+// htmlTemplates holds error page templates for specific codes
+var htmlTemplates = struct {
+	lk     sync.RWMutex
+	byCode map[Code]*template.Template
+}{byCode: map[Code]*template.Template{}}
+
+// SetHTMLTemplate sets the template used to render error pages for a code,
+// overriding DefaultHTMLTemplate. Templates are executed with an HTMLPage.
+// Setting a nil template removes the override
+func SetHTMLTemplate(c Code, tmpl *template.Template) {
+	htmlTemplates.lk.Lock()
+	defer htmlTemplates.lk.Unlock()
+	if tmpl == nil {
+		delete(htmlTemplates.byCode, c)
+		return
+	}
+	htmlTemplates.byCode[c] = tmpl
+}
+
+// htmlTemplate returns the template for a code
+func htmlTemplate(c Code) *template.Template {
+	htmlTemplates.lk.RLock()
+	defer htmlTemplates.lk.RUnlock()
+	if tmpl, ok := htmlTemplates.byCode[c]; ok {
+		return tmpl
+	}
+	return DefaultHTMLTemplate
+}
+
+// NewHTMLPage creates the data for an error page from an error, using the
+// code ResolveCode picks for err. The friendly message defaults to the
+// status text, so pages never show machine messages. Pages render in
+// DefaultLanguage, use NewHTMLPageIn for other languages
+func NewHTMLPage(err error) HTMLPage {
+	return NewHTMLPageIn(err, DefaultLanguage)
+}
+
+// NewHTMLPageIn is like NewHTMLPage, rendering the friendly message & fix in
+// lang. The page is built from the same error the HTTP writers write in
+// DefaultOutputMode
+func NewHTMLPageIn(err error, lang language.Tag) HTMLPage {
+	return htmlPage(outputError(err, lang, OutputDefault))
+}
+
+// htmlPage creates the data for an error page from an error that's been
+// prepared for output
+func htmlPage(e *Error) HTMLPage {
+	reg := e.Registry()
+	page := HTMLPage{
+		Status:   reg.CodeHTTPStatus(e.code),
+		Code:     e.code,
+		Slug:     reg.CodeSlug(e.code),
+		Title:    reg.CodeString(e.code),
+		Friendly: e.friendlyMessage(),
+		Fix:      e.Fix(),
+		HelpURL:  reg.CodeDocsURL(e.code),
+	}
+	if page.Friendly == "" {
+		page.Friendly = http.StatusText(page.Status)
+	}
+	return page
+}
+
+// RenderHTML writes err as an html error page, using the template set for
+// the error's code with SetHTMLTemplate or DefaultHTMLTemplate
+func RenderHTML(w io.Writer, err error) error {
+	return RenderHTMLIn(w, err, DefaultLanguage)
+}
+
+// RenderHTMLIn is like RenderHTML, rendering the page in lang
+func RenderHTMLIn(w io.Writer, err error, lang language.Tag) error {
+	return executeHTML(w, NewHTMLPageIn(err, lang))
+}
+
+// executeHTML executes the template for a page's code
+func executeHTML(w io.Writer, page HTMLPage) error {
+	return htmlTemplate(page.Code).Execute(w, page)
+}
+
+// renderHTML renders an error page into a byte slice. The HTTP writers pass
+// errors already localized & redacted, so the page is built from e as is
+func renderHTML(e *Error) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	err := executeHTML(buf, htmlPage(resolvedError(e)))
+	return buf.Bytes(), err
+}
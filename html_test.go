@@ -0,0 +1,93 @@
+package errors
+
+import (
+	"bytes"
+	"html/template"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+	textcatalog "golang.org/x/text/message/catalog"
+)
+
+func TestRenderHTML(t *testing.T) {
+	r := NewRegistry()
+	CodeQuota := Code(100)
+	r.RegisterSpec(CodeQuota, CodeSpec{
+		HTTPStatus: 402,
+		Name:       "quota",
+		DocsURL:    "https://qri.io/problems/quota",
+	})
+	e := NewFriendlyFix(CodeQuota, "quota exceeded", "you're out of <storage>", "delete some datasets").WithRegistry(r)
+
+	buf := &bytes.Buffer{}
+	if err := RenderHTML(buf, e); err != nil {
+		t.Fatal(err)
+	}
+	for _, expect := range []string{
+		"402 quota",
+		"you&#39;re out of &lt;storage&gt;",
+		"delete some datasets",
+		`href="https://qri.io/problems/quota"`,
+	} {
+		if !strings.Contains(buf.String(), expect) {
+			t.Errorf("expected page to contain %q. got: %s", expect, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := RenderHTML(buf, New(CodeGeneric, "something broke")); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "something broke") {
+		t.Errorf("expected machine message to be left off the page. got: %s", buf.String())
+	}
+}
+
+func TestSetHTMLTemplate(t *testing.T) {
+	SetHTMLTemplate(CodeNotFound, template.Must(template.New("not_found").Parse(`<h1>nothing at {{.Slug}}</h1>`)))
+	defer SetHTMLTemplate(CodeNotFound, nil)
+
+	buf := &bytes.Buffer{}
+	if err := RenderHTML(buf, New(CodeNotFound, "no dataset")); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "<h1>nothing at not_found</h1>" {
+		t.Errorf("page mismatch. expected: %s, got: %s", "<h1>nothing at not_found</h1>", buf.String())
+	}
+
+	SetHTMLTemplate(CodeNotFound, nil)
+	buf.Reset()
+	if err := RenderHTML(buf, New(CodeNotFound, "no dataset")); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "<!DOCTYPE html>") {
+		t.Errorf("expected removing a template to restore the default")
+	}
+}
+
+func TestRenderHTMLLanguage(t *testing.T) {
+	cat := textcatalog.NewBuilder()
+	cat.SetString(language.Spanish, MessageID(CodeUnauthorized, FriendlyMessageField), "necesitas iniciar sesión")
+	SetCatalog(cat)
+	defer SetCatalog(nil)
+
+	buf := &bytes.Buffer{}
+	if err := RenderHTMLIn(buf, New(CodeUnauthorized, "token expired"), language.Spanish); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "<h1>necesitas iniciar sesión</h1>") {
+		t.Errorf("expected page to be translated. got: %s", buf.String())
+	}
+
+	hw := &HTTPWriter{Mode: OutputExternal}
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept", "text/html")
+	r.Header.Set("Accept-Language", "es")
+	w := httptest.NewRecorder()
+	hw.WriteRequest(w, r, New(CodeUnauthorized, "token expired"))
+	if !strings.Contains(w.Body.String(), "<h1>necesitas iniciar sesión</h1>") {
+		t.Errorf("expected written page to be translated. got: %s", w.Body.String())
+	}
+}
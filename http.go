@@ -0,0 +1,464 @@
+package errors
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// JSONContentType is the media type of JSON error bodies
+const JSONContentType = "application/json; charset=utf-8"
+
+// ErrorCodeHeader is the http header HTTP writers set to the slug of an
+// error's code, so intermediaries and frontends can branch on codes without
+// parsing bodies
+const ErrorCodeHeader = "X-Error-Code"
+
+// RequestIDHeader is the http header HTTP writers set to the id of the
+// request an error response answers
+const RequestIDHeader = "X-Request-Id"
+
+// requestIDKey is the context key for request ids
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx holding a request or trace id,
+// which HTTP writers include in error responses so users can quote an id
+// support can find in logs
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the id set with ContextWithRequestID, or an
+// empty string
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// resolvedError finds the Error to present for err, with it's code replaced
+// by the canonical code ResolveCode picks. Errors that aren't an *Error are
+// wrapped with CodeUnknown, or the code of a context error
+func resolvedError(err error) *Error {
+	e, ok := AsError(err)
+	if !ok {
+		e = &Error{code: contextCode(err), cause: err}
+	}
+	resolved := *e
+	resolved.code = e.Registry().Canonical(ResolveCode(e))
+	return &resolved
+}
+
+// outputError prepares err for writing, resolving its code, rendering the
+// friendly message & fix in lang, and redacting it for mode. Localizing comes
+// before redacting, which fixes the friendly message in place and hides the
+// code's message ids
+func outputError(err error, lang language.Tag, mode OutputMode) *Error {
+	return resolvedError(err).localized(lang).forOutput(mode)
+}
+
+// withDetailCopy returns a copy of e with an added detail, leaving e's
+// details untouched
+func (e Error) withDetailCopy(key string, value interface{}) *Error {
+	details := make(map[string]interface{}, len(e.details)+1)
+	for k, v := range e.details {
+		details[k] = v
+	}
+	details[key] = value
+	e.details = details
+	return &e
+}
+
+// WithHeader adds an http header the HTTP writers emit with the error, like
+// Retry-After for rate limits or WWW-Authenticate for auth challenges
+func (e *Error) WithHeader(key, value string) *Error {
+	if e.headers == nil {
+		e.headers = map[string][]string{}
+	}
+	http.Header(e.headers).Add(key, value)
+	return e
+}
+
+// Headers returns the http headers attached to the error
+func (e Error) Headers() http.Header {
+	return http.Header(e.headers).Clone()
+}
+
+// httpHeaders collects the headers attached to every Error in err's chain,
+// including rate limit headers. Headers attached to outer errors replace the
+// same header on inner errors, and attached headers replace rate limit
+// headers
+func httpHeaders(err error) http.Header {
+	h := http.Header{}
+	var rl *RateLimit
+	walk(err, func(err error) bool {
+		if e, ok := err.(*Error); ok {
+			for key, vals := range e.headers {
+				if _, ok := h[key]; !ok {
+					h[key] = append([]string(nil), vals...)
+				}
+			}
+			if rl == nil {
+				rl = e.rateLimit
+			}
+		}
+		return false
+	})
+	if rl != nil {
+		for key, vals := range rl.headers(time.Now()) {
+			if _, ok := h[key]; !ok {
+				h[key] = vals
+			}
+		}
+	}
+	return h
+}
+
+// HTTPWriter writes errors as http responses
+type HTTPWriter struct {
+	// Mode sets how much of each error is written. The default, OutputDefault,
+	// uses DefaultOutputMode
+	Mode OutputMode
+	// DebugHeader names a request header that switches a response to
+	// OutputDebug when the header holds DebugToken. Debug requests are
+	// disabled unless both are set
+	DebugHeader string
+	// DebugToken is the secret value of DebugHeader
+	DebugToken string
+	// Audience is a policy deciding who a request comes from, like checking
+	// for an internal network header or an auth role. The audience's output
+	// mode replaces Mode, unless the policy returns AudienceUnknown
+	Audience func(r *http.Request) Audience
+	// RequestID returns the id of a request, which is written in the
+	// "request_id" detail of the body and in the RequestIDHeader header. The
+	// default reads the id set with ContextWithRequestID
+	RequestID func(r *http.Request) string
+}
+
+// DefaultHTTPWriter is the writer WriteHTTP and WriteHTTPRequest use
+var DefaultHTTPWriter = &HTTPWriter{}
+
+// WriteHTTP writes err as a JSON http response with DefaultHTTPWriter
+func WriteHTTP(w http.ResponseWriter, err error) {
+	DefaultHTTPWriter.Write(w, err)
+}
+
+// WriteHTTPRequest writes err as an http response in the media type r accepts
+// with DefaultHTTPWriter
+func WriteHTTPRequest(w http.ResponseWriter, r *http.Request, err error) {
+	DefaultHTTPWriter.WriteRequest(w, r, err)
+}
+
+// Write writes err as a JSON http response. The status comes from the code
+// ResolveCode picks for err, and the body holds the same fields as
+// MarshalJSON, including the friendly message, fix & details. In external
+// mode only the code, friendly message & fix are written, while debug mode
+// adds the cause chain & stack traces written by MarshalJSONDebug. Headers
+// attached with WithHeader are written with the response, and the code's slug
+// is written in the ErrorCodeHeader header. Templates set with
+// SetBodyTemplate replace the body for their code. Errors that aren't an
+// *Error are written with CodeUnknown. A nil error writes nothing
+func (hw *HTTPWriter) Write(w http.ResponseWriter, err error) {
+	hw.write(w, nil, err, "application/json")
+}
+
+// WriteRequest writes err as an http response in the media type that best
+// matches the Accept header of r, falling back to JSON. Any content type with
+// a registered Serializer can be negotiated, which includes JSON, problem
+// details, XML, plain text and HTML. The friendly message & fix are rendered
+// in the language NegotiateLanguage picks for the Accept-Language header, and
+// the language is written in the Content-Language header. The request id is
+// written in the body & headers. Responses are otherwise the same as Write
+func (hw *HTTPWriter) WriteRequest(w http.ResponseWriter, r *http.Request, err error) {
+	hw.write(w, r, err, NegotiateContentType(r.Header.Get("Accept")))
+}
+
+// requestID returns the id of a request, which may be nil
+func (hw *HTTPWriter) requestID(r *http.Request) string {
+	if r == nil {
+		return ""
+	}
+	if hw.RequestID != nil {
+		return hw.RequestID(r)
+	}
+	return RequestIDFromContext(r.Context())
+}
+
+// mode picks the output mode for a request, which may be nil
+func (hw *HTTPWriter) mode(r *http.Request) OutputMode {
+	if r != nil && hw.DebugHeader != "" && hw.DebugToken != "" {
+		token := r.Header.Get(hw.DebugHeader)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(hw.DebugToken)) == 1 {
+			return OutputDebug
+		}
+	}
+	if r != nil && hw.Audience != nil {
+		if mode := hw.Audience(r).OutputMode(); mode != OutputDefault {
+			return mode
+		}
+	}
+	return hw.Mode.resolve()
+}
+
+// write writes err as an http response with the serializer for mediaType
+func (hw *HTTPWriter) write(w http.ResponseWriter, r *http.Request, err error, mediaType string) {
+	if err == nil {
+		return
+	}
+	mode, lang := hw.mode(r), DefaultLanguage
+	if r != nil {
+		if accept := r.Header.Get("Accept-Language"); accept != "" {
+			lang = NegotiateLanguage(accept)
+		}
+	}
+	e := outputError(err, lang, mode)
+	id := hw.requestID(r)
+	if id != "" {
+		e = e.withDetailCopy(RequestIDDetail, id)
+	}
+	s, ok := SerializerFor(mediaType)
+	if !ok {
+		mediaType = "application/json"
+		s, _ = SerializerFor(mediaType)
+	}
+
+	var data []byte
+	var merr error
+	if tmpl, ok := bodyTemplate(e.code, mediaType); ok {
+		data, merr = renderBody(tmpl, e)
+	} else if mediaType == "application/json" {
+		data = marshalJSONBody(err, e, mode)
+	} else {
+		data, merr = s.Marshal(e)
+	}
+	if merr != nil {
+		mediaType = "application/json"
+		data = fallbackJSONBody(e)
+	}
+
+	h := w.Header()
+	for key, vals := range httpHeaders(err) {
+		h[key] = vals
+	}
+	h.Set("Content-Type", withCharset(mediaType))
+	h.Set(ErrorCodeHeader, e.Registry().CodeSlug(e.code))
+	if id != "" {
+		h.Set(RequestIDHeader, id)
+	}
+	h.Set("Content-Language", lang.String())
+	h.Set("X-Content-Type-Options", "nosniff")
+	h.Add("Vary", "Accept")
+	h.Add("Vary", "Accept-Language")
+	if mode == OutputDebug {
+		h.Set("Cache-Control", "no-store")
+	}
+	w.WriteHeader(e.Registry().CodeHTTPStatus(e.code))
+	w.Write(data)
+}
+
+// marshalJSONBody marshals the JSON body written for err in mode, where e is
+// err resolved for output. Debug mode adds the cause chain & stack traces.
+// Bodies over SizeBudget are truncated
+func marshalJSONBody(err error, e *Error, mode OutputMode) []byte {
+	var data []byte
+	var merr error
+	if mode == OutputDebug {
+		je := chainJSONError(err, maxDebugDepth, true)
+		je.Version = WireVersion
+		je.Code = e.code
+		je.Slug = e.Registry().CodeSlug(e.code)
+		je.Details = e.details
+		data, merr = marshalBudget(je)
+	} else {
+		s, _ := SerializerFor("application/json")
+		data, merr = s.Marshal(e)
+		if merr == nil && SizeBudget > 0 && len(data) > SizeBudget {
+			data, merr = marshalBudget(e.wireError())
+		}
+	}
+	if merr != nil {
+		return fallbackJSONBody(e)
+	}
+	return data
+}
+
+// fallbackJSONBody marshals the code & message of an error that couldn't be
+// serialized
+func fallbackJSONBody(e *Error) []byte {
+	reg := e.Registry()
+	data, _ := json.Marshal(jsonError{Version: WireVersion, Code: e.code, Slug: reg.CodeSlug(e.code), Message: e.Message()})
+	return data
+}
+
+// HandlerFunc is an http handler that returns an error instead of writing
+// error responses itself
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// LogHTTPError is called with every error a HandlerFunc returns, and should
+// log the machine message. It defaults to the standard library logger, and
+// can be replaced to use another logger, or set to nil to disable logging
+var LogHTTPError = func(r *http.Request, err error) {
+	log.Printf("%s %s: %s", r.Method, r.URL.Path, err)
+}
+
+// ServeHTTP implements the http.Handler interface, calling fn. Errors fn
+// returns are logged with LogHTTPError, then written with WriteHTTPRequest.
+// If fn has already started the response the error is only logged
+func (fn HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	sw := &statusWriter{ResponseWriter: w}
+	err := fn(sw, r)
+	if err == nil {
+		return
+	}
+	if LogHTTPError != nil {
+		LogHTTPError(r, err)
+	}
+	if !sw.wroteHeader {
+		WriteHTTPRequest(w, r, err)
+	}
+}
+
+// ReportPanic is called with every panic Recover recovers, as an Error with
+// CodeInternal holding the stack of the panic. It defaults to logging the
+// panic & stack with the standard library logger, and sending the panic to
+// the reporter set with SetReporter
+var ReportPanic = func(r *http.Request, err *Error) {
+	buf := &strings.Builder{}
+	for _, f := range err.StackTrace() {
+		fmt.Fprintf(buf, "\n\t%s", f)
+	}
+	log.Printf("%s %s: %s%s", r.Method, r.URL.Path, err, buf)
+	Report(r.Context(), err)
+}
+
+// Recover is middleware that recovers panics in next. Panics are reported
+// with ReportPanic, and the client gets a 500 response that doesn't describe
+// the panic. Panics with http.ErrAbortHandler are passed through
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw := &statusWriter{ResponseWriter: w}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+			var e *Error
+			if err, ok := v.(error); ok {
+				e = Wrap(CodeInternal, err, "panic")
+			} else {
+				e = New(CodeInternal, fmt.Sprintf("panic: %v", v))
+			}
+			if ReportPanic != nil {
+				ReportPanic(r, e)
+			}
+			if !sw.wroteHeader {
+				WriteHTTPRequest(w, r, New(CodeInternal, "internal server error", NoHooks))
+			}
+		}()
+		next.ServeHTTP(sw, r)
+	})
+}
+
+// statusWriter records whether a response has been started
+type statusWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+// WriteHeader implements the http.ResponseWriter interface
+func (sw *statusWriter) WriteHeader(status int) {
+	sw.wroteHeader = true
+	sw.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements the http.ResponseWriter interface
+func (sw *statusWriter) Write(p []byte) (int, error) {
+	sw.wroteHeader = true
+	return sw.ResponseWriter.Write(p)
+}
+
+// Flush implements the http.Flusher interface when the underlying writer
+// does
+func (sw *statusWriter) Flush() {
+	if f, ok := sw.ResponseWriter.(http.Flusher); ok {
+		sw.wroteHeader = true
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer for http.ResponseController
+func (sw *statusWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}
+
+// withCharset adds a utf-8 charset parameter to textual media types
+func withCharset(mediaType string) string {
+	if strings.HasPrefix(mediaType, "text/") ||
+		strings.HasSuffix(mediaType, "json") ||
+		strings.HasSuffix(mediaType, "xml") {
+		return mediaType + "; charset=utf-8"
+	}
+	return mediaType
+}
+
+// NegotiateContentType picks the registered serializer media type that best
+// matches an Accept header, preferring higher quality values, then earlier
+// entries. Wildcards match JSON first. Headers that accept no registered type
+// negotiate JSON
+func NegotiateContentType(accept string) string {
+	types := SerializerContentTypes()
+	best, bestQ := "application/json", 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if qs, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(qs, 64); err != nil {
+				continue
+			}
+		}
+		if q <= bestQ {
+			continue
+		}
+		if match, ok := matchMediaType(mediaType, types); ok {
+			best, bestQ = match, q
+		}
+	}
+	return best
+}
+
+// matchMediaType finds the media type in types that satisfies an accepted
+// media type, which may be a wildcard
+func matchMediaType(accepted string, types []string) (string, bool) {
+	if accepted == "*/*" || accepted == "application/*" {
+		return "application/json", true
+	}
+	if strings.HasSuffix(accepted, "/*") {
+		prefix := strings.TrimSuffix(accepted, "*")
+		for _, t := range types {
+			if strings.HasPrefix(t, prefix) {
+				return t, true
+			}
+		}
+		return "", false
+	}
+	for _, t := range types {
+		if t == accepted {
+			return t, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,390 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWriteHTTP(t *testing.T) {
+	w := httptest.NewRecorder()
+	WriteHTTP(w, NewFriendlyFix(CodeNotFound, "no dataset", "couldn't find that dataset", "check the name").WithDetail("ref", "b5/world_bank"))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status mismatch. expected: %d, got: %d", http.StatusNotFound, w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != JSONContentType {
+		t.Errorf("content type mismatch. expected: %s, got: %s", JSONContentType, ct)
+	}
+	body := map[string]interface{}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]interface{}{
+		"slug":     "not_found",
+		"friendly": "couldn't find that dataset",
+		"fix":      "check the name",
+	}
+	for key, val := range expect {
+		if body[key] != val {
+			t.Errorf("body %s mismatch. expected: %v, got: %v", key, val, body[key])
+		}
+	}
+	if details, _ := body["details"].(map[string]interface{}); details["ref"] != "b5/world_bank" {
+		t.Errorf("expected details in body. got: %v", body["details"])
+	}
+}
+
+func TestWriteHTTPResolvesCode(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := WrapAll(CodeInvalidArgs, []error{New(CodeInvalidArgs, "bad name"), New(CodeUnauthorized, "no token")}, "invalid request")
+	WriteHTTP(w, err)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status mismatch. expected: %d, got: %d", http.StatusUnauthorized, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	WriteHTTP(w, context.DeadlineExceeded)
+	if w.Code != CodeHTTPStatus(CodeTimeout) {
+		t.Errorf("status mismatch. expected: %d, got: %d", CodeHTTPStatus(CodeTimeout), w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	WriteHTTP(w, nil)
+	if w.Body.Len() != 0 {
+		t.Errorf("expected nil error to write nothing. got: %s", w.Body.String())
+	}
+}
+
+func TestWriteHTTPPlainError(t *testing.T) {
+	w := httptest.NewRecorder()
+	WriteHTTP(w, io.ErrUnexpectedEOF)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status mismatch. expected: %d, got: %d", http.StatusInternalServerError, w.Code)
+	}
+	body := map[string]interface{}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body["message"] != io.ErrUnexpectedEOF.Error() {
+		t.Errorf("message mismatch. expected: %s, got: %v", io.ErrUnexpectedEOF, body["message"])
+	}
+}
+
+func TestNegotiateContentType(t *testing.T) {
+	cases := []struct {
+		accept, expect string
+	}{
+		{"", "application/json"},
+		{"*/*", "application/json"},
+		{"application/problem+json", ProblemContentType},
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", "text/html"},
+		{"application/xml;q=0.5, text/plain", "text/plain"},
+		{"text/*", "text/html"},
+		{"image/png", "application/json"},
+		{"text/html;q=0", "application/json"},
+		{"application/xml;q=nope, text/plain", "text/plain"},
+	}
+	for _, c := range cases {
+		if got := NegotiateContentType(c.accept); got != c.expect {
+			t.Errorf("%q negotiation mismatch. expected: %s, got: %s", c.accept, c.expect, got)
+		}
+	}
+}
+
+func TestWriteHTTPRequest(t *testing.T) {
+	e := NewFriendly(CodeNotFound, "no dataset", "couldn't find <that> dataset")
+	cases := []struct {
+		accept, contentType, contains string
+	}{
+		{"application/json", JSONContentType, `"slug":"not_found"`},
+		{"application/problem+json", ProblemContentType + "; charset=utf-8", `"status":404`},
+		{"application/xml", "application/xml; charset=utf-8", `slug="not_found"`},
+		{"text/plain", "text/plain; charset=utf-8", "code=not_found"},
+		{"text/html", "text/html; charset=utf-8", "couldn&#39;t find &lt;that&gt; dataset"},
+	}
+	for _, c := range cases {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept", c.accept)
+		w := httptest.NewRecorder()
+		WriteHTTPRequest(w, r, e)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("%s status mismatch. expected: %d, got: %d", c.accept, http.StatusNotFound, w.Code)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != c.contentType {
+			t.Errorf("%s content type mismatch. expected: %s, got: %s", c.accept, c.contentType, ct)
+		}
+		if !strings.Contains(w.Body.String(), c.contains) {
+			t.Errorf("%s body mismatch. expected to contain: %s, got: %s", c.accept, c.contains, w.Body.String())
+		}
+	}
+}
+
+func TestHandlerFunc(t *testing.T) {
+	var logged []string
+	prev := LogHTTPError
+	LogHTTPError = func(r *http.Request, err error) {
+		logged = append(logged, err.Error())
+	}
+	defer func() { LogHTTPError = prev }()
+
+	h := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return NewFriendly(CodeForbidden, "user b5 can't edit", "you can't edit this dataset")
+	})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/edit", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status mismatch. expected: %d, got: %d", http.StatusForbidden, w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "you can't edit this dataset") {
+		t.Errorf("expected friendly message in body. got: %s", w.Body.String())
+	}
+	if len(logged) != 1 || logged[0] != "auth: user b5 can't edit" {
+		t.Errorf("log mismatch. expected: %s, got: %v", "auth: user b5 can't edit", logged)
+	}
+
+	started := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		w.WriteHeader(http.StatusAccepted)
+		return New(CodeInternal, "stream failed")
+	})
+	w = httptest.NewRecorder()
+	started.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusAccepted || w.Body.Len() != 0 {
+		t.Errorf("expected started responses to be left alone. got: %d %s", w.Code, w.Body.String())
+	}
+	if len(logged) != 2 {
+		t.Errorf("expected errors after a response started to be logged")
+	}
+}
+
+func TestRecover(t *testing.T) {
+	var reported *Error
+	prev := ReportPanic
+	ReportPanic = func(r *http.Request, err *Error) {
+		reported = err
+	}
+	defer func() { ReportPanic = prev }()
+	hooked := 0
+	remove := RegisterHook(func(*Error) { hooked++ })
+	defer remove()
+
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panicOnNil(nil)
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status mismatch. expected: %d, got: %d", http.StatusInternalServerError, w.Code)
+	}
+	if strings.Contains(w.Body.String(), "nil map") {
+		t.Errorf("expected panic to be hidden from the client. got: %s", w.Body.String())
+	}
+	if reported == nil {
+		t.Fatal("expected panic to be reported")
+	}
+	if hooked != 1 {
+		t.Errorf("expected a panic to call hooks once. got: %d", hooked)
+	}
+	if reported.Code() != CodeInternal || !strings.Contains(reported.Error(), "nil map") {
+		t.Errorf("reported error mismatch. got: %s", reported)
+	}
+	found := false
+	for _, f := range reported.StackTrace() {
+		if strings.HasSuffix(f.Function, "panicOnNil") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected reported stack to include the panic site. got: %v", reported.StackTrace())
+	}
+
+	h = Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if reported.Message() != "panic: boom" {
+		t.Errorf("message mismatch. expected: %s, got: %s", "panic: boom", reported.Message())
+	}
+}
+
+func panicOnNil(m map[string]int) {
+	m["a"] = 1
+}
+
+func TestWriteHTTPHeaders(t *testing.T) {
+	inner := New(CodeTooManyRequests, "rate limited").
+		WithHeader("Retry-After", "30").
+		WithHeader("X-Ratelimit-Scope", "user")
+	err := Wrap(CodeTooManyRequests, inner, "listing datasets").WithHeader("retry-after", "10")
+
+	if got := err.Headers().Get("Retry-After"); got != "10" {
+		t.Errorf("header mismatch. expected: %s, got: %s", "10", got)
+	}
+
+	w := httptest.NewRecorder()
+	WriteHTTP(w, err)
+	if got := w.Header().Values("Retry-After"); len(got) != 1 || got[0] != "10" {
+		t.Errorf("expected outer header to replace inner. got: %v", got)
+	}
+	if got := w.Header().Get("X-Ratelimit-Scope"); got != "user" {
+		t.Errorf("expected inner headers to be written. got: %q", got)
+	}
+	if got := w.Header().Get("Content-Type"); got != JSONContentType {
+		t.Errorf("expected error headers not to replace content type. got: %s", got)
+	}
+}
+
+func TestHTTPWriterExternal(t *testing.T) {
+	e := NewFriendlyFix(CodeNotFound, "select * from datasets where id = 7", "couldn't find that dataset", "check the name", "secret-token").
+		WithDetail("table", "datasets").
+		WithField("id")
+
+	hw := &HTTPWriter{Mode: OutputExternal}
+	for _, accept := range []string{"application/json", ProblemContentType, "application/xml", "text/plain", "text/html"} {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		hw.WriteRequest(w, r, e)
+
+		body := w.Body.String()
+		for _, hidden := range []string{"select *", "secret-token", "datasets\"", "table"} {
+			if strings.Contains(body, hidden) {
+				t.Errorf("%s: expected external output to hide %q. got: %s", accept, hidden, body)
+			}
+		}
+		if !strings.Contains(body, "find that dataset") || !strings.Contains(body, "check the name") {
+			t.Errorf("%s: expected external output to keep friendly message & fix. got: %s", accept, body)
+		}
+		if w.Code != http.StatusNotFound {
+			t.Errorf("%s status mismatch. expected: %d, got: %d", accept, http.StatusNotFound, w.Code)
+		}
+	}
+
+	prev := DefaultOutputMode
+	DefaultOutputMode = OutputExternal
+	defer func() { DefaultOutputMode = prev }()
+	w := httptest.NewRecorder()
+	WriteHTTP(w, e)
+	if strings.Contains(w.Body.String(), "select *") {
+		t.Errorf("expected default output mode to apply to WriteHTTP. got: %s", w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	(&HTTPWriter{Mode: OutputInternal}).Write(w, e)
+	if !strings.Contains(w.Body.String(), "select *") {
+		t.Errorf("expected writer mode to override the default. got: %s", w.Body.String())
+	}
+}
+
+func TestHTTPWriterDebug(t *testing.T) {
+	inner := New(CodeUnavailable, "connection refused")
+	e := Wrap(CodeNotFound, inner, "loading dataset")
+
+	hw := &HTTPWriter{Mode: OutputExternal, DebugHeader: "X-Debug", DebugToken: "s3cret"}
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("X-Debug", "s3cret")
+	w := httptest.NewRecorder()
+	hw.WriteRequest(w, r, e)
+
+	body := struct {
+		Code  Code                   `json:"code"`
+		Stack []Frame                `json:"stack"`
+		Cause map[string]interface{} `json:"cause"`
+	}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Code != ResolveCode(e) {
+		t.Errorf("code mismatch. expected: %d, got: %d", ResolveCode(e), body.Code)
+	}
+	if len(body.Stack) == 0 || !strings.HasSuffix(body.Stack[0].Function, "TestHTTPWriterDebug") {
+		t.Errorf("expected stack in debug output. got: %v", body.Stack)
+	}
+	if body.Cause["message"] != "connection refused" || body.Cause["stack"] == nil {
+		t.Errorf("expected cause chain with stacks in debug output. got: %v", body.Cause)
+	}
+	if w.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("expected debug responses not to be cached")
+	}
+
+	r.Header.Set("X-Debug", "guess")
+	w = httptest.NewRecorder()
+	hw.WriteRequest(w, r, e)
+	if strings.Contains(w.Body.String(), "stack") || strings.Contains(w.Body.String(), "connection refused") {
+		t.Errorf("expected wrong debug token to get external output. got: %s", w.Body.String())
+	}
+}
+
+func TestHTTPWriterRequestID(t *testing.T) {
+	e := New(CodeNotFound, "no dataset").WithDetail("ref", "b5/world_bank")
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(ContextWithRequestID(r.Context(), "req-123"))
+
+	hw := &HTTPWriter{Mode: OutputExternal}
+	w := httptest.NewRecorder()
+	hw.WriteRequest(w, r, e)
+	if got := w.Header().Get(RequestIDHeader); got != "req-123" {
+		t.Errorf("header mismatch. expected: %s, got: %s", "req-123", got)
+	}
+	if !strings.Contains(w.Body.String(), `"request_id":"req-123"`) {
+		t.Errorf("expected request id in external body. got: %s", w.Body.String())
+	}
+	if _, ok := e.Details()["request_id"]; ok {
+		t.Errorf("expected writing not to modify the error's details")
+	}
+
+	hw = &HTTPWriter{RequestID: func(r *http.Request) string { return r.Header.Get("X-Trace") }}
+	r = httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("X-Trace", "trace-9")
+	w = httptest.NewRecorder()
+	hw.WriteRequest(w, r, e)
+	if got := w.Header().Get(RequestIDHeader); got != "trace-9" {
+		t.Errorf("header mismatch. expected: %s, got: %s", "trace-9", got)
+	}
+}
+
+func TestHTTPWriterAudience(t *testing.T) {
+	e := Wrap(CodeNotFound, New(CodeNotFound, "no rows"), "loading dataset")
+	hw := &HTTPWriter{
+		Mode: OutputExternal,
+		Audience: func(r *http.Request) Audience {
+			switch r.Header.Get("X-Role") {
+			case "operator":
+				return AudienceOperator
+			case "developer":
+				return AudienceDeveloper
+			case "user":
+				return AudienceUser
+			}
+			return AudienceUnknown
+		},
+	}
+
+	cases := []struct {
+		role           string
+		message, stack bool
+	}{
+		{"", false, false},
+		{"user", false, false},
+		{"operator", true, false},
+		{"developer", true, true},
+	}
+	for _, c := range cases {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("X-Role", c.role)
+		w := httptest.NewRecorder()
+		hw.WriteRequest(w, r, e)
+		body := w.Body.String()
+		if got := strings.Contains(body, "no rows"); got != c.message {
+			t.Errorf("%q: expected message shown: %t. got: %s", c.role, c.message, body)
+		}
+		if got := strings.Contains(body, `"stack"`); got != c.stack {
+			t.Errorf("%q: expected stack shown: %t. got: %s", c.role, c.stack, body)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package errors
+
+import (
+	"encoding/json"
+	goerrors "errors"
+)
+
+// jsonError is the serialized form of an Error, used for JSON & YAML. CBOR and
+// MessagePack codecs encode it through it's json struct tags
+type jsonError struct {
+	Version     int                    `json:"version,omitempty" yaml:"version,omitempty"`
+	Code        Code                   `json:"code" yaml:"code"`
+	Slug        string                 `json:"slug,omitempty" yaml:"slug,omitempty"`
+	Message     string                 `json:"message" yaml:"message"`
+	Friendly    string                 `json:"friendly,omitempty" yaml:"friendly,omitempty"`
+	Fix         string                 `json:"fix,omitempty" yaml:"fix,omitempty"`
+	FixCommands []string               `json:"fix_commands,omitempty" yaml:"fix_commands,omitempty"`
+	Field       string                 `json:"field,omitempty" yaml:"field,omitempty"`
+	Data        []interface{}          `json:"data,omitempty" yaml:"data,omitempty"`
+	Details     map[string]interface{} `json:"details,omitempty" yaml:"details,omitempty"`
+	Cause       *jsonError             `json:"cause,omitempty" yaml:"cause,omitempty"`
+	Causes      []jsonError            `json:"causes,omitempty" yaml:"causes,omitempty"`
+	Stack       []Frame                `json:"stack,omitempty" yaml:"stack,omitempty"`
+	// Truncated marks errors cut down to fit SizeBudget
+	Truncated bool `json:"truncated,omitempty" yaml:"truncated,omitempty"`
+}
+
+// WireVersion is the version of the serialized error layout this package
+// writes. Serialized errors carry their version so services on different
+// releases can keep exchanging errors as the layout evolves. Unversioned
+// errors, written before the version field existed, share the version 1
+// layout and decode as-is. Errors written by newer versions decode on a
+// best-effort basis: fields this version knows are kept, and unknown fields
+// are dropped
+const WireVersion = 1
+
+// wireError creates the top-level serialized form of an error, stamped with
+// the current wire version
+func (e Error) wireError() jsonError {
+	je := e.toJSONError()
+	je.Version = WireVersion
+	return je
+}
+
+// WireValue returns the serialized form of an error, a struct holding the same
+// fields as MarshalJSON, named by json struct tags. Codecs outside this
+// package, like errorscbor & errorsmsgpack, encode it directly
+func (e Error) WireValue() interface{} {
+	return e.wireError()
+}
+
+// DecodeWire sets e from the serialized form written with WireValue. decode is
+// called with a pointer to the struct to decode into
+func (e *Error) DecodeWire(decode func(v interface{}) error) error {
+	je := jsonError{}
+	if err := decode(&je); err != nil {
+		return err
+	}
+	e.fromJSONError(je)
+	return nil
+}
+
+// toJSONError creates the serialized form of an error. deprecated codes are
+// written as their replacement
+func (e Error) toJSONError() jsonError {
+	reg := e.Registry()
+	code := reg.Canonical(e.code)
+	return jsonError{
+		Code:        code,
+		Slug:        reg.CodeSlug(code),
+		Message:     e.Message(),
+		Friendly:    e.friendlyMessage(),
+		Fix:         e.Fix(),
+		FixCommands: e.fixCommands,
+		Field:       e.field,
+		Data:        e.data,
+		Details:     e.details,
+	}
+}
+
+// fromJSONError sets e's fields from a serialized error, using e's registry to
+// resolve codes
+func (e *Error) fromJSONError(je jsonError) {
+	code := je.Code
+	if code == CodeUnknown && je.Slug != "" {
+		code = e.Registry().CodeFromSlug(je.Slug)
+	}
+	e.code = code
+	e.cause = je.cause()
+	e.friendly = je.Friendly
+	e.fix = je.Fix
+	e.fixCommands = je.FixCommands
+	e.field = je.Field
+	e.data = je.Data
+	e.details = je.Details
+}
+
+// MarshalJSON implements the json.Marshaler interface, writing the wire
+// version, code, slug, machine message, friendly message, fix, fix commands,
+// data and details of an error
+func (e Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.wireError())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, reading the output
+// of MarshalJSON from any wire version. When the code is missing the slug is
+// used to find one
+func (e *Error) UnmarshalJSON(data []byte) error {
+	je := jsonError{}
+	if err := json.Unmarshal(data, &je); err != nil {
+		return err
+	}
+	e.fromJSONError(je)
+	return nil
+}
+
+// MarshalJSONChain marshals err to JSON, nesting up to depth levels of wrapped
+// errors in "cause" fields. Errors that join many errors nest them in a
+// "causes" list instead. Errors that aren't an *Error are written with only a
+// message. Debug tooling can use MarshalJSONChain to inspect a full chain,
+// which UnmarshalJSON will read back
+func MarshalJSONChain(err error, depth int) ([]byte, error) {
+	if err == nil {
+		return []byte("null"), nil
+	}
+	je := chainJSONError(err, depth, false)
+	je.Version = WireVersion
+	return json.Marshal(je)
+}
+
+// maxDebugDepth caps the levels of causes written by MarshalJSONDebug
+const maxDebugDepth = 32
+
+// MarshalJSONDebug marshals err to JSON with it's full cause chain, and the
+// stack trace of every Error in the chain. Debug output reveals the internals
+// of a service, and should only be shown to trusted audiences
+func MarshalJSONDebug(err error) ([]byte, error) {
+	if err == nil {
+		return []byte("null"), nil
+	}
+	je := chainJSONError(err, maxDebugDepth, true)
+	je.Version = WireVersion
+	return json.Marshal(je)
+}
+
+// chainJSONError serializes err and up to depth levels of it's causes,
+// optionally with the stack trace of each Error
+func chainJSONError(err error, depth int, stacks bool) jsonError {
+	je := jsonError{Message: err.Error()}
+	if e, ok := err.(*Error); ok {
+		je = e.toJSONError()
+		if stacks {
+			je.Stack = e.StackTrace()
+		}
+	}
+	if depth <= 0 {
+		return je
+	}
+
+	children := chainChildren(err)
+	if _, ok := chainCause(err).(joinError); ok {
+		for _, child := range children {
+			je.Causes = append(je.Causes, chainJSONError(child, depth-1, stacks))
+		}
+	} else if len(children) == 1 {
+		cause := chainJSONError(children[0], depth-1, stacks)
+		je.Cause = &cause
+	}
+	return je
+}
+
+// chainCause returns the error err directly wraps, skipping the message
+// annotations Errors add to their causes
+func chainCause(err error) error {
+	if e, ok := err.(*Error); ok {
+		switch c := e.cause.(type) {
+		case *withMessage:
+			return c.cause
+		case *decodedCause:
+			return c.cause
+		default:
+			return nil
+		}
+	}
+	switch x := err.(type) {
+	case interface{ Unwrap() error }:
+		return x.Unwrap()
+	default:
+		return causeOf(err)
+	}
+}
+
+// chainChildren lists the errors err directly wraps for serialization
+func chainChildren(err error) []error {
+	if x, ok := err.(interface{ Unwrap() []error }); ok {
+		if _, isErr := err.(*Error); !isErr {
+			return x.Unwrap()
+		}
+	}
+	cause := chainCause(err)
+	if cause == nil {
+		return nil
+	}
+	if j, ok := cause.(joinError); ok {
+		return j.errs
+	}
+	return []error{cause}
+}
+
+// cause creates the error a serialized error describes. The message is kept
+// intact, while any serialized causes are decoded and made available to Is,
+// As, and Cause
+func (je jsonError) cause() error {
+	var cause error
+	if je.Cause != nil {
+		cause = je.Cause.decode()
+	} else if len(je.Causes) > 0 {
+		j := joinError{}
+		for _, c := range je.Causes {
+			j.errs = append(j.errs, c.decode())
+		}
+		cause = j
+	}
+	if cause == nil {
+		return goerrors.New(je.Message)
+	}
+	return &decodedCause{msg: je.Message, cause: cause}
+}
+
+// decode creates an error from a serialized cause. Causes with a code or slug
+// become an *Error
+func (je jsonError) decode() error {
+	if je.Code == CodeUnknown && je.Slug == "" {
+		return je.cause()
+	}
+	e := &Error{}
+	e.fromJSONError(je)
+	return e
+}
+
+// decodedCause is a deserialized error message with a deserialized cause
+type decodedCause struct {
+	msg   string
+	cause error
+}
+
+// Error returns the message exactly as it was serialized
+func (d *decodedCause) Error() string {
+	return d.msg
+}
+
+// Cause returns the decoded cause
+func (d *decodedCause) Cause() error {
+	return d.cause
+}
+
+// Unwrap returns the decoded cause
+func (d *decodedCause) Unwrap() error {
+	return d.cause
+}
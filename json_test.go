@@ -0,0 +1,172 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestErrorJSON(t *testing.T) {
+	e := NewFriendlyFix(CodeNotFound, "dataset not found", "couldn't find a dataset named", "check the spelling", "b5/world_bank").
+		WithDetail("peer", "QmPeer")
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `{"version":1,"code":6,"slug":"not_found","message":"dataset not found","friendly":"couldn't find a dataset named","fix":"check the spelling","data":["b5/world_bank"],"details":{"peer":"QmPeer"}}`
+	if string(data) != expect {
+		t.Errorf("json mismatch.\nexpected: %s\ngot:      %s", expect, string(data))
+	}
+
+	got := &Error{}
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Code() != CodeNotFound {
+		t.Errorf("code mismatch. expected: %d, got: %d", CodeNotFound, got.Code())
+	}
+	if got.Error() != e.Error() {
+		t.Errorf("error mismatch. expected: %s, got: %s", e.Error(), got.Error())
+	}
+	if got.Friendly() != e.Friendly() {
+		t.Errorf("friendly mismatch. expected: %s, got: %s", e.Friendly(), got.Friendly())
+	}
+	if got.Details()["peer"] != "QmPeer" {
+		t.Errorf("details mismatch. expected: %s, got: %v", "QmPeer", got.Details()["peer"])
+	}
+}
+
+func TestErrorJSONFixCommands(t *testing.T) {
+	e := New(CodeUnavailable, "no connection").WithFixCommands("qri setup", "qri connect")
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"fix_commands":["qri setup","qri connect"]`) {
+		t.Errorf("expected fix commands to be serialized. got: %s", string(data))
+	}
+
+	got := &Error{}
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	if cmds := got.FixCommands(); len(cmds) != 2 || cmds[1] != "qri connect" {
+		t.Errorf("fix commands mismatch. expected: %v, got: %v", e.FixCommands(), cmds)
+	}
+}
+
+func TestUnmarshalJSONSlug(t *testing.T) {
+	got := &Error{}
+	if err := json.Unmarshal([]byte(`{"slug":"unauthorized","message":"no token"}`), got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Code() != CodeUnauthorized {
+		t.Errorf("code mismatch. expected: %d, got: %d", CodeUnauthorized, got.Code())
+	}
+	if got.Fix() != "please log in and try again" {
+		t.Errorf("expected default fix for unmarshaled code. got: %s", got.Fix())
+	}
+}
+
+func TestMarshalJSONChain(t *testing.T) {
+	base := New(CodeNotFound, "no dataset")
+	e := Wrap(CodeUnavailable, fmt.Errorf("fetching: %w", base), "loading")
+
+	data, err := MarshalJSONChain(e, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `{"version":1,"code":7,"slug":"unavailable","message":"loading: fetching: missing: no dataset"` +
+		`,"friendly":"this service is currently unavailable","fix":"please try again later"` +
+		`,"cause":{"code":0,"message":"fetching: missing: no dataset"` +
+		`,"cause":{"code":6,"slug":"not_found","message":"no dataset"}}}`
+	if string(data) != expect {
+		t.Errorf("json mismatch.\nexpected: %s\ngot:      %s", expect, string(data))
+	}
+
+	got := &Error{}
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Error() != e.Error() {
+		t.Errorf("error mismatch. expected: %s, got: %s", e.Error(), got.Error())
+	}
+	if !IsCode(got, CodeNotFound) {
+		t.Errorf("expected decoded chain to contain CodeNotFound")
+	}
+
+	data, err = MarshalJSONChain(e, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(string(data), `"cause"`) != 1 {
+		t.Errorf("expected depth limit to cap nesting. got: %s", string(data))
+	}
+
+	joined := WrapAll(CodeGeneric, []error{io.EOF, New(CodeTimeout, "slow")}, "many")
+	data, err = MarshalJSONChain(joined, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect = `"causes":[{"code":0,"message":"EOF"},{"code":10,"slug":"timeout","message":"slow","friendly":"this is taking too long","fix":"please try again"}]`
+	if !strings.Contains(string(data), expect) {
+		t.Errorf("expected joined causes.\nexpected to contain: %s\ngot: %s", expect, string(data))
+	}
+	got = &Error{}
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	if !IsCode(got, CodeTimeout) {
+		t.Errorf("expected decoded joined chain to contain CodeTimeout")
+	}
+}
+
+func TestUnmarshalJSONVersions(t *testing.T) {
+	layouts := []string{
+		// unversioned layout written before the version field existed
+		`{"code":6,"slug":"not_found","message":"no dataset","friendly":"couldn't find that","data":["b5/world_bank"],"details":{"peer":"QmPeer"}}`,
+		`{"version":1,"code":6,"slug":"not_found","message":"no dataset","friendly":"couldn't find that"}`,
+		// a newer layout with fields this version doesn't know
+		`{"version":9,"code":6,"slug":"not_found","message":"no dataset","friendly":"couldn't find that","severity":"info","op":"dsfs.Load"}`,
+	}
+
+	for i, layout := range layouts {
+		got := &Error{}
+		if err := json.Unmarshal([]byte(layout), got); err != nil {
+			t.Errorf("case %d unexpected error: %s", i, err)
+			continue
+		}
+		if got.Code() != CodeNotFound || got.Message() != "no dataset" || got.FriendlyMessage() != "couldn't find that" {
+			t.Errorf("case %d decode mismatch. got: %s", i, got.Friendly())
+		}
+	}
+
+	got := &Error{}
+	if err := json.Unmarshal([]byte(layouts[0]), got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Data()) != 1 || got.Data()[0] != "b5/world_bank" || got.Details()["peer"] != "QmPeer" {
+		t.Errorf("unversioned data & details mismatch. got: %v %v", got.Data(), got.Details())
+	}
+}
+
+func TestMarshalJSONDebug(t *testing.T) {
+	e := Wrap(CodeNotFound, New(CodeUnavailable, "connection refused"), "loading dataset")
+	data, err := MarshalJSONDebug(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &Error{}
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	if !IsCode(got, CodeUnavailable) {
+		t.Errorf("expected debug output to keep the cause chain. got: %s", data)
+	}
+	if !strings.Contains(string(data), `"stack":[{"function":"github.com/qri-io/errors.TestMarshalJSONDebug"`) {
+		t.Errorf("expected debug output to include stacks. got: %s", data)
+	}
+}
@@ -0,0 +1,88 @@
+package errors
+
+import (
+	"strconv"
+	"strings"
+)
+
+// JSONAPIContentType is the media type of JSON:API documents
+const JSONAPIContentType = "application/vnd.api+json"
+
+// JSONAPIError is a JSON:API error object
+type JSONAPIError struct {
+	ID     string                 `json:"id,omitempty"`
+	Status string                 `json:"status,omitempty"`
+	Code   string                 `json:"code,omitempty"`
+	Title  string                 `json:"title,omitempty"`
+	Detail string                 `json:"detail,omitempty"`
+	Source *JSONAPISource         `json:"source,omitempty"`
+	Meta   map[string]interface{} `json:"meta,omitempty"`
+}
+
+// JSONAPISource points to the part of a request that caused an error
+type JSONAPISource struct {
+	Pointer   string `json:"pointer,omitempty"`
+	Parameter string `json:"parameter,omitempty"`
+}
+
+// ToJSONAPIErrors converts an error to a list of JSON:API error objects, for
+// use as the "errors" member of a JSON:API document. When err joins a batch of
+// errors, like field validation errors grouped with WrapAll, each *Error in
+// the batch becomes it's own error object. Field errors point to the
+// offending attribute with a source pointer
+func ToJSONAPIErrors(err error) []JSONAPIError {
+	if err == nil {
+		return nil
+	}
+
+	var objs []JSONAPIError
+	walk(err, func(err error) bool {
+		j, ok := err.(joinError)
+		if !ok {
+			return false
+		}
+		for _, member := range j.errs {
+			if e, ok := AsError(member); ok {
+				objs = append(objs, jsonAPIError(e))
+			}
+		}
+		return true
+	})
+	if len(objs) > 0 {
+		return objs
+	}
+
+	e, ok := AsError(err)
+	if !ok {
+		return []JSONAPIError{{Status: "500", Code: CodeSlug(CodeUnknown), Title: "error"}}
+	}
+	return []JSONAPIError{jsonAPIError(e)}
+}
+
+// jsonAPIError converts a single Error to a JSON:API error object
+func jsonAPIError(e *Error) JSONAPIError {
+	reg := e.Registry()
+	code := reg.Canonical(e.code)
+	obj := JSONAPIError{
+		Status: strconv.Itoa(reg.CodeHTTPStatus(code)),
+		Code:   reg.CodeSlug(code),
+		Title:  reg.CodeString(code),
+		Detail: e.friendlyMessage(),
+	}
+	if e.field != "" {
+		obj.Source = &JSONAPISource{Pointer: jsonAPIPointer(e.field)}
+	}
+	if fix := e.Fix(); fix != "" {
+		obj.Meta = map[string]interface{}{"fix": fix}
+	}
+	return obj
+}
+
+// jsonAPIPointer converts a field name to a JSON pointer into a resource's
+// attributes. fields that are already JSON pointers are left as-is
+func jsonAPIPointer(field string) string {
+	if strings.HasPrefix(field, "/") {
+		return field
+	}
+	return "/data/attributes/" + strings.Replace(field, ".", "/", -1)
+}
@@ -0,0 +1,41 @@
+package errors
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+)
+
+func TestToJSONAPIErrors(t *testing.T) {
+	batch := WrapAll(CodeInvalidArgs, []error{
+		NewFriendly(CodeInvalidArgs, "title required", "title is required").WithField("meta.title"),
+		NewFriendly(CodeInvalidSyntax, "bad json", "body isn't valid json").WithField("/data/attributes/body"),
+		io.EOF,
+	}, "invalid dataset")
+
+	data, err := json.Marshal(ToJSONAPIErrors(batch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `[{"status":"400","code":"invalid_args","title":"arguments","detail":"title is required","source":{"pointer":"/data/attributes/meta/title"}},` +
+		`{"status":"400","code":"invalid_syntax","title":"syntax","detail":"body isn't valid json","source":{"pointer":"/data/attributes/body"}}]`
+	if string(data) != expect {
+		t.Errorf("json:api mismatch.\nexpected: %s\ngot:      %s", expect, string(data))
+	}
+
+	objs := ToJSONAPIErrors(New(CodeUnauthorized, "no token"))
+	if len(objs) != 1 {
+		t.Fatalf("length mismatch. expected: %d, got: %d", 1, len(objs))
+	}
+	if objs[0].Status != "401" || objs[0].Meta["fix"] != "please log in and try again" {
+		t.Errorf("object mismatch. got: %#v", objs[0])
+	}
+
+	objs = ToJSONAPIErrors(io.EOF)
+	if len(objs) != 1 || objs[0].Status != "500" {
+		t.Errorf("expected a single generic error for plain errors. got: %#v", objs)
+	}
+	if ToJSONAPIErrors(nil) != nil {
+		t.Errorf("expected nil error to produce no objects")
+	}
+}
@@ -0,0 +1,36 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+)
+
+// RenderJSONLine writes err to w as a single line of JSON, for tooling that
+// wraps a command line program. The object is the body the HTTP writers write
+// in DefaultOutputMode, so scripts can share parsers between a service and
+// it's command line client. Errors that aren't an *Error are written with
+// CodeUnknown. A nil error writes nothing
+func RenderJSONLine(w io.Writer, err error) error {
+	if err == nil {
+		return nil
+	}
+	mode := OutputDefault.resolve()
+	e := resolvedError(err).forOutput(mode)
+	data := marshalJSONBody(err, e, mode)
+	// a registered JSON serializer may indent it's output
+	if bytes.ContainsAny(data, "\n\r") {
+		buf := &bytes.Buffer{}
+		if cerr := json.Compact(buf, data); cerr == nil {
+			data = buf.Bytes()
+		}
+	}
+	_, werr := w.Write(append(data, '\n'))
+	return werr
+}
+
+// PrintJSONLine writes err to stderr with RenderJSONLine
+func PrintJSONLine(err error) error {
+	return RenderJSONLine(os.Stderr, err)
+}
@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRenderJSONLine(t *testing.T) {
+	e := NewFriendlyFix(CodeNotFound, "dataset not found", "couldn't find that dataset", "check the spelling", "b5/world_bank").
+		WithDetail("peer", "QmPeer")
+
+	buf := &bytes.Buffer{}
+	if err := RenderJSONLine(buf, e); err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	WriteHTTP(rec, e)
+	expect := rec.Body.String() + "\n"
+	if buf.String() != expect {
+		t.Errorf("expected the HTTP body.\nexpected: %s\ngot:      %s", expect, buf.String())
+	}
+	if bytes.Count(buf.Bytes(), []byte("\n")) != 1 {
+		t.Errorf("expected a single line. got: %q", buf.String())
+	}
+
+	buf.Reset()
+	RenderJSONLine(buf, fmt.Errorf("oh no"))
+	if expect := `{"version":1,"code":0,"slug":"unknown","message":"oh no"}` + "\n"; buf.String() != expect {
+		t.Errorf("output mismatch.\nexpected: %s\ngot:      %s", expect, buf.String())
+	}
+
+	buf.Reset()
+	RenderJSONLine(buf, nil)
+	if buf.Len() != 0 {
+		t.Errorf("expected nil errors to write nothing. got: %q", buf.String())
+	}
+}
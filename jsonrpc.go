@@ -0,0 +1,187 @@
+package errors
+
+import (
+	"encoding/json"
+	goerrors "errors"
+)
+
+// JSON-RPC 2.0 predefined error codes
+const (
+	JSONRPCParseError     = -32700
+	JSONRPCInvalidRequest = -32600
+	JSONRPCMethodNotFound = -32601
+	JSONRPCInvalidParams  = -32602
+	JSONRPCInternalError  = -32603
+)
+
+// JSONRPCError is a JSON-RPC 2.0 error object
+type JSONRPCError struct {
+	// Code is the JSON-RPC error code
+	Code int `json:"code"`
+	// Message is a short description of the error
+	Message string `json:"message"`
+	// Data holds additional information about the error
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// Error implements the error interface
+func (je JSONRPCError) Error() string {
+	return je.Message
+}
+
+// jsonRPCData is the data member written for an *Error
+type jsonRPCData struct {
+	Slug     string                 `json:"slug,omitempty"`
+	Friendly string                 `json:"friendly,omitempty"`
+	Fix      string                 `json:"fix,omitempty"`
+	Field    string                 `json:"field,omitempty"`
+	Data     []interface{}          `json:"data,omitempty"`
+	Details  map[string]interface{} `json:"details,omitempty"`
+}
+
+// JSONRPCScheme maps codes to JSON-RPC error codes. Codes listed in Standard
+// use a predefined JSON-RPC code. Other built-in codes are placed in the
+// implementation-defined server error range, counting down from ServerOffset,
+// and application codes are shifted by ApplicationOffset
+type JSONRPCScheme struct {
+	// Standard maps codes to predefined JSON-RPC codes
+	Standard map[Code]int
+	// ServerOffset is the JSON-RPC code for CodeUnknown. built-in code c
+	// maps to ServerOffset - c
+	ServerOffset int
+	// ApplicationOffset is added to application codes
+	ApplicationOffset int
+}
+
+// DefaultJSONRPCScheme places built-in codes in the -32000 to -32099 server
+// error range, and leaves application codes as-is
+var DefaultJSONRPCScheme = JSONRPCScheme{
+	Standard: map[Code]int{
+		CodeInvalidSyntax:  JSONRPCParseError,
+		CodeInvalidArgs:    JSONRPCInvalidParams,
+		CodeNotImplemented: JSONRPCMethodNotFound,
+		CodeInternal:       JSONRPCInternalError,
+	},
+	ServerOffset: -32000,
+}
+
+// Code returns the JSON-RPC error code for a code
+func (s JSONRPCScheme) Code(c Code) int {
+	if n, ok := s.Standard[c]; ok {
+		return n
+	}
+	if c <= MaxReservedCode {
+		return s.ServerOffset - int(c)
+	}
+	return int(c) + s.ApplicationOffset
+}
+
+// FromCode returns the code for a JSON-RPC error code. The invalid request
+// code maps to CodeInvalidArgs, and codes outside the scheme map to
+// CodeGeneric
+func (s JSONRPCScheme) FromCode(n int) Code {
+	for c, sn := range s.Standard {
+		if sn == n {
+			return c
+		}
+	}
+	if n == JSONRPCInvalidRequest {
+		return CodeInvalidArgs
+	}
+	if n <= s.ServerOffset && n >= s.ServerOffset-int(MaxReservedCode) {
+		if c := Code(s.ServerOffset - n); c != CodeUnknown {
+			return c
+		}
+		return CodeGeneric
+	}
+	if c := Code(n - s.ApplicationOffset); c > MaxReservedCode {
+		return c
+	}
+	return CodeGeneric
+}
+
+// ToJSONRPC creates a JSON-RPC error object from an error with the default
+// scheme
+func ToJSONRPC(err error) *JSONRPCError {
+	return DefaultJSONRPCScheme.ToJSONRPC(err)
+}
+
+// FromJSONRPC creates an Error from a JSON-RPC error object with the default
+// scheme
+func FromJSONRPC(je *JSONRPCError) *Error {
+	return DefaultJSONRPCScheme.FromJSONRPC(je)
+}
+
+// ToJSONRPC creates a JSON-RPC error object from an error. The code chosen by
+// ResolveCode sets the JSON-RPC code, and the message is the machine message.
+// The slug, friendly message, fix, field, data & details of the error are
+// written to the data member. When DefaultOutputMode is OutputExternal the
+// error is redacted: the message is the friendly message, and only the slug,
+// friendly message & fix are written to the data member. Errors that aren't
+// an *Error produce a generic internal error. nil errors convert to nil
+func (s JSONRPCScheme) ToJSONRPC(err error) *JSONRPCError {
+	if err == nil {
+		return nil
+	}
+	e, ok := AsError(err)
+	if !ok {
+		return &JSONRPCError{Code: s.Code(CodeInternal), Message: "internal error"}
+	}
+
+	reg := e.Registry()
+	code := reg.Canonical(ResolveCode(err))
+	message := e.Message()
+	if DefaultOutputMode.resolve() == OutputExternal {
+		e = e.Redact()
+		message = e.friendlyMessage()
+	}
+	je := &JSONRPCError{Code: s.Code(code), Message: message}
+	data, merr := json.Marshal(jsonRPCData{
+		Slug:     reg.CodeSlug(code),
+		Friendly: e.friendlyMessage(),
+		Fix:      e.Fix(),
+		Field:    e.field,
+		Data:     e.data,
+		Details:  e.details,
+	})
+	if merr == nil {
+		je.Data = data
+	}
+	return je
+}
+
+// FromJSONRPC creates an Error from a JSON-RPC error object. A slug in the
+// data member takes precedence over the JSON-RPC code. data members that
+// weren't written by ToJSONRPC are kept as the "data" detail. nil objects
+// convert to nil
+func (s JSONRPCScheme) FromJSONRPC(je *JSONRPCError) *Error {
+	if je == nil {
+		return nil
+	}
+	e := &Error{code: s.FromCode(je.Code), cause: goerrors.New(je.Message)}
+	if len(je.Data) == 0 {
+		return e
+	}
+
+	d := jsonRPCData{}
+	if err := json.Unmarshal(je.Data, &d); err != nil {
+		var val interface{}
+		if json.Unmarshal(je.Data, &val) == nil {
+			e.WithDetail("data", val)
+		}
+		return e
+	}
+	if d.Slug != "" {
+		if c := CodeFromSlug(d.Slug); c != CodeUnknown {
+			e.code = c
+		}
+	}
+	e.friendly = d.Friendly
+	e.fix = d.Fix
+	e.field = d.Field
+	e.data = d.Data
+	for key, val := range d.Details {
+		e.WithDetail(key, val)
+	}
+	return e
+}
@@ -0,0 +1,114 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestJSONRPCScheme(t *testing.T) {
+	cases := []struct {
+		code Code
+		n    int
+	}{
+		{CodeInvalidSyntax, JSONRPCParseError},
+		{CodeInvalidArgs, JSONRPCInvalidParams},
+		{CodeNotImplemented, JSONRPCMethodNotFound},
+		{CodeInternal, JSONRPCInternalError},
+		{CodeNotFound, -32006},
+		{CodeGeneric, -32001},
+		{Code(120), 120},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%d", c.code), func(t *testing.T) {
+			if got := DefaultJSONRPCScheme.Code(c.code); got != c.n {
+				t.Errorf("jsonrpc code mismatch. expected: %d, got: %d", c.n, got)
+			}
+			if got := DefaultJSONRPCScheme.FromCode(c.n); got != c.code {
+				t.Errorf("code mismatch. expected: %d, got: %d", c.code, got)
+			}
+		})
+	}
+
+	if got := DefaultJSONRPCScheme.FromCode(JSONRPCInvalidRequest); got != CodeInvalidArgs {
+		t.Errorf("invalid request code mismatch. expected: %d, got: %d", CodeInvalidArgs, got)
+	}
+	if got := DefaultJSONRPCScheme.FromCode(-5); got != CodeGeneric {
+		t.Errorf("unmatched code mismatch. expected: %d, got: %d", CodeGeneric, got)
+	}
+
+	app := JSONRPCScheme{ServerOffset: -32000, ApplicationOffset: 1000}
+	if got := app.Code(Code(120)); got != 1120 {
+		t.Errorf("application offset mismatch. expected: %d, got: %d", 1120, got)
+	}
+	if got := app.FromCode(1120); got != Code(120) {
+		t.Errorf("application offset mismatch. expected: %d, got: %d", 120, got)
+	}
+}
+
+func TestJSONRPCRoundTrip(t *testing.T) {
+	e := NewFriendlyFix(CodeNotFound, "no dataset", "couldn't find that dataset", "check the name", "b5/world_bank").
+		WithField("ref").
+		WithDetail("peer", "QmFoo")
+
+	je := ToJSONRPC(e)
+	data, err := json.Marshal(je)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &JSONRPCError{}
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Code != -32006 || got.Message != "no dataset" {
+		t.Errorf("error object mismatch. got: %s", data)
+	}
+
+	back := FromJSONRPC(got)
+	if back.Code() != CodeNotFound {
+		t.Errorf("code mismatch. expected: %d, got: %d", CodeNotFound, back.Code())
+	}
+	if back.Message() != "no dataset" || back.FriendlyMessage() != "couldn't find that dataset" || back.Fix() != "check the name" {
+		t.Errorf("message mismatch. expected: %s, got: %s", e.Friendly(), back.Friendly())
+	}
+	if back.Field() != "ref" || back.Details()["peer"] != "QmFoo" || len(back.Data()) != 1 {
+		t.Errorf("fields mismatch. got: %q %v %v", back.Field(), back.Details(), back.Data())
+	}
+
+	foreign := FromJSONRPC(&JSONRPCError{Code: JSONRPCMethodNotFound, Message: "Method not found", Data: json.RawMessage(`"ping"`)})
+	if foreign.Code() != CodeNotImplemented || foreign.Details()["data"] != "ping" {
+		t.Errorf("foreign error mismatch. got: %d %v", foreign.Code(), foreign.Details())
+	}
+
+	if internal := ToJSONRPC(fmt.Errorf("oh no")); internal.Code != JSONRPCInternalError {
+		t.Errorf("internal error code mismatch. expected: %d, got: %d", JSONRPCInternalError, internal.Code)
+	}
+}
+
+func TestToJSONRPCExternal(t *testing.T) {
+	prev := DefaultOutputMode
+	DefaultOutputMode = OutputExternal
+	defer func() { DefaultOutputMode = prev }()
+
+	e := NewFriendlyFix(CodeInternal, "select * from secrets failed", "something went wrong", "try again later", "users").
+		WithField("ref").
+		WithDetail("dsn", "postgres://admin:hunter2@db")
+
+	je := ToJSONRPC(e)
+	if je.Message != "something went wrong" {
+		t.Errorf("message mismatch. expected: %s, got: %s", "something went wrong", je.Message)
+	}
+	data, err := json.Marshal(je)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leak := range []string{"secrets", "hunter2", "dsn", "users", "ref"} {
+		if strings.Contains(string(data), leak) {
+			t.Errorf("expected external output to hide %q. got: %s", leak, data)
+		}
+	}
+	if !strings.Contains(string(data), `"fix":"try again later"`) {
+		t.Errorf("expected the fix to be kept. got: %s", data)
+	}
+}
@@ -0,0 +1,192 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+	textcatalog "golang.org/x/text/message/catalog"
+)
+
+// DefaultLanguage is the language Friendly renders messages in. Friendly
+// messages & fixes given to constructors and code specs are written in
+// DefaultLanguage, and used as is when no translation exists
+var DefaultLanguage = language.English
+
+// message fields of a code, used in message ids
+const (
+	FriendlyMessageField = "friendly"
+	FixMessageField      = "fix"
+)
+
+// defaultCatalog is the catalog SetTranslation adds to
+var defaultCatalog = textcatalog.NewBuilder()
+
+// localization holds the catalog friendly messages & fixes are translated
+// with
+var localization = struct {
+	sync.RWMutex
+	cat textcatalog.Catalog
+}{cat: defaultCatalog}
+
+// SetCatalog sets the golang.org/x/text message catalog friendly messages &
+// fixes are translated with, so existing translation pipelines can provide
+// translations. A nil catalog restores the default catalog SetTranslation
+// adds to. Messages are looked up by two keys, in order:
+//
+//   - the MessageID of the error's code, for default messages of the code
+//   - the untranslated string, as written in DefaultLanguage
+//
+// Messages are rendered as is, without fmt verbs. Placeholders of the form
+// {name} are replaced with the error's detail of the same name. Plural &
+// select messages can choose on data values, numbered from 1
+func SetCatalog(c textcatalog.Catalog) {
+	localization.Lock()
+	defer localization.Unlock()
+	if c == nil {
+		c = defaultCatalog
+	}
+	localization.cat = c
+}
+
+// SetTranslation adds a translation of a friendly message or fix to the
+// default catalog. key is a MessageID, or the string as written in
+// DefaultLanguage, including the default friendly messages & fixes of code
+// specs
+func SetTranslation(lang language.Tag, key, translation string) {
+	defaultCatalog.SetString(lang, key, translation)
+}
+
+// MessageID returns the catalog key of a default message of a code, eg:
+// "errors.not_found.friendly". field is FriendlyMessageField or
+// FixMessageField
+func MessageID(c Code, field string) string {
+	return DefaultRegistry.messageID(c, field)
+}
+
+// messageID returns the catalog key of a default message of a code
+func (r *Registry) messageID(c Code, field string) string {
+	return "errors." + r.CodeSlug(r.Canonical(c)) + "." + field
+}
+
+// messageRenderer implements the renderer golang.org/x/text catalogs
+// execute messages with, collecting rendered text
+type messageRenderer struct {
+	b    strings.Builder
+	data []interface{}
+}
+
+// Render appends a rendered string
+func (r *messageRenderer) Render(s string) {
+	r.b.WriteString(s)
+}
+
+// Arg returns the data value numbered i, counting from 1
+func (r *messageRenderer) Arg(i int) interface{} {
+	if i < 1 || i > len(r.data) {
+		return nil
+	}
+	return r.data[i-1]
+}
+
+// translate renders the message for id or source in lang, falling back to
+// source. lang falls back to it's parent languages, eg: "es-MX" falls back
+// to "es". An empty id skips the message id lookup
+func (e Error) translate(lang language.Tag, id, source string) string {
+	localization.RLock()
+	cat := localization.cat
+	localization.RUnlock()
+
+	str := source
+	for _, key := range []string{id, source} {
+		if key == "" {
+			continue
+		}
+		r := &messageRenderer{data: e.data}
+		if err := cat.Context(lang, r).Execute(key); err == nil {
+			str = r.b.String()
+			break
+		}
+	}
+	return e.expandDetails(str)
+}
+
+// expandDetails replaces {name} placeholders with the error's detail of the
+// same name. Placeholders without a detail are left as is
+func (e Error) expandDetails(str string) string {
+	if len(e.details) == 0 || !strings.Contains(str, "{") {
+		return str
+	}
+	pairs := make([]string, 0, len(e.details)*2)
+	for key, val := range e.details {
+		pairs = append(pairs, "{"+key+"}", fmt.Sprint(val))
+	}
+	return strings.NewReplacer(pairs...).Replace(str)
+}
+
+// NegotiateLanguage picks the language of the message catalog that best
+// matches an Accept-Language header, falling back to DefaultLanguage
+func NegotiateLanguage(acceptLanguage string) language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return DefaultLanguage
+	}
+	localization.RLock()
+	cat := localization.cat
+	localization.RUnlock()
+
+	supported := append([]language.Tag{DefaultLanguage}, cat.Languages()...)
+	_, i, conf := language.NewMatcher(supported).Match(tags...)
+	if conf == language.No {
+		return DefaultLanguage
+	}
+	return supported[i]
+}
+
+// localized returns a copy of e with the friendly message & fix rendered in
+// lang
+func (e Error) localized(lang language.Tag) *Error {
+	e.friendly, e.fix = e.FriendlyMessageIn(lang), e.FixIn(lang)
+	return &e
+}
+
+// FriendlyIn is like Friendly, rendering the friendly message & fix in lang
+func (e Error) FriendlyIn(lang language.Tag) string {
+	friendly, fix := e.FriendlyMessageIn(lang), e.FixIn(lang)
+	if friendly == "" && fix == "" {
+		return ""
+	}
+
+	str := fmt.Sprintf("%s: %s", e.Registry().CodeString(e.code), friendly)
+	for i, d := range e.data {
+		str += fmt.Sprintf(" %v", d)
+		if i < len(e.data)-1 {
+			str += ","
+		} else {
+			str += "."
+		}
+	}
+	if fix != "" {
+		str += fmt.Sprintf(" %s", fix)
+	}
+	return str
+}
+
+// FriendlyMessageIn is like FriendlyMessage, translated to lang
+func (e Error) FriendlyMessageIn(lang language.Tag) string {
+	id := ""
+	if e.friendly == "" {
+		id = e.Registry().messageID(e.code, FriendlyMessageField)
+	}
+	return e.translate(lang, id, e.friendlyMessage())
+}
+
+// FixIn is like Fix, translated to lang
+func (e Error) FixIn(lang language.Tag) string {
+	id := ""
+	if e.fix == "" && !e.noDefaultFix {
+		id = e.Registry().messageID(e.code, FixMessageField)
+	}
+	return e.translate(lang, id, e.Fix())
+}
@@ -0,0 +1,156 @@
+package errors
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+	textcatalog "golang.org/x/text/message/catalog"
+)
+
+func TestFriendlyIn(t *testing.T) {
+	SetTranslation(language.Spanish, "couldn't find that dataset", "no se pudo encontrar ese conjunto de datos")
+	SetTranslation(language.Spanish, "check the spelling", "revisa la ortografía")
+	SetTranslation(language.Spanish, "you need to be logged in to do that", "necesitas iniciar sesión para hacer eso")
+
+	e := NewFriendlyFix(CodeNotFound, "no dataset", "couldn't find that dataset", "check the spelling", "b5/world_bank")
+	cases := []struct {
+		lang   language.Tag
+		expect string
+	}{
+		{language.Spanish, "missing: no se pudo encontrar ese conjunto de datos b5/world_bank. revisa la ortografía"},
+		{language.MustParse("es-MX"), "missing: no se pudo encontrar ese conjunto de datos b5/world_bank. revisa la ortografía"},
+		{language.French, "missing: couldn't find that dataset b5/world_bank. check the spelling"},
+		{language.English, "missing: couldn't find that dataset b5/world_bank. check the spelling"},
+	}
+	for _, c := range cases {
+		if got := e.FriendlyIn(c.lang); got != c.expect {
+			t.Errorf("%s friendly mismatch.\nexpected: %s\ngot:      %s", c.lang, c.expect, got)
+		}
+	}
+	if e.Friendly() != e.FriendlyIn(DefaultLanguage) {
+		t.Errorf("expected Friendly to render in the default language. got: %s", e.Friendly())
+	}
+
+	// default messages of codes are translated too
+	auth := New(CodeUnauthorized, "no token")
+	if got := auth.FriendlyMessageIn(language.Spanish); got != "necesitas iniciar sesión para hacer eso" {
+		t.Errorf("default friendly mismatch. got: %s", got)
+	}
+	if got := auth.FixIn(language.Spanish); got != "please log in and try again" {
+		t.Errorf("expected untranslated fixes to be used as is. got: %s", got)
+	}
+}
+
+func TestSetCatalog(t *testing.T) {
+	cat := textcatalog.NewBuilder()
+	cat.SetString(language.German, MessageID(CodeNotFound, FriendlyMessageField), "{dataset} wurde nicht gefunden")
+	cat.SetString(language.German, MessageID(CodeNotFound, FixMessageField), "prüfe den Namen")
+	cat.SetString(language.German, "couldn't find that dataset", "Datensatz nicht gefunden")
+	SetCatalog(cat)
+	defer SetCatalog(nil)
+
+	if id := MessageID(CodeNotFound, FriendlyMessageField); id != "errors.not_found.friendly" {
+		t.Errorf("message id mismatch. expected: %s, got: %s", "errors.not_found.friendly", id)
+	}
+
+	e := New(CodeNotFound, "no dataset").WithDetail("dataset", "b5/world_bank")
+	if got := e.FriendlyMessageIn(language.German); got != "b5/world_bank wurde nicht gefunden" {
+		t.Errorf("friendly mismatch. got: %s", got)
+	}
+	if got := e.FixIn(language.German); got != "prüfe den Namen" {
+		t.Errorf("fix mismatch. got: %s", got)
+	}
+
+	// messages given to constructors are looked up by their text, not the
+	// code's message id
+	custom := NewFriendly(CodeNotFound, "no dataset", "couldn't find that dataset")
+	if got := custom.FriendlyMessageIn(language.German); got != "Datensatz nicht gefunden" {
+		t.Errorf("custom friendly mismatch. got: %s", got)
+	}
+	if got := custom.FriendlyMessageIn(language.Spanish); got != "couldn't find that dataset" {
+		t.Errorf("expected languages missing from the catalog to use the source. got: %s", got)
+	}
+}
+
+func TestNegotiateLanguage(t *testing.T) {
+	cat := textcatalog.NewBuilder()
+	cat.SetString(language.German, "couldn't find that dataset", "Datensatz nicht gefunden")
+	cat.SetString(language.MustParse("pt-BR"), "couldn't find that dataset", "conjunto de dados não encontrado")
+	SetCatalog(cat)
+	defer SetCatalog(nil)
+
+	cases := []struct {
+		accept string
+		expect language.Tag
+	}{
+		{"de-CH, en;q=0.5", language.German},
+		{"fr, pt-BR;q=0.8", language.MustParse("pt-BR")},
+		{"fr", DefaultLanguage},
+		{"", DefaultLanguage},
+		{"not a language", DefaultLanguage},
+	}
+	for _, c := range cases {
+		if got := NegotiateLanguage(c.accept); got != c.expect {
+			t.Errorf("%q language mismatch. expected: %s, got: %s", c.accept, c.expect, got)
+		}
+	}
+}
+
+func TestWriteHTTPRequestLanguage(t *testing.T) {
+	cat := textcatalog.NewBuilder()
+	cat.SetString(language.German, "couldn't find that dataset", "Datensatz nicht gefunden")
+	SetCatalog(cat)
+	defer SetCatalog(nil)
+
+	e := NewFriendly(CodeNotFound, "no dataset", "couldn't find that dataset")
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Language", "de-DE,de;q=0.9")
+	w := httptest.NewRecorder()
+	WriteHTTPRequest(w, r, e)
+
+	if got := w.Header().Get("Content-Language"); got != "de" {
+		t.Errorf("content language mismatch. expected: %s, got: %s", "de", got)
+	}
+	if !strings.Contains(w.Body.String(), `"friendly":"Datensatz nicht gefunden"`) {
+		t.Errorf("expected a translated friendly message. got: %s", w.Body.String())
+	}
+	if e.FriendlyMessage() != "couldn't find that dataset" {
+		t.Errorf("expected the written error to be left unchanged. got: %s", e.FriendlyMessage())
+	}
+
+	r.Header.Set("Accept-Language", "fr")
+	w = httptest.NewRecorder()
+	WriteHTTPRequest(w, r, e)
+	if got := w.Header().Get("Content-Language"); got != "en" {
+		t.Errorf("expected the default language. got: %s", got)
+	}
+	if !strings.Contains(w.Body.String(), `"friendly":"couldn't find that dataset"`) {
+		t.Errorf("expected the untranslated friendly message. got: %s", w.Body.String())
+	}
+}
+
+func TestWriteHTTPRequestLanguageExternal(t *testing.T) {
+	cat := textcatalog.NewBuilder()
+	cat.SetString(language.Spanish, MessageID(CodeUnauthorized, FriendlyMessageField), "necesitas iniciar sesión")
+	SetCatalog(cat)
+	defer SetCatalog(nil)
+
+	hw := &HTTPWriter{Mode: OutputExternal}
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Language", "es")
+	w := httptest.NewRecorder()
+	hw.WriteRequest(w, r, New(CodeUnauthorized, "token expired for user 42"))
+
+	if got := w.Header().Get("Content-Language"); got != "es" {
+		t.Errorf("content language mismatch. expected: %s, got: %s", "es", got)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, `"friendly":"necesitas iniciar sesión"`) {
+		t.Errorf("expected external output to translate the code's default message. got: %s", body)
+	}
+	if strings.Contains(body, "token expired for user 42") {
+		t.Errorf("expected external output to hide the machine message. got: %s", body)
+	}
+}
@@ -0,0 +1,41 @@
+package errors
+
+// LogFields flattens an error into fields for loggers with flat key-value
+// fields, like logrus. "error" holds the error message, and the code, slug,
+// friendly message, op & error id are held in "error_" prefixed fields.
+// Details are added with the same prefix. The op & error id come from
+// OriginOf, so errors received from other services report their origin.
+// Errors that aren't an *Error only set "error", and nil errors return nil
+func LogFields(err error) map[string]interface{} {
+	if err == nil {
+		return nil
+	}
+	fields := map[string]interface{}{"error": err.Error()}
+	e, ok := AsError(err)
+	if !ok {
+		return fields
+	}
+
+	for key, val := range e.details {
+		switch key {
+		case OpDetail, ErrorIDDetail:
+		default:
+			fields["error_"+key] = val
+		}
+	}
+	reg := e.Registry()
+	code := reg.Canonical(ResolveCode(err))
+	fields["error_code"] = int(code)
+	fields["error_slug"] = reg.CodeSlug(code)
+	if friendly := e.friendlyMessage(); friendly != "" {
+		fields["error_friendly"] = friendly
+	}
+	o := OriginOf(err)
+	if o.Op != "" {
+		fields["error_op"] = o.Op
+	}
+	if o.ID != "" {
+		fields["error_id"] = o.ID
+	}
+	return fields
+}
@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLogFields(t *testing.T) {
+	err := NewFriendly(CodeNotFound, "no dataset", "couldn't find that dataset").
+		WithDetail(OpDetail, "dsfs.LoadDataset").
+		WithDetail(ErrorIDDetail, "err-123").
+		WithDetail("ref", "b5/world_bank")
+	got := LogFields(fmt.Errorf("loading: %w", err))
+	expect := map[string]interface{}{
+		"error":          "loading: missing: no dataset",
+		"error_code":     int(CodeNotFound),
+		"error_slug":     "not_found",
+		"error_friendly": "couldn't find that dataset",
+		"error_op":       "dsfs.LoadDataset",
+		"error_id":       "err-123",
+		"error_ref":      "b5/world_bank",
+	}
+	if len(got) != len(expect) {
+		t.Errorf("field count mismatch. expected: %d, got: %d %v", len(expect), len(got), got)
+	}
+	for key, val := range expect {
+		if got[key] != val {
+			t.Errorf("field %q mismatch. expected: %v, got: %v", key, val, got[key])
+		}
+	}
+
+	plain := LogFields(fmt.Errorf("oh no"))
+	if len(plain) != 1 || plain["error"] != "oh no" {
+		t.Errorf("expected plain errors to only set error. got: %v", plain)
+	}
+	if LogFields(nil) != nil {
+		t.Errorf("expected nil fields for a nil error")
+	}
+}
@@ -0,0 +1,83 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToMarkdown formats an error as markdown for pasting into issue trackers or
+// posting from bots. The code heads the block, followed by sections for the
+// message, friendly message, fix, fix commands, and data. The stack trace is
+// folded into a collapsible details block. Messages, commands & stacks are
+// fenced, so their content is shown verbatim. nil errors return an empty
+// string
+func ToMarkdown(err error) string {
+	if err == nil {
+		return ""
+	}
+	b := &strings.Builder{}
+	e, ok := AsError(err)
+	if !ok {
+		fmt.Fprintf(b, "#### Error `%s`\n\n", DefaultRegistry.CodeSlug(contextCode(err)))
+		writeMarkdownFence(b, "text", err.Error())
+		return b.String()
+	}
+
+	reg := e.Registry()
+	code := reg.Canonical(ResolveCode(err))
+	fmt.Fprintf(b, "#### Error `%s` (%d)\n\n", reg.CodeSlug(code), code)
+	msg := err.Error()
+	if err == error(e) {
+		// the code heads the block, leave out the code prefix Error adds
+		msg = e.Message()
+	}
+	writeMarkdownFence(b, "text", msg)
+	if friendly := e.friendlyMessage(); friendly != "" {
+		fmt.Fprintf(b, "**Friendly:** %s\n\n", friendly)
+	}
+	if fix := e.Fix(); fix != "" {
+		fmt.Fprintf(b, "**Fix:** %s\n\n", fix)
+	}
+	if cmds := e.FixCommands(); len(cmds) > 0 {
+		b.WriteString("**Try:**\n\n")
+		writeMarkdownFence(b, "sh", strings.Join(cmds, "\n"))
+	}
+	if len(e.data) > 0 {
+		b.WriteString("**Data:**\n\n")
+		for _, d := range e.data {
+			fmt.Fprintf(b, "- `%v`\n", d)
+		}
+		b.WriteString("\n")
+	}
+	if frames := e.StackTrace(); len(frames) > 0 {
+		strs := make([]string, len(frames))
+		for i, f := range frames {
+			strs[i] = f.String()
+		}
+		b.WriteString("<details>\n<summary>Stack trace</summary>\n\n")
+		writeMarkdownFence(b, "text", strings.Join(strs, "\n"))
+		b.WriteString("</details>\n")
+	}
+	return b.String()
+}
+
+// writeMarkdownFence writes text in a fenced code block, using a fence longer
+// than any run of backticks in text so the block can't be closed early
+func writeMarkdownFence(b *strings.Builder, lang, text string) {
+	longest, run := 0, 0
+	for _, r := range text {
+		if r == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+	fence := "```"
+	if longest >= len(fence) {
+		fence = strings.Repeat("`", longest+1)
+	}
+	fmt.Fprintf(b, "%s%s\n%s\n%s\n\n", fence, lang, text, fence)
+}
@@ -0,0 +1,35 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestToMarkdown(t *testing.T) {
+	e := NewFriendlyFix(CodeNotFound, "dataset not found", "couldn't find that dataset", "check the spelling", "b5/world_bank").
+		WithFixCommands("qri list")
+	md := ToMarkdown(e)
+
+	expectPrefix := "#### Error `not_found` (6)\n\n" +
+		"```text\ndataset not found\n```\n\n" +
+		"**Friendly:** couldn't find that dataset\n\n" +
+		"**Fix:** check the spelling\n\n" +
+		"**Try:**\n\n```sh\nqri list\n```\n\n" +
+		"**Data:**\n\n- `b5/world_bank`\n\n" +
+		"<details>\n<summary>Stack trace</summary>\n\n```text\ngithub.com/qri-io/errors.TestToMarkdown\n"
+	if !strings.HasPrefix(md, expectPrefix) {
+		t.Errorf("markdown mismatch.\nexpected prefix:\n%s\ngot:\n%s", expectPrefix, md)
+	}
+	if !strings.HasSuffix(md, "```\n\n</details>\n") {
+		t.Errorf("expected the stack to be in a closed details block. got:\n%s", md)
+	}
+
+	expect := "#### Error `unknown`\n\n````text\nbad ``` fence\n````\n\n"
+	if got := ToMarkdown(fmt.Errorf("bad ``` fence")); got != expect {
+		t.Errorf("markdown mismatch.\nexpected:\n%s\ngot:\n%s", expect, got)
+	}
+	if ToMarkdown(nil) != "" {
+		t.Errorf("expected nil errors to render nothing")
+	}
+}
@@ -0,0 +1,18 @@
+package errors
+
+// MarshalMsgpack implements the msgpack.Marshaler interface with the
+// serializer registered for "application/msgpack", so errors can travel
+// inside MessagePack envelopes. Programs must import
+// github.com/qri-io/errors/errorsmsgpack to register one, without it
+// MarshalMsgpack fails with CodeNotImplemented:
+//
+//	import _ "github.com/qri-io/errors/errorsmsgpack"
+func (e Error) MarshalMsgpack() ([]byte, error) {
+	return marshalWith("application/msgpack", "github.com/qri-io/errors/errorsmsgpack", &e)
+}
+
+// UnmarshalMsgpack implements the msgpack.Unmarshaler interface, reading the
+// output of MarshalMsgpack
+func (e *Error) UnmarshalMsgpack(data []byte) error {
+	return unmarshalWith("application/msgpack", "github.com/qri-io/errors/errorsmsgpack", e, data)
+}
@@ -0,0 +1,128 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// OpenAPIComponents generates an OpenAPI 3 components object describing
+// errors in the default registry
+func OpenAPIComponents() map[string]interface{} {
+	return DefaultRegistry.OpenAPIComponents()
+}
+
+// OpenAPIComponents generates an OpenAPI 3 components object from the codes
+// in this registry and the JSON layout of an Error. The object holds an
+// "Error" schema, and a response named "Error<status>" for every http status
+// a code maps to, describing the codes that produce it. Marshal the result
+// to JSON or YAML and merge it into an API spec, so the spec stays in sync
+// with the errors a service can return. Known codes & slugs are listed in an
+// "x-enum" extension rather than a closed "enum", so generated clients accept
+// codes registered after the spec was generated
+func (r *Registry) OpenAPIComponents() map[string]interface{} {
+	codes := r.Codes()
+	codeEnum := make([]interface{}, 0, len(codes))
+	slugEnum := make([]interface{}, 0, len(codes))
+	byStatus := map[int][]Code{}
+	for _, c := range codes {
+		codeEnum = append(codeEnum, int(c))
+		slugEnum = append(slugEnum, r.CodeSlug(c))
+		status := r.CodeHTTPStatus(c)
+		byStatus[status] = append(byStatus[status], c)
+	}
+
+	str := func(description string) map[string]interface{} {
+		return map[string]interface{}{"type": "string", "description": description}
+	}
+	ref := map[string]interface{}{"$ref": "#/components/schemas/Error"}
+	schemas := map[string]interface{}{
+		"Error": map[string]interface{}{
+			"type":     "object",
+			"required": []interface{}{"code", "message"},
+			"properties": map[string]interface{}{
+				"version": map[string]interface{}{
+					"type":        "integer",
+					"description": "wire version of the error layout",
+					"example":     WireVersion,
+				},
+				"code": map[string]interface{}{
+					"type":        "integer",
+					"description": "numeric code classifying the error. x-enum lists the codes known when the spec was generated, services may return others",
+					"x-enum":      codeEnum,
+				},
+				"slug": map[string]interface{}{
+					"type":        "string",
+					"description": "stable string identifier of the code. x-enum lists the slugs known when the spec was generated, services may return others",
+					"x-enum":      slugEnum,
+				},
+				"message":  str("machine-readable message for developers"),
+				"friendly": str("user-facing message describing the problem"),
+				"fix":      str("message on how to fix the problem"),
+				"field":    str("name of the input field the error applies to"),
+				"fix_commands": map[string]interface{}{
+					"type":        "array",
+					"description": "shell commands that fix the problem",
+					"items":       map[string]interface{}{"type": "string"},
+				},
+				"data": map[string]interface{}{
+					"type":        "array",
+					"description": "values that caused the error",
+					"items":       map[string]interface{}{},
+				},
+				"details": map[string]interface{}{
+					"type":                 "object",
+					"description":          "structured details of the error",
+					"additionalProperties": true,
+				},
+				"cause": ref,
+				"causes": map[string]interface{}{
+					"type":  "array",
+					"items": ref,
+				},
+				"truncated": map[string]interface{}{
+					"type":        "boolean",
+					"description": "set when the error was cut down to fit a size budget",
+				},
+				"stack": map[string]interface{}{
+					"type":        "array",
+					"description": "stack trace, only written in debug output",
+					"items":       map[string]interface{}{"$ref": "#/components/schemas/ErrorFrame"},
+				},
+			},
+		},
+		"ErrorFrame": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"function": map[string]interface{}{"type": "string"},
+				"file":     map[string]interface{}{"type": "string"},
+				"line":     map[string]interface{}{"type": "integer"},
+			},
+		},
+	}
+
+	statuses := make([]int, 0, len(byStatus))
+	for status := range byStatus {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	responses := map[string]interface{}{}
+	for _, status := range statuses {
+		slugs := make([]string, 0, len(byStatus[status]))
+		for _, c := range byStatus[status] {
+			slugs = append(slugs, r.CodeSlug(c))
+		}
+		responses[fmt.Sprintf("Error%d", status)] = map[string]interface{}{
+			"description": fmt.Sprintf("%s. codes: %s", http.StatusText(status), strings.Join(slugs, ", ")),
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": ref},
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"schemas":   schemas,
+		"responses": responses,
+	}
+}
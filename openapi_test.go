@@ -0,0 +1,65 @@
+package errors
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestOpenAPIComponents(t *testing.T) {
+	r := NewRegistry()
+	if err := r.RegisterSpec(Code(100), CodeSpec{HTTPStatus: 402, Name: "quota", Slug: "quota_exceeded"}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(r.OpenAPIComponents())
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := struct {
+		Schemas struct {
+			Error struct {
+				Properties map[string]struct {
+					Enum  []interface{} `json:"enum"`
+					XEnum []interface{} `json:"x-enum"`
+				} `json:"properties"`
+			} `json:"Error"`
+		} `json:"schemas"`
+		Responses map[string]struct {
+			Description string `json:"description"`
+		} `json:"responses"`
+	}{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	for field := range wireFields {
+		if _, ok := doc.Schemas.Error.Properties[field]; !ok {
+			t.Errorf("expected schema to describe serialized field %q", field)
+		}
+	}
+	found := false
+	for _, slug := range doc.Schemas.Error.Properties["slug"].XEnum {
+		if slug == "quota_exceeded" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected registered slugs in the schema. got: %v", doc.Schemas.Error.Properties["slug"].XEnum)
+	}
+	for _, field := range []string{"code", "slug"} {
+		if enum := doc.Schemas.Error.Properties[field].Enum; enum != nil {
+			t.Errorf("expected %s not to be a closed enum, rejecting codes registered later. got: %v", field, enum)
+		}
+	}
+
+	resp, ok := doc.Responses["Error402"]
+	if !ok {
+		t.Fatalf("expected a response for status 402. got: %v", doc.Responses)
+	}
+	if !strings.Contains(resp.Description, "quota_exceeded") {
+		t.Errorf("expected response to list it's codes. got: %s", resp.Description)
+	}
+	if desc := doc.Responses["Error404"].Description; desc != "Not Found. codes: not_found" {
+		t.Errorf("description mismatch. expected: %s, got: %s", "Not Found. codes: not_found", desc)
+	}
+}
@@ -0,0 +1,132 @@
+package errors
+
+import "net/http"
+
+// Origin identifies where an error was first raised. Services pass origins
+// along a chain of internal hops, so the edge can report the service, code
+// and occurrence an error really came from
+type Origin struct {
+	// Code is the code the originating service resolved for the error
+	Code Code
+	// Op names the operation that failed, eg: "dsfs.LoadDataset"
+	Op string
+	// ID identifies the occurrence of the error in the originating service's
+	// logs
+	ID string
+}
+
+// detail keys OriginOf reads the op & id of errors raised in this process from
+const (
+	OpDetail      = "op"
+	ErrorIDDetail = "error_id"
+)
+
+// headers carrying origins in http responses
+const (
+	OriginCodeHeader = "X-Error-Origin-Code"
+	OriginOpHeader   = "X-Error-Origin-Op"
+	OriginIDHeader   = "X-Error-Origin-Id"
+)
+
+// WithOrigin sets where an error was first raised. Conversions from other
+// services' responses set origins, so there's rarely a need to call it
+// directly
+func (e *Error) WithOrigin(o Origin) *Error {
+	e.origin = &o
+	return e
+}
+
+// OriginOf returns where an error was first raised. The first origin set in
+// the chain is returned. Errors without one originate in this process, and
+// get an origin with the code ResolveCode picks, and the op & id from
+// OpDetail and ErrorIDDetail details
+func OriginOf(err error) Origin {
+	var found *Origin
+	walk(err, func(err error) bool {
+		if e, ok := err.(*Error); ok && e.origin != nil {
+			found = e.origin
+			return true
+		}
+		return false
+	})
+	if found != nil {
+		return *found
+	}
+
+	o := Origin{}
+	if e, ok := AsError(err); ok {
+		o.Code = e.Registry().Canonical(ResolveCode(err))
+	}
+	walk(err, func(err error) bool {
+		if e, ok := err.(*Error); ok {
+			if op, ok := e.details[OpDetail].(string); ok && o.Op == "" {
+				o.Op = op
+			}
+			if id, ok := e.details[ErrorIDDetail].(string); ok && o.ID == "" {
+				o.ID = id
+			}
+		}
+		return o.Op != "" && o.ID != ""
+	})
+	return o
+}
+
+// Ops returns the OpDetail of every Error in err's chain that has one,
+// outermost first
+func Ops(err error) []string {
+	var ops []string
+	walk(err, func(err error) bool {
+		if e, ok := err.(*Error); ok {
+			if op, ok := e.details[OpDetail].(string); ok {
+				ops = append(ops, op)
+			}
+		}
+		return false
+	})
+	return ops
+}
+
+// SetOriginHeaders writes the origin of an error to h. Use it for responses
+// to other internal services, never for responses leaving the system, as
+// ops can reveal implementation details
+func SetOriginHeaders(h http.Header, err error) {
+	setOriginHeaders(h, "", err)
+}
+
+// WriteOriginTrailers writes the origin of an error as http trailers, for
+// streaming responses that have already written headers. The response must
+// be sent with chunked encoding for trailers to arrive
+func WriteOriginTrailers(w http.ResponseWriter, err error) {
+	setOriginHeaders(w.Header(), http.TrailerPrefix, err)
+}
+
+// setOriginHeaders writes origin headers with a prefix on each name
+func setOriginHeaders(h http.Header, prefix string, err error) {
+	if err == nil {
+		return
+	}
+	o := OriginOf(err)
+	if o.Code != CodeUnknown {
+		h.Set(prefix+OriginCodeHeader, CodeSlug(o.Code))
+	}
+	if o.Op != "" {
+		h.Set(prefix+OriginOpHeader, o.Op)
+	}
+	if o.ID != "" {
+		h.Set(prefix+OriginIDHeader, o.ID)
+	}
+}
+
+// OriginFromHeader reads an origin written by SetOriginHeaders or
+// WriteOriginTrailers. Pass response trailers after reading the full body
+func OriginFromHeader(h http.Header) (Origin, bool) {
+	slug := h.Get(OriginCodeHeader)
+	if slug == "" {
+		return Origin{}, false
+	}
+	return Origin{
+		Code: CodeFromSlug(slug),
+		Op:   h.Get(OriginOpHeader),
+		ID:   h.Get(OriginIDHeader),
+	}, true
+}
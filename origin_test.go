@@ -0,0 +1,70 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOriginOf(t *testing.T) {
+	local := New(CodeNotFound, "no dataset").
+		WithDetail(OpDetail, "dsfs.LoadDataset").
+		WithDetail(ErrorIDDetail, "err-123")
+	got := OriginOf(fmt.Errorf("loading: %w", Wrap(CodeUnavailable, local, "registry lookup")))
+	expect := Origin{Code: CodeNotFound, Op: "dsfs.LoadDataset", ID: "err-123"}
+	if got != expect {
+		t.Errorf("local origin mismatch. expected: %v, got: %v", expect, got)
+	}
+
+	remote := New(CodeUnavailable, "registry unavailable").WithOrigin(Origin{Code: CodeTimeout, Op: "registry.Get", ID: "err-456"})
+	got = OriginOf(Wrap(CodeGeneric, remote, "searching"))
+	if got.Code != CodeTimeout || got.Op != "registry.Get" {
+		t.Errorf("remote origin mismatch. expected: registry.Get, got: %v", got)
+	}
+
+	if got := OriginOf(fmt.Errorf("oh no")); got != (Origin{}) {
+		t.Errorf("expected empty origin for a plain error. got: %v", got)
+	}
+}
+
+func TestOriginHeaders(t *testing.T) {
+	err := New(CodeTimeout, "peer took too long").WithOrigin(Origin{Code: CodeTimeout, Op: "p2p.Fetch", ID: "err-789"})
+	h := http.Header{}
+	SetOriginHeaders(h, err)
+	got, ok := OriginFromHeader(h)
+	if !ok {
+		t.Fatal("expected origin headers to be set")
+	}
+	if got != (Origin{Code: CodeTimeout, Op: "p2p.Fetch", ID: "err-789"}) {
+		t.Errorf("origin mismatch. got: %v", got)
+	}
+	if _, ok := OriginFromHeader(http.Header{}); ok {
+		t.Errorf("expected no origin from empty headers")
+	}
+}
+
+func TestOriginTrailers(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusBadGateway)
+		w.(http.Flusher).Flush()
+		io.WriteString(w, "upstream failed")
+		WriteOriginTrailers(w, New(CodeNotFound, "no dataset").WithDetail(OpDetail, "dsfs.LoadDataset"))
+	}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := FromHTTPResponse(resp)
+	got := OriginOf(e)
+	if got.Code != CodeNotFound || got.Op != "dsfs.LoadDataset" {
+		t.Errorf("origin mismatch. expected: not_found dsfs.LoadDataset, got: %v", got)
+	}
+	if e.Code() != CodeUnavailable {
+		t.Errorf("code mismatch. expected: %d, got: %d", CodeUnavailable, e.Code())
+	}
+}
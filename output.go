@@ -0,0 +1,89 @@
+package errors
+
+import (
+	goerrors "errors"
+)
+
+// OutputMode selects how much of an error writers show their audience
+type OutputMode int
+
+const (
+	// OutputDefault uses the mode set in DefaultOutputMode
+	OutputDefault OutputMode = iota
+	// OutputInternal writes everything serializers write, including machine
+	// messages, data, details and fields
+	OutputInternal
+	// OutputExternal writes only the code, friendly message, and fix, leaving
+	// out machine messages, stack traces & raw data values
+	OutputExternal
+	// OutputDebug writes everything, adding the full cause chain and stack
+	// traces to JSON output. Use it in development & staging environments
+	OutputDebug
+)
+
+// DefaultOutputMode is the mode writers like WriteHTTP use unless they're
+// configured with a mode of their own. Services that present errors to the
+// public should set it to OutputExternal. Serializers like MarshalJSON always
+// write complete errors, for passing errors between trusted services
+var DefaultOutputMode = OutputInternal
+
+// resolve replaces OutputDefault with DefaultOutputMode
+func (m OutputMode) resolve() OutputMode {
+	if m == OutputDefault {
+		return DefaultOutputMode
+	}
+	return m
+}
+
+// Redact returns a copy of e safe to show outside of the service, holding
+// only the code, friendly message, and fix of e. The machine message, data,
+// details, field, stack & causes are dropped. Headers are kept
+func (e Error) Redact() *Error {
+	return &Error{
+		code:         e.code,
+		friendly:     e.friendlyMessage(),
+		fix:          e.Fix(),
+		cause:        goerrors.New(""),
+		registry:     e.registry,
+		headers:      e.headers,
+		noDefaultFix: e.noDefaultFix,
+	}
+}
+
+// forOutput prepares an error for writing in mode
+func (e *Error) forOutput(mode OutputMode) *Error {
+	if mode.resolve() == OutputExternal {
+		return e.Redact()
+	}
+	return e
+}
+
+// Audience is who an error is written for
+type Audience int
+
+const (
+	// AudienceUnknown defers to a writer's configured output mode
+	AudienceUnknown Audience = iota
+	// AudienceUser is an end user, who sees only friendly messages & fixes
+	AudienceUser
+	// AudienceOperator runs the service, and also sees machine messages,
+	// data & details
+	AudienceOperator
+	// AudienceDeveloper builds the service, and also sees cause chains &
+	// stack traces
+	AudienceDeveloper
+)
+
+// OutputMode returns the output mode for an audience
+func (a Audience) OutputMode() OutputMode {
+	switch a {
+	case AudienceUser:
+		return OutputExternal
+	case AudienceOperator:
+		return OutputInternal
+	case AudienceDeveloper:
+		return OutputDebug
+	default:
+		return OutputDefault
+	}
+}
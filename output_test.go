@@ -0,0 +1,25 @@
+package errors
+
+import (
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	e := New(CodeUnavailable, "dial tcp 10.0.0.7:5432: connection refused", "postgres").
+		WithDetail("host", "10.0.0.7").
+		WithHeader("Retry-After", "5")
+
+	got := e.Redact()
+	if got.Code() != CodeUnavailable {
+		t.Errorf("code mismatch. expected: %d, got: %d", CodeUnavailable, got.Code())
+	}
+	if got.Message() != "" || got.Data() != nil || got.Details() != nil || got.StackTrace() != nil {
+		t.Errorf("expected redacted error to drop machine output. got: %q %v %v", got.Message(), got.Data(), got.Details())
+	}
+	if got.friendlyMessage() != "this service is currently unavailable" || got.Fix() != "please try again later" {
+		t.Errorf("expected default friendly message & fix. got: %q %q", got.friendlyMessage(), got.Fix())
+	}
+	if got.Headers().Get("Retry-After") != "5" {
+		t.Errorf("expected headers to be kept")
+	}
+}
@@ -0,0 +1,169 @@
+package errors
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"net/http"
+)
+
+// ProblemContentType is the media type of problem details documents
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 9457 problem details document. Members beyond the
+// standard five are held in Extensions, and written as top-level members
+type Problem struct {
+	// Type is a URI identifying the problem type
+	Type string
+	// Title is a short summary of the problem type
+	Title string
+	// Status is the http status code
+	Status int
+	// Detail is a human-readable explanation specific to this occurrence
+	Detail string
+	// Instance is a URI identifying this occurrence of the problem
+	Instance string
+	// Extensions are additional members of the problem
+	Extensions map[string]interface{}
+}
+
+// problemMembers lists the standard members of a problem details document
+var problemMembers = []string{"type", "title", "status", "detail", "instance"}
+
+// ProblemDetails creates a problem details document from an error. The code
+// chosen by ResolveCode sets the status, the title comes from the code name,
+// and the type is the code's docs url, defaulting to "about:blank". The detail
+// is the friendly message. The code, slug, fix, data and details of the error
+// are included as extensions. Errors that aren't an *Error produce a generic
+// 500 problem
+func ProblemDetails(err error) *Problem {
+	e, ok := AsError(err)
+	if !ok {
+		return &Problem{
+			Type:   "about:blank",
+			Title:  http.StatusText(http.StatusInternalServerError),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	reg := e.Registry()
+	code := reg.Canonical(ResolveCode(err))
+	p := &Problem{
+		Type:   reg.CodeDocsURL(code),
+		Title:  reg.CodeString(code),
+		Status: reg.CodeHTTPStatus(code),
+		Detail: e.friendlyMessage(),
+		Extensions: map[string]interface{}{
+			"code": code,
+			"slug": reg.CodeSlug(code),
+		},
+	}
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	if fix := e.Fix(); fix != "" {
+		p.Extensions["fix"] = fix
+	}
+	if len(e.data) > 0 {
+		p.Extensions["data"] = e.data
+	}
+	for key, val := range e.details {
+		if _, ok := p.Extensions[key]; !ok {
+			p.Extensions[key] = val
+		}
+	}
+	return p
+}
+
+// MarshalJSON implements the json.Marshaler interface, writing extensions as
+// top-level members. Extensions never overwrite standard members
+func (p Problem) MarshalJSON() ([]byte, error) {
+	doc := map[string]interface{}{}
+	for key, val := range p.Extensions {
+		doc[key] = val
+	}
+	for _, key := range problemMembers {
+		delete(doc, key)
+	}
+	if p.Type != "" {
+		doc["type"] = p.Type
+	}
+	if p.Title != "" {
+		doc["title"] = p.Title
+	}
+	if p.Status != 0 {
+		doc["status"] = p.Status
+	}
+	if p.Detail != "" {
+		doc["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		doc["instance"] = p.Instance
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, collecting
+// non-standard members into Extensions
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	std := struct {
+		Type     string `json:"type"`
+		Title    string `json:"title"`
+		Status   int    `json:"status"`
+		Detail   string `json:"detail"`
+		Instance string `json:"instance"`
+	}{}
+	if err := json.Unmarshal(data, &std); err != nil {
+		return err
+	}
+	doc := map[string]interface{}{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	for _, key := range problemMembers {
+		delete(doc, key)
+	}
+
+	*p = Problem{
+		Type:     std.Type,
+		Title:    std.Title,
+		Status:   std.Status,
+		Detail:   std.Detail,
+		Instance: std.Instance,
+	}
+	if len(doc) > 0 {
+		p.Extensions = doc
+	}
+	return nil
+}
+
+// FromProblem creates an Error from a problem details document. The code is
+// read from the "slug" or "code" extensions, falling back to the code for the
+// status. The title becomes the message and the detail the friendly message.
+// The fix & data extensions are restored, and any other extensions become
+// details
+func FromProblem(p *Problem) *Error {
+	e := &Error{cause: goerrors.New(p.Title), friendly: p.Detail}
+	if slug, ok := p.Extensions["slug"].(string); ok {
+		e.code = CodeFromSlug(slug)
+	}
+	if code, ok := p.Extensions["code"].(float64); ok && e.code == CodeUnknown {
+		e.code = Code(code)
+	}
+	if e.code == CodeUnknown {
+		e.code = CodeFromHTTPStatus(p.Status)
+	}
+	if fix, ok := p.Extensions["fix"].(string); ok {
+		e.fix = fix
+	}
+	if data, ok := p.Extensions["data"].([]interface{}); ok {
+		e.data = data
+	}
+	for key, val := range p.Extensions {
+		switch key {
+		case "code", "slug", "fix", "data":
+		default:
+			e.WithDetail(key, val)
+		}
+	}
+	return e
+}
@@ -0,0 +1,88 @@
+package errors
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+)
+
+func TestProblemDetails(t *testing.T) {
+	r := NewRegistry()
+	CodeQuota := Code(100)
+	r.RegisterSpec(CodeQuota, CodeSpec{
+		HTTPStatus: 402,
+		Name:       "quota",
+		Slug:       "quota_exceeded",
+		DocsURL:    "https://qri.io/problems/quota",
+		Fix:        "delete some datasets",
+	})
+	e := NewFriendly(CodeQuota, "quota exceeded", "you're out of storage", "b5").
+		WithRegistry(r).
+		WithDetail("used_bytes", 2048)
+
+	data, err := json.Marshal(ProblemDetails(e))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `{"code":100,"data":["b5"],"detail":"you're out of storage","fix":"delete some datasets","slug":"quota_exceeded","status":402,"title":"quota","type":"https://qri.io/problems/quota","used_bytes":2048}`
+	if string(data) != expect {
+		t.Errorf("problem mismatch.\nexpected: %s\ngot:      %s", expect, string(data))
+	}
+
+	got := &Problem{}
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != 402 || got.Type != "https://qri.io/problems/quota" {
+		t.Errorf("standard member mismatch. got: %#v", got)
+	}
+	if got.Extensions["slug"] != "quota_exceeded" {
+		t.Errorf("extension mismatch. expected: %s, got: %v", "quota_exceeded", got.Extensions["slug"])
+	}
+	if _, ok := got.Extensions["status"]; ok {
+		t.Errorf("expected standard members not to be extensions")
+	}
+}
+
+func TestProblemDetailsPlainError(t *testing.T) {
+	p := ProblemDetails(io.EOF)
+	if p.Status != 500 || p.Type != "about:blank" || p.Title != "Internal Server Error" {
+		t.Errorf("expected generic problem. got: %#v", p)
+	}
+	if p.Detail != "" {
+		t.Errorf("expected plain error messages not to leak into detail. got: %s", p.Detail)
+	}
+
+	p = ProblemDetails(New(CodeNotFound, "no row"))
+	if p.Type != "about:blank" || p.Status != 404 {
+		t.Errorf("expected about:blank 404 problem. got: %#v", p)
+	}
+}
+
+func TestFromProblem(t *testing.T) {
+	e := NewFriendlyFix(CodeNotFound, "no dataset", "couldn't find that dataset", "check the name", "b5").
+		WithDetail("ref", "b5/world_bank")
+	data, err := json.Marshal(ProblemDetails(e))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Problem{}
+	if err := json.Unmarshal(data, p); err != nil {
+		t.Fatal(err)
+	}
+	got := FromProblem(p)
+	if got.Code() != CodeNotFound {
+		t.Errorf("code mismatch. expected: %d, got: %d", CodeNotFound, got.Code())
+	}
+	if got.Friendly() != e.Friendly() {
+		t.Errorf("friendly mismatch. expected: %s, got: %s", e.Friendly(), got.Friendly())
+	}
+	if got.Details()["ref"] != "b5/world_bank" {
+		t.Errorf("expected extensions as details. got: %v", got.Details())
+	}
+
+	got = FromProblem(&Problem{Title: "Forbidden", Status: 403})
+	if got.Code() != CodeForbidden {
+		t.Errorf("status code mismatch. expected: %d, got: %d", CodeForbidden, got.Code())
+	}
+}
@@ -0,0 +1,72 @@
+package errors
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit describes the limit a rate limited request exceeded
+type RateLimit struct {
+	// Limit is the number of requests allowed in the current window
+	Limit int
+	// Remaining is the number of requests left in the current window
+	Remaining int
+	// Reset is when the current window ends
+	Reset time.Time
+}
+
+// NewRateLimited creates a CodeTooManyRequests error carrying rate limit
+// metadata. HTTP writers render the metadata as X-RateLimit-Limit,
+// X-RateLimit-Remaining, X-RateLimit-Reset and Retry-After headers
+func NewRateLimited(limit, remaining int, reset time.Time, message string, data ...interface{}) *Error {
+	e := newError(CodeTooManyRequests, message, data)
+	e.rateLimit = &RateLimit{Limit: limit, Remaining: remaining, Reset: reset}
+	return hooked(e)
+}
+
+// RateLimit returns the rate limit metadata of an error, and whether it has
+// any
+func (e Error) RateLimit() (RateLimit, bool) {
+	if e.rateLimit == nil {
+		return RateLimit{}, false
+	}
+	return *e.rateLimit, true
+}
+
+// RetryAfter returns how long to wait before the limit resets, rounded up to
+// the second
+func (rl RateLimit) RetryAfter(now time.Time) time.Duration {
+	wait := rl.Reset.Sub(now)
+	if wait <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(wait.Seconds())) * time.Second
+}
+
+// headers renders rate limit metadata as http headers
+func (rl RateLimit) headers(now time.Time) http.Header {
+	return http.Header{
+		"X-Ratelimit-Limit":     {strconv.Itoa(rl.Limit)},
+		"X-Ratelimit-Remaining": {strconv.Itoa(rl.Remaining)},
+		"X-Ratelimit-Reset":     {strconv.FormatInt(rl.Reset.Unix(), 10)},
+		"Retry-After":           {strconv.Itoa(int(rl.RetryAfter(now) / time.Second))},
+	}
+}
+
+// rateLimitFromHeaders reads rate limit metadata from http headers
+func rateLimitFromHeaders(h http.Header) (*RateLimit, bool) {
+	limit, err := strconv.Atoi(h.Get("X-Ratelimit-Limit"))
+	if err != nil {
+		return nil, false
+	}
+	rl := &RateLimit{Limit: limit}
+	rl.Remaining, _ = strconv.Atoi(h.Get("X-Ratelimit-Remaining"))
+	if reset, err := strconv.ParseInt(h.Get("X-Ratelimit-Reset"), 10, 64); err == nil {
+		rl.Reset = time.Unix(reset, 0)
+	} else if after, err := strconv.Atoi(h.Get("Retry-After")); err == nil {
+		rl.Reset = time.Now().Add(time.Duration(after) * time.Second).Truncate(time.Second)
+	}
+	return rl, true
+}
@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestNewRateLimited(t *testing.T) {
+	reset := time.Now().Add(30 * time.Second).Truncate(time.Second)
+	e := NewRateLimited(100, 0, reset, "user b5 over limit")
+	if e.Code() != CodeTooManyRequests {
+		t.Errorf("code mismatch. expected: %d, got: %d", CodeTooManyRequests, e.Code())
+	}
+	rl, ok := e.RateLimit()
+	if !ok || rl.Limit != 100 || rl.Remaining != 0 || !rl.Reset.Equal(reset) {
+		t.Errorf("rate limit mismatch. got: %v", rl)
+	}
+	if _, ok := New(CodeTooManyRequests, "slow down").RateLimit(); ok {
+		t.Errorf("expected plain errors to have no rate limit")
+	}
+
+	w := httptest.NewRecorder()
+	WriteHTTP(w, Wrap(CodeTooManyRequests, e, "listing datasets"))
+	expect := map[string]string{
+		"X-RateLimit-Limit":     "100",
+		"X-RateLimit-Remaining": "0",
+		"X-RateLimit-Reset":     strconv.FormatInt(reset.Unix(), 10),
+	}
+	for key, val := range expect {
+		if got := w.Header().Get(key); got != val {
+			t.Errorf("header %s mismatch. expected: %s, got: %s", key, val, got)
+		}
+	}
+	if got := w.Header().Get("Retry-After"); got != "30" && got != "29" {
+		t.Errorf("retry after mismatch. expected about 30 seconds, got: %s", got)
+	}
+
+	got := FromHTTPResponse(w.Result())
+	rl, ok = got.RateLimit()
+	if !ok || rl.Limit != 100 || !rl.Reset.Equal(reset) {
+		t.Errorf("expected client to read rate limit headers. got: %v", rl)
+	}
+}
+
+func TestRateLimitRetryAfter(t *testing.T) {
+	now := time.Now()
+	rl := RateLimit{Reset: now.Add(1500 * time.Millisecond)}
+	if got := rl.RetryAfter(now); got != 2*time.Second {
+		t.Errorf("retry after mismatch. expected: %s, got: %s", 2*time.Second, got)
+	}
+	rl.Reset = now.Add(-time.Second)
+	if got := rl.RetryAfter(now); got != 0 {
+		t.Errorf("expected past resets to retry immediately. got: %s", got)
+	}
+}
@@ -0,0 +1,1029 @@
+package errors
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Severity ranks how serious an error is
+type Severity int
+
+const (
+	// SeverityUnknown indicates severity hasn't been specified
+	SeverityUnknown Severity = iota
+	// SeverityDebug is only of interest while developing
+	SeverityDebug
+	// SeverityInfo is expected in normal operation, and needs no attention
+	SeverityInfo
+	// SeverityWarning may need attention, but isn't a failure of the system
+	SeverityWarning
+	// SeverityError is a failure that should be looked into
+	SeverityError
+	// SeverityCritical is a failure that needs immediate attention
+	SeverityCritical
+)
+
+var severityStrings = map[Severity]string{
+	SeverityUnknown:  "unknown",
+	SeverityDebug:    "debug",
+	SeverityInfo:     "info",
+	SeverityWarning:  "warning",
+	SeverityError:    "error",
+	SeverityCritical: "critical",
+}
+
+// String returns a lowercase name for the severity
+func (s Severity) String() string {
+	if str, ok := severityStrings[s]; ok {
+		return str
+	}
+	return "unknown"
+}
+
+// ParseSeverity converts the output of Severity.String back to a Severity
+func ParseSeverity(str string) (Severity, error) {
+	for s, ss := range severityStrings {
+		if ss == str {
+			return s, nil
+		}
+	}
+	return SeverityUnknown, New(CodeInvalidArgs, "unknown severity", str)
+}
+
+// CodeSpec describes a code. Integrations can use the spec to derive behavior
+// from a code alone
+type CodeSpec struct {
+	// HTTPStatus is the http status code responses should use
+	HTTPStatus int
+	// Name is the short string representation of the code
+	Name string
+	// Slug is a stable identifier for the code, safe for use on the wire and
+	// in logs, eg: "not_found". Slug defaults to Name with spaces replaced by
+	// underscores
+	Slug string
+	// Severity is the default severity of errors with this code
+	Severity Severity
+	// Retryable indicates retrying the operation that caused the error may
+	// succeed
+	Retryable bool
+	// DocsURL links to documentation for the code
+	DocsURL string
+	// GRPCCode is the numeric value of the google.golang.org/grpc/codes.Code
+	// this code maps to. Zero (codes.OK) means no mapping is set
+	GRPCCode uint32
+	// Friendly is the default user-facing message for errors with this code
+	Friendly string
+	// Fix is the default message on how to fix errors with this code
+	Fix string
+	// ExitStatus is the process exit status command line programs should use
+	// when exiting because of an error with this code. Zero means no exit
+	// status is set, and 1 will be used
+	ExitStatus int
+	// WSCloseStatus is the websocket close code connections should close with
+	// when terminated because of an error with this code. Zero means no close
+	// code is set, and one is derived from HTTPStatus
+	WSCloseStatus int
+	// Parent is the broader category this code belongs to, eg:
+	// a CodeTokenExpired code could have CodeUnauthorized as it's parent.
+	// CodeUnknown means the code has no parent
+	Parent Code
+}
+
+// slug returns the spec's slug, deriving one from Name if Slug isn't set
+func (spec CodeSpec) slug() string {
+	if spec.Slug != "" {
+		return spec.Slug
+	}
+	return strings.Replace(strings.ToLower(spec.Name), " ", "_", -1)
+}
+
+// defaultSpec is used for any code that hasn't been registered
+var defaultSpec = CodeSpec{HTTPStatus: 500, Name: "error", Slug: "unknown", Severity: SeverityError}
+
+// numeric values of google.golang.org/grpc/codes.Code, duplicated here to
+// keep this package free of a grpc dependency
+const (
+	grpcCanceled           uint32 = 1
+	grpcUnknown            uint32 = 2
+	grpcInvalidArgument    uint32 = 3
+	grpcDeadlineExceeded   uint32 = 4
+	grpcNotFound           uint32 = 5
+	grpcAlreadyExists      uint32 = 6
+	grpcPermissionDenied   uint32 = 7
+	grpcResourceExhausted  uint32 = 8
+	grpcFailedPrecondition uint32 = 9
+	grpcUnimplemented      uint32 = 12
+	grpcInternal           uint32 = 13
+	grpcUnavailable        uint32 = 14
+	grpcUnauthenticated    uint32 = 16
+)
+
+// httpGRPCCodes maps http statuses to grpc codes for codes that don't specify
+// a grpc code
+var httpGRPCCodes = map[int]uint32{
+	400: grpcInvalidArgument,
+	401: grpcUnauthenticated,
+	403: grpcPermissionDenied,
+	404: grpcNotFound,
+	409: grpcAlreadyExists,
+	410: grpcNotFound,
+	412: grpcFailedPrecondition,
+	429: grpcResourceExhausted,
+	499: grpcCanceled,
+	500: grpcInternal,
+	501: grpcUnimplemented,
+	503: grpcUnavailable,
+	504: grpcDeadlineExceeded,
+}
+
+// httpWSCloseStatuses maps http statuses to websocket close codes for codes
+// that don't specify a close code
+var httpWSCloseStatuses = map[int]int{
+	400: 1007, // invalid frame payload data
+	413: 1009, // message too big
+	429: 1013, // try again later
+	499: 1001, // going away
+	503: 1013,
+	504: 1013,
+}
+
+// httpStatusFallbacks classifies error statuses no registered code uses
+var httpStatusFallbacks = map[int]Code{
+	408: CodeTimeout,
+	423: CodeConflict,
+	424: CodePreconditionFailed,
+	428: CodePreconditionFailed,
+	451: CodeForbidden,
+	502: CodeUnavailable,
+	507: CodeUnavailable,
+}
+
+// builtinCodes lists the specs of codes defined by this package
+var builtinCodes = map[Code]CodeSpec{
+	CodeUnknown:       {HTTPStatus: 500, Name: "error", Slug: "unknown", Severity: SeverityError, GRPCCode: grpcUnknown, ExitStatus: 1},
+	CodeGeneric:       {HTTPStatus: 500, Name: "error", Slug: "generic", Severity: SeverityError, GRPCCode: grpcUnknown, ExitStatus: 1},
+	CodeInvalidSyntax: {HTTPStatus: 400, Name: "syntax", Slug: "invalid_syntax", Severity: SeverityWarning, GRPCCode: grpcInvalidArgument, ExitStatus: 3},
+	CodeInvalidArgs:   {HTTPStatus: 400, Name: "arguments", Slug: "invalid_args", Severity: SeverityWarning, GRPCCode: grpcInvalidArgument, ExitStatus: 3},
+	CodeUnauthorized: {
+		HTTPStatus: 401, Name: "auth", Slug: "unauthorized", Severity: SeverityWarning, GRPCCode: grpcUnauthenticated, ExitStatus: 5,
+		Friendly: "you need to be logged in to do that",
+		Fix:      "please log in and try again",
+	},
+	CodeForbidden: {
+		HTTPStatus: 403, Name: "auth", Slug: "forbidden", Severity: SeverityWarning, GRPCCode: grpcPermissionDenied, ExitStatus: 5,
+		Friendly: "you don't have permission to do that",
+	},
+	CodeNotFound: {HTTPStatus: 404, Name: "missing", Slug: "not_found", Severity: SeverityInfo, GRPCCode: grpcNotFound, ExitStatus: 4},
+	CodeUnavailable: {
+		HTTPStatus: 503, Name: "unavailable", Slug: "unavailable", Severity: SeverityError, GRPCCode: grpcUnavailable, ExitStatus: 6, Retryable: true,
+		Friendly: "this service is currently unavailable",
+		Fix:      "please try again later",
+	},
+	CodeConflict: {HTTPStatus: 409, Name: "conflict", Slug: "conflict", Severity: SeverityWarning, GRPCCode: grpcAlreadyExists, ExitStatus: 7},
+	CodeTooManyRequests: {
+		HTTPStatus: 429, Name: "rate limit", Slug: "too_many_requests", Severity: SeverityWarning, GRPCCode: grpcResourceExhausted, ExitStatus: 6, Retryable: true,
+		Friendly: "you're making too many requests",
+		Fix:      "please wait a moment and try again",
+	},
+	CodeTimeout: {
+		HTTPStatus: 504, Name: "timeout", Slug: "timeout", Severity: SeverityError, GRPCCode: grpcDeadlineExceeded, ExitStatus: 6, Retryable: true,
+		Friendly: "this is taking too long",
+		Fix:      "please try again",
+	},
+	CodeCancelled: {HTTPStatus: 499, Name: "cancelled", Slug: "cancelled", Severity: SeverityInfo, GRPCCode: grpcCanceled, ExitStatus: 130},
+	CodeNotImplemented: {
+		HTTPStatus: 501, Name: "not implemented", Slug: "not_implemented", Severity: SeverityWarning, GRPCCode: grpcUnimplemented, ExitStatus: 8,
+		Friendly: "this isn't supported yet",
+	},
+	CodePreconditionFailed: {HTTPStatus: 412, Name: "precondition", Slug: "precondition_failed", Severity: SeverityWarning, GRPCCode: grpcFailedPrecondition, ExitStatus: 7},
+	CodeGone:               {HTTPStatus: 410, Name: "gone", Slug: "gone", Severity: SeverityInfo, GRPCCode: grpcNotFound, ExitStatus: 4},
+	CodeInternal: {
+		HTTPStatus: 500, Name: "internal", Slug: "internal", Severity: SeverityCritical, GRPCCode: grpcInternal, ExitStatus: 1,
+		Friendly: "something went wrong on our end",
+	},
+	CodeUsage: {
+		HTTPStatus: 400, Name: "usage", Slug: "usage", Severity: SeverityWarning, GRPCCode: grpcInvalidArgument, ExitStatus: 2, Parent: CodeInvalidArgs,
+		Fix: "run with --help to see usage",
+	},
+}
+
+// defaultPrecedence ranks built-in codes from most to least important when
+// resolving a single code for an error that wraps many. Client mistakes
+// outrank server failures, because the client can act on them
+var defaultPrecedence = []Code{
+	CodeUnauthorized,
+	CodeForbidden,
+	CodeInvalidSyntax,
+	CodeUsage,
+	CodeInvalidArgs,
+	CodePreconditionFailed,
+	CodeConflict,
+	CodeTooManyRequests,
+	CodeNotFound,
+	CodeGone,
+	CodeTimeout,
+	CodeUnavailable,
+	CodeCancelled,
+	CodeNotImplemented,
+	CodeInternal,
+	CodeGeneric,
+}
+
+// DefaultRegistry is the registry used by package-level code functions, and
+// by any Error that hasn't been given a registry of it's own
+var DefaultRegistry = NewRegistry()
+
+// RegisterCodeSpec adds a code with a full spec to the default registry
+func RegisterCodeSpec(c Code, spec CodeSpec) error {
+	return DefaultRegistry.RegisterSpec(c, spec)
+}
+
+// MustRegisterCode is like RegisterCode but panics if the code can't be
+// registered. It's intended for use in package init functions
+func MustRegisterCode(c Code, httpStatus int, typeStr string) {
+	if err := RegisterCode(c, httpStatus, typeStr); err != nil {
+		panic(err)
+	}
+}
+
+// OnRegister adds a function to be called with each code registered in the
+// default registry, returning a function that removes it
+func OnRegister(fn func(Code, CodeSpec)) (remove func()) {
+	return DefaultRegistry.OnRegister(fn)
+}
+
+// RegisterCodes adds a set of codes to the default registry
+func RegisterCodes(specs map[Code]CodeSpec) error {
+	return DefaultRegistry.RegisterSpecs(specs)
+}
+
+// LookupCode returns the spec for a code from the default registry, and
+// whether the code is registered
+func LookupCode(c Code) (CodeSpec, bool) {
+	return DefaultRegistry.Lookup(c)
+}
+
+// CodeSeverity returns the default severity of a code
+func CodeSeverity(c Code) Severity {
+	return DefaultRegistry.CodeSeverity(c)
+}
+
+// CodeRetryable reports whether errors with a code can be retried
+func CodeRetryable(c Code) bool {
+	return DefaultRegistry.CodeRetryable(c)
+}
+
+// CodeGRPCStatus returns the numeric google.golang.org/grpc/codes.Code for a
+// code in the default registry. Convert the result with codes.Code(n)
+func CodeGRPCStatus(c Code) uint32 {
+	return DefaultRegistry.CodeGRPCStatus(c)
+}
+
+// CodeFromGRPCStatus finds a code in the default registry by numeric grpc
+// code
+func CodeFromGRPCStatus(gc uint32) Code {
+	return DefaultRegistry.CodeFromGRPCStatus(gc)
+}
+
+// CodeWSCloseStatus returns the websocket close code for a code in the default
+// registry
+func CodeWSCloseStatus(c Code) int {
+	return DefaultRegistry.CodeWSCloseStatus(c)
+}
+
+// CodeExitStatus returns the process exit status for a code in the default
+// registry
+func CodeExitStatus(c Code) int {
+	return DefaultRegistry.CodeExitStatus(c)
+}
+
+// CodeParent returns the parent category of a code in the default registry
+func CodeParent(c Code) Code {
+	return DefaultRegistry.CodeParent(c)
+}
+
+// CodeDocsURL returns the documentation link for a code, if any
+func CodeDocsURL(c Code) string {
+	return DefaultRegistry.CodeDocsURL(c)
+}
+
+// SetDefaultMessages overrides the default friendly & fix messages for a code
+// in the default registry
+func SetDefaultMessages(c Code, friendly, fix string) error {
+	return DefaultRegistry.SetDefaultMessages(c, friendly, fix)
+}
+
+// CodeFriendly returns the default friendly message for a code, if any
+func CodeFriendly(c Code) string {
+	return DefaultRegistry.CodeFriendly(c)
+}
+
+// CodeFix returns the default fix message for a code, if any
+func CodeFix(c Code) string {
+	return DefaultRegistry.CodeFix(c)
+}
+
+// CodeFromString finds a code in the default registry by it's string
+// representation
+func CodeFromString(str string) Code {
+	return DefaultRegistry.CodeFromString(str)
+}
+
+// CodeSlug returns the slug identifier for a code in the default registry
+func CodeSlug(c Code) string {
+	return DefaultRegistry.CodeSlug(c)
+}
+
+// CodeFromSlug finds a code in the default registry by slug
+func CodeFromSlug(slug string) Code {
+	return DefaultRegistry.CodeFromSlug(slug)
+}
+
+// CodeFromHTTPStatus finds a code in the default registry by http status
+func CodeFromHTTPStatus(status int) Code {
+	return DefaultRegistry.CodeFromHTTPStatus(status)
+}
+
+// Freeze locks the default registry against further changes
+func Freeze() {
+	DefaultRegistry.Freeze()
+}
+
+// AllocateRange hands out a block of codes from the default registry
+func AllocateRange(name string, size int) (CodeRange, error) {
+	return DefaultRegistry.AllocateRange(name, size)
+}
+
+// ReserveRange claims a fixed block of codes in the default registry
+func ReserveRange(name string, start Code, size int) (CodeRange, error) {
+	return DefaultRegistry.ReserveRange(name, start, size)
+}
+
+// RegisterAlias marks old as a deprecated alias of replacement in the default
+// registry
+func RegisterAlias(old, replacement Code) error {
+	return DefaultRegistry.RegisterAlias(old, replacement)
+}
+
+// IsDeprecated reports whether c is an alias in the default registry
+func IsDeprecated(c Code) bool {
+	return DefaultRegistry.IsDeprecated(c)
+}
+
+// Canonical returns the replacement for a deprecated code from the default
+// registry, or c itself if c isn't an alias
+func Canonical(c Code) Code {
+	return DefaultRegistry.Canonical(c)
+}
+
+// ValidateRegistry checks the default registry for taxonomy mistakes
+func ValidateRegistry() error {
+	return DefaultRegistry.Validate()
+}
+
+// CodeRange is a contiguous block of codes owned by a single library
+type CodeRange struct {
+	Name  string
+	Start Code
+	Size  int
+}
+
+// Code returns the i-th code in the range, panicking if i is out of bounds
+func (cr CodeRange) Code(i int) Code {
+	if i < 0 || i >= cr.Size {
+		panic(fmt.Sprintf("errors: index %d out of range %q of size %d", i, cr.Name, cr.Size))
+	}
+	return cr.Start + Code(i)
+}
+
+// Contains reports whether a code falls within the range
+func (cr CodeRange) Contains(c Code) bool {
+	return c >= cr.Start && c < cr.Start+Code(cr.Size)
+}
+
+// overlaps reports whether two ranges share any codes
+func (cr CodeRange) overlaps(b CodeRange) bool {
+	return cr.Start < b.Start+Code(b.Size) && b.Start < cr.Start+Code(cr.Size)
+}
+
+// Registry is a table of codes and their specs. A single binary can hold many
+// registries, which keeps libraries that define their own codes from
+// conflicting with one another
+type Registry struct {
+	lk      sync.RWMutex
+	frozen  bool
+	codes   map[Code]CodeSpec
+	aliases map[Code]Code
+	ranges  []CodeRange
+	hooks   []*func(Code, CodeSpec)
+	// precedence ranks codes for ResolveCode, highest first
+	precedence []Code
+}
+
+// NewRegistry creates a registry populated with the built-in codes
+func NewRegistry() *Registry {
+	r := &Registry{
+		codes:      map[Code]CodeSpec{},
+		aliases:    map[Code]Code{},
+		precedence: append([]Code(nil), defaultPrecedence...),
+	}
+	for c, spec := range builtinCodes {
+		r.codes[c] = spec
+	}
+	return r
+}
+
+// Freeze locks the registry against further changes. Services can freeze once
+// startup is complete to guarantee the code table is never mutated at runtime.
+// Any attempt to change a frozen registry returns an error
+func (r *Registry) Freeze() {
+	r.lk.Lock()
+	r.frozen = true
+	r.lk.Unlock()
+}
+
+// Frozen reports whether the registry has been frozen
+func (r *Registry) Frozen() bool {
+	r.lk.RLock()
+	defer r.lk.RUnlock()
+	return r.frozen
+}
+
+// registryError creates an error without calling hooks registered with
+// RegisterHook, for errors created with the registry lock held. Hooks may
+// read the registry, so exported methods call them with hookedErr once the
+// lock is released
+func registryError(c Code, message string, data ...interface{}) *Error {
+	return newError(c, message, data)
+}
+
+// hookedErr calls hooks with err if it's an *Error. It must be called without
+// the registry lock held
+func hookedErr(err error) error {
+	if e, ok := err.(*Error); ok {
+		return hooked(e)
+	}
+	return err
+}
+
+// errFrozen is returned when attempting to modify a frozen registry
+func errFrozen(c Code) *Error {
+	return registryError(CodeForbidden, "registry is frozen", c)
+}
+
+// Register adds a code to the registry, erroring if the code is already
+// registered
+func (r *Registry) Register(c Code, httpStatus int, typeStr string) error {
+	return r.RegisterSpec(c, CodeSpec{HTTPStatus: httpStatus, Name: typeStr, Severity: SeverityError})
+}
+
+// RegisterSpec adds a code with a full spec to the registry, erroring if the
+// code is already registered
+func (r *Registry) RegisterSpec(c Code, spec CodeSpec) error {
+	return r.RegisterSpecs(map[Code]CodeSpec{c: spec})
+}
+
+// SetDefaultMessages overrides the default friendly & fix messages for an
+// already-registered code
+func (r *Registry) SetDefaultMessages(c Code, friendly, fix string) error {
+	return hookedErr(r.setDefaultMessages(c, friendly, fix))
+}
+
+// setDefaultMessages implements SetDefaultMessages
+func (r *Registry) setDefaultMessages(c Code, friendly, fix string) error {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	if r.frozen {
+		return errFrozen(c)
+	}
+	spec, ok := r.codes[c]
+	if !ok {
+		return registryError(CodeNotFound, "code not registered", c)
+	}
+	spec.Friendly = friendly
+	spec.Fix = fix
+	r.codes[c] = spec
+	return nil
+}
+
+// RegisterSpecs adds a set of codes to the registry. All codes are checked
+// before any are registered, so if any code can't be registered none are
+func (r *Registry) RegisterSpecs(specs map[Code]CodeSpec) error {
+	r.lk.Lock()
+	for c := range specs {
+		var err error
+		if r.frozen {
+			err = errFrozen(c)
+		} else if c >= 0 && c <= MaxReservedCode {
+			err = registryError(CodeInvalidArgs, "code is reserved", c)
+		} else if r.registered(c) {
+			err = registryError(CodeInvalidArgs, "already registered", c)
+		}
+		if err != nil {
+			r.lk.Unlock()
+			return hookedErr(err)
+		}
+	}
+	codes := make([]Code, 0, len(specs))
+	for c, spec := range specs {
+		r.codes[c] = spec
+		codes = append(codes, c)
+	}
+	hooks := make([]*func(Code, CodeSpec), len(r.hooks))
+	copy(hooks, r.hooks)
+	r.lk.Unlock()
+
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	for _, c := range codes {
+		for _, hook := range hooks {
+			(*hook)(c, specs[c])
+		}
+	}
+	return nil
+}
+
+// OnRegister adds a function to be called with each code registered. fn is
+// called right away for every code that's already registered, so frameworks
+// can mirror the full code table regardless of when they're set up.
+// OnRegister returns a function that removes fn
+func (r *Registry) OnRegister(fn func(Code, CodeSpec)) (remove func()) {
+	h := &fn
+	r.lk.Lock()
+	r.hooks = append(r.hooks, h)
+	r.lk.Unlock()
+
+	for _, c := range r.Codes() {
+		spec, _ := r.Lookup(c)
+		fn(c, spec)
+	}
+	return func() {
+		r.lk.Lock()
+		defer r.lk.Unlock()
+		for i, hook := range r.hooks {
+			if hook == h {
+				r.hooks = append(r.hooks[:i:i], r.hooks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// registered reports whether c is a code or alias. must be called with the
+// registry lock held
+func (r *Registry) registered(c Code) bool {
+	if _, ok := r.codes[c]; ok {
+		return true
+	}
+	_, ok := r.aliases[c]
+	return ok
+}
+
+// RegisterAlias marks old as a deprecated alias of a registered code. Looking
+// up an alias resolves to the spec of it's replacement, so renumbered codes
+// keep working during migrations
+func (r *Registry) RegisterAlias(old, replacement Code) error {
+	return hookedErr(r.registerAlias(old, replacement))
+}
+
+// registerAlias implements RegisterAlias
+func (r *Registry) registerAlias(old, replacement Code) error {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	if r.frozen {
+		return errFrozen(old)
+	}
+	if old >= 0 && old <= MaxReservedCode {
+		return registryError(CodeInvalidArgs, "code is reserved", old)
+	}
+	if r.registered(old) {
+		return registryError(CodeInvalidArgs, "already registered", old)
+	}
+	if _, ok := r.codes[replacement]; !ok {
+		return registryError(CodeNotFound, "replacement code not registered", replacement)
+	}
+	r.aliases[old] = replacement
+	return nil
+}
+
+// IsDeprecated reports whether c is an alias for another code
+func (r *Registry) IsDeprecated(c Code) bool {
+	r.lk.RLock()
+	defer r.lk.RUnlock()
+	_, ok := r.aliases[c]
+	return ok
+}
+
+// Canonical returns the replacement for a deprecated code, or c itself if c
+// isn't an alias. Serializers should emit canonical codes
+func (r *Registry) Canonical(c Code) Code {
+	r.lk.RLock()
+	defer r.lk.RUnlock()
+	if replacement, ok := r.aliases[c]; ok {
+		return replacement
+	}
+	return c
+}
+
+// Aliases returns a map of deprecated codes to their replacements
+func (r *Registry) Aliases() map[Code]Code {
+	r.lk.RLock()
+	defer r.lk.RUnlock()
+	aliases := make(map[Code]Code, len(r.aliases))
+	for old, replacement := range r.aliases {
+		aliases[old] = replacement
+	}
+	return aliases
+}
+
+// Lookup returns the spec for a code, and whether the code is registered.
+// Aliases resolve to the spec of their replacement
+func (r *Registry) Lookup(c Code) (CodeSpec, bool) {
+	r.lk.RLock()
+	defer r.lk.RUnlock()
+	if replacement, ok := r.aliases[c]; ok {
+		c = replacement
+	}
+	spec, ok := r.codes[c]
+	return spec, ok
+}
+
+// AllocateRange hands out the lowest block of size codes above
+// MaxReservedCode that doesn't overlap any other range or registered code.
+// Libraries should allocate at init and register codes from the returned
+// range. Allocating the same name twice is an error, which catches two
+// packages sharing one binary claiming the same name
+func (r *Registry) AllocateRange(name string, size int) (CodeRange, error) {
+	cr, err := r.allocateRange(name, size)
+	return cr, hookedErr(err)
+}
+
+// allocateRange implements AllocateRange
+func (r *Registry) allocateRange(name string, size int) (CodeRange, error) {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	if err := r.checkRangeName(name, size); err != nil {
+		return CodeRange{}, err
+	}
+
+	cr := CodeRange{Name: name, Start: MaxReservedCode + 1, Size: size}
+	for {
+		col, ok := r.rangeCollision(cr)
+		if !ok {
+			break
+		}
+		cr.Start = col.next
+	}
+	r.ranges = append(r.ranges, cr)
+	return cr, nil
+}
+
+// ReserveRange claims a fixed block of codes for libraries that can't change
+// their numbering, erroring if the block collides with another range or an
+// already-registered code
+func (r *Registry) ReserveRange(name string, start Code, size int) (CodeRange, error) {
+	cr, err := r.reserveRange(name, start, size)
+	return cr, hookedErr(err)
+}
+
+// reserveRange implements ReserveRange
+func (r *Registry) reserveRange(name string, start Code, size int) (CodeRange, error) {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	if err := r.checkRangeName(name, size); err != nil {
+		return CodeRange{}, err
+	}
+
+	cr := CodeRange{Name: name, Start: start, Size: size}
+	if col, ok := r.rangeCollision(cr); ok {
+		data := append([]interface{}{cr.Name}, col.data...)
+		return CodeRange{}, registryError(CodeInvalidArgs, col.reason, data...)
+	}
+	r.ranges = append(r.ranges, cr)
+	return cr, nil
+}
+
+// Ranges lists all allocated & reserved ranges
+func (r *Registry) Ranges() []CodeRange {
+	r.lk.RLock()
+	defer r.lk.RUnlock()
+	return append([]CodeRange(nil), r.ranges...)
+}
+
+// checkRangeName confirms a range with the given name & size can be added.
+// must be called with the registry lock held
+func (r *Registry) checkRangeName(name string, size int) error {
+	if r.frozen {
+		return registryError(CodeForbidden, "registry is frozen", name)
+	}
+	if size <= 0 {
+		return registryError(CodeInvalidArgs, "range size must be positive", name, size)
+	}
+	for _, b := range r.ranges {
+		if b.Name == name {
+			return registryError(CodeInvalidArgs, "range name already allocated", name)
+		}
+	}
+	return nil
+}
+
+// collision describes what a range overlaps
+type collision struct {
+	reason string
+	data   []interface{}
+	// next is the first code past the overlapping codes
+	next Code
+}
+
+// rangeCollision reports whether cr overlaps reserved codes, another range, or
+// a registered code or alias, and what it overlaps. must be called with the
+// registry lock held
+func (r *Registry) rangeCollision(cr CodeRange) (collision, bool) {
+	if cr.overlaps(CodeRange{Start: 0, Size: int(MaxReservedCode) + 1}) {
+		return collision{reason: "range overlaps reserved codes", next: MaxReservedCode + 1}, true
+	}
+	for _, b := range r.ranges {
+		if cr.overlaps(b) {
+			return collision{reason: "range overlaps existing range", data: []interface{}{b.Name}, next: b.Start + Code(b.Size)}, true
+		}
+	}
+	for c := range r.codes {
+		if cr.Contains(c) {
+			return collision{reason: "range overlaps registered code", data: []interface{}{c}, next: c + 1}, true
+		}
+	}
+	for c := range r.aliases {
+		if cr.Contains(c) {
+			return collision{reason: "range overlaps registered alias", data: []interface{}{c}, next: c + 1}, true
+		}
+	}
+	return collision{}, false
+}
+
+// Validate checks registered application codes for taxonomy mistakes:
+// string names or slugs shared with another code, http statuses outside 100-599, and
+// missing default friendly messages. Built-in codes are exempt. Validate is
+// intended to run at init or in a test so mistakes fail fast. All problems
+// are reported in a single error
+func (r *Registry) Validate() error {
+	codes := r.Codes()
+	names := map[string]Code{}
+	slugs := map[string]Code{}
+	for _, c := range codes {
+		spec, _ := r.Lookup(c)
+		if _, ok := names[spec.Name]; !ok {
+			names[spec.Name] = c
+		}
+		if _, ok := slugs[spec.slug()]; !ok {
+			slugs[spec.slug()] = c
+		}
+	}
+
+	var problems []string
+	for _, c := range codes {
+		if c >= 0 && c <= MaxReservedCode {
+			continue
+		}
+		spec, _ := r.Lookup(c)
+		if other := names[spec.Name]; other != c {
+			problems = append(problems, fmt.Sprintf("code %d shares name %q with code %d", c, spec.Name, other))
+		}
+		if other := slugs[spec.slug()]; other != c {
+			problems = append(problems, fmt.Sprintf("code %d shares slug %q with code %d", c, spec.slug(), other))
+		}
+		if spec.HTTPStatus < 100 || spec.HTTPStatus > 599 {
+			problems = append(problems, fmt.Sprintf("code %d has invalid http status %d", c, spec.HTTPStatus))
+		}
+		if spec.Friendly == "" {
+			problems = append(problems, fmt.Sprintf("code %d has no default friendly message", c))
+		}
+		if spec.Parent != CodeUnknown {
+			if _, ok := r.Lookup(spec.Parent); !ok {
+				problems = append(problems, fmt.Sprintf("code %d has unregistered parent %d", c, spec.Parent))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return New(CodeInvalidArgs, fmt.Sprintf("invalid registry: %s", strings.Join(problems, "; ")))
+	}
+	return nil
+}
+
+// SetPrecedence sets the ranking ResolveCode uses to pick a representative
+// code, from most to least important. Codes in a category rank with their
+// nearest listed ancestor. Unlisted codes rank below all listed codes
+func (r *Registry) SetPrecedence(codes ...Code) error {
+	r.lk.Lock()
+	if r.frozen {
+		r.lk.Unlock()
+		return New(CodeForbidden, "registry is frozen")
+	}
+	r.precedence = append([]Code(nil), codes...)
+	r.lk.Unlock()
+	return nil
+}
+
+// rank returns c's position in the precedence list, lower is more important
+func (r *Registry) rank(c Code) int {
+	r.lk.RLock()
+	precedence := r.precedence
+	r.lk.RUnlock()
+
+	for i := 0; i < maxCategoryDepth && c != CodeUnknown; i++ {
+		c = r.Canonical(c)
+		for rank, pc := range precedence {
+			if pc == c {
+				return rank
+			}
+		}
+		c = r.CodeParent(c)
+	}
+	return len(precedence)
+}
+
+// Codes returns all registered codes in ascending order
+func (r *Registry) Codes() []Code {
+	r.lk.RLock()
+	defer r.lk.RUnlock()
+	codes := make([]Code, 0, len(r.codes))
+	for c := range r.codes {
+		codes = append(codes, c)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	return codes
+}
+
+// find returns the lowest registered code other than CodeUnknown for which
+// match returns true, returning CodeUnknown if no code matches
+func (r *Registry) find(match func(spec CodeSpec) bool) Code {
+	for _, c := range r.Codes() {
+		if c == CodeUnknown {
+			continue
+		}
+		if spec, ok := r.Lookup(c); ok && match(spec) {
+			return c
+		}
+	}
+	return CodeUnknown
+}
+
+// CodeFromString finds a code by it's string representation. When more than
+// one code shares a string the lowest code wins. Unmatched strings return
+// CodeUnknown
+func (r *Registry) CodeFromString(str string) Code {
+	return r.find(func(spec CodeSpec) bool { return spec.Name == str })
+}
+
+// CodeFromSlug finds a code by slug, returning CodeUnknown if no code matches
+func (r *Registry) CodeFromSlug(slug string) Code {
+	return r.find(func(spec CodeSpec) bool { return spec.slug() == slug })
+}
+
+// CodeFromHTTPStatus finds a code by http status. When more than one code
+// shares a status the lowest code wins. Error statuses no code uses fall back
+// to a builtin code for similar failures, then to CodeInvalidArgs for client
+// errors and CodeGeneric for server errors, so clients can classify any
+// failed response. Other unmatched statuses return CodeUnknown
+func (r *Registry) CodeFromHTTPStatus(status int) Code {
+	if c := r.find(func(spec CodeSpec) bool { return spec.HTTPStatus == status }); c != CodeUnknown {
+		return c
+	}
+	if c, ok := httpStatusFallbacks[status]; ok {
+		return c
+	}
+	switch {
+	case status >= 400 && status < 500:
+		return CodeInvalidArgs
+	case status >= 500 && status < 600:
+		return CodeGeneric
+	}
+	return CodeUnknown
+}
+
+// spec returns the spec for a code, falling back to defaultSpec
+func (r *Registry) spec(c Code) CodeSpec {
+	if spec, ok := r.Lookup(c); ok {
+		return spec
+	}
+	return defaultSpec
+}
+
+// CodeString returns a string representation of a code, defaulting to "error"
+func (r *Registry) CodeString(c Code) string {
+	return r.spec(c).Name
+}
+
+// CodeSlug returns the slug identifier for a code, defaulting to "unknown"
+func (r *Registry) CodeSlug(c Code) string {
+	return r.spec(c).slug()
+}
+
+// CodeHTTPStatus converts a Code to an http status code, defaulting to 500
+func (r *Registry) CodeHTTPStatus(c Code) int {
+	return r.spec(c).HTTPStatus
+}
+
+// CodeSeverity returns the default severity of a code, defaulting to
+// SeverityError
+func (r *Registry) CodeSeverity(c Code) Severity {
+	return r.spec(c).Severity
+}
+
+// CodeRetryable reports whether errors with a code can be retried
+func (r *Registry) CodeRetryable(c Code) bool {
+	return r.spec(c).Retryable
+}
+
+// CodeGRPCStatus returns the numeric google.golang.org/grpc/codes.Code for a
+// code. Codes registered without a grpc code fall back to a mapping from their
+// http status, defaulting to codes.Unknown
+func (r *Registry) CodeGRPCStatus(c Code) uint32 {
+	spec := r.spec(c)
+	if spec.GRPCCode != 0 {
+		return spec.GRPCCode
+	}
+	if gc, ok := httpGRPCCodes[spec.HTTPStatus]; ok {
+		return gc
+	}
+	return grpcUnknown
+}
+
+// CodeFromGRPCStatus finds a code by numeric grpc code. When more than one
+// code shares a grpc code the lowest code wins. Unmatched grpc codes return
+// CodeGeneric, and grpc's OK code returns CodeUnknown
+func (r *Registry) CodeFromGRPCStatus(gc uint32) Code {
+	if gc == 0 {
+		return CodeUnknown
+	}
+	for _, c := range r.Codes() {
+		if c != CodeUnknown && r.CodeGRPCStatus(c) == gc {
+			return c
+		}
+	}
+	return CodeGeneric
+}
+
+// CodeWSCloseStatus returns the websocket close code for a code. Codes
+// registered without a close code fall back to a mapping from their http
+// status. Other 4xx statuses give policy violation (1008), and anything else
+// internal error (1011)
+func (r *Registry) CodeWSCloseStatus(c Code) int {
+	spec := r.spec(c)
+	if spec.WSCloseStatus != 0 {
+		return spec.WSCloseStatus
+	}
+	if status, ok := httpWSCloseStatuses[spec.HTTPStatus]; ok {
+		return status
+	}
+	if spec.HTTPStatus >= 400 && spec.HTTPStatus < 500 {
+		return 1008
+	}
+	return 1011
+}
+
+// CodeExitStatus returns the process exit status for a code, defaulting to 1
+func (r *Registry) CodeExitStatus(c Code) int {
+	if status := r.spec(c).ExitStatus; status != 0 {
+		return status
+	}
+	return 1
+}
+
+// CodeParent returns the parent category of a code, returning CodeUnknown if
+// the code has no parent
+func (r *Registry) CodeParent(c Code) Code {
+	return r.spec(c).Parent
+}
+
+// maxCategoryDepth bounds walks up the category tree, guarding against cycles
+const maxCategoryDepth = 32
+
+// IsInCategory reports whether c is parent, or descends from parent
+func (r *Registry) IsInCategory(c, parent Code) bool {
+	for i := 0; i < maxCategoryDepth; i++ {
+		if c == parent {
+			return true
+		}
+		if c = r.CodeParent(c); c == CodeUnknown {
+			return false
+		}
+	}
+	return false
+}
+
+// CodeDocsURL returns the documentation link for a code, if any
+func (r *Registry) CodeDocsURL(c Code) string {
+	return r.spec(c).DocsURL
+}
+
+// CodeFriendly returns the default friendly message for a code, if any
+func (r *Registry) CodeFriendly(c Code) string {
+	return r.spec(c).Friendly
+}
+
+// CodeFix returns the default fix message for a code, if any
+func (r *Registry) CodeFix(c Code) string {
+	return r.spec(c).Fix
+}
@@ -0,0 +1,511 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRegistryIsolation(t *testing.T) {
+	a := NewRegistry()
+	b := NewRegistry()
+	CodeNoDatabase := Code(200)
+
+	if err := a.Register(CodeNoDatabase, 504, "database"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Register(CodeNoDatabase, 409, "conflict"); err != nil {
+		t.Errorf("expected registering a code in a separate registry not to error. got: %s", err)
+	}
+	if err := a.Register(CodeNoDatabase, 504, "database"); err == nil {
+		t.Error("expected registering an already-existing Code to error")
+	}
+
+	if got := a.CodeHTTPStatus(CodeNoDatabase); got != 504 {
+		t.Errorf("status mismatch. expected: %d, got: %d", 504, got)
+	}
+	if got := b.CodeString(CodeNoDatabase); got != "conflict" {
+		t.Errorf("string mismatch. expected: %s, got: %s", "conflict", got)
+	}
+	if got := CodeString(CodeNoDatabase); got != "error" {
+		t.Errorf("expected default registry to be unaffected. got: %s", got)
+	}
+	if got := a.CodeString(CodeNotFound); got != "missing" {
+		t.Errorf("expected new registries to include built-in codes. got: %s", got)
+	}
+}
+
+func TestErrorWithRegistry(t *testing.T) {
+	r := NewRegistry()
+	CodeNoDatabase := Code(201)
+	r.Register(CodeNoDatabase, 504, "database")
+
+	e := New(CodeNoDatabase, "no connection")
+	if e.Registry() != DefaultRegistry {
+		t.Errorf("expected errors to default to DefaultRegistry")
+	}
+	if e.Error() != "error: no connection" {
+		t.Errorf("error mismatch. expected: %s, got: %s", "error: no connection", e.Error())
+	}
+
+	e.WithRegistry(r)
+	if e.Error() != "database: no connection" {
+		t.Errorf("error mismatch. expected: %s, got: %s", "database: no connection", e.Error())
+	}
+}
+
+func TestRegisterSpec(t *testing.T) {
+	r := NewRegistry()
+	CodeRateLimited := Code(202)
+	spec := CodeSpec{
+		HTTPStatus: 429,
+		Name:       "rate limit",
+		Severity:   SeverityWarning,
+		Retryable:  true,
+		DocsURL:    "https://qri.io/docs/errors/rate-limit",
+		GRPCCode:   8,
+	}
+	if err := r.RegisterSpec(CodeRateLimited, spec); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RegisterSpec(CodeRateLimited, spec); err == nil {
+		t.Error("expected registering an already-existing Code to error")
+	}
+
+	got, ok := r.Lookup(CodeRateLimited)
+	if !ok {
+		t.Fatal("expected code to be registered")
+	}
+	if got != spec {
+		t.Errorf("spec mismatch. expected: %v, got: %v", spec, got)
+	}
+	if r.CodeSeverity(CodeRateLimited) != SeverityWarning {
+		t.Errorf("severity mismatch. expected: %s, got: %s", SeverityWarning, r.CodeSeverity(CodeRateLimited))
+	}
+	if !r.CodeRetryable(CodeRateLimited) {
+		t.Errorf("expected code to be retryable")
+	}
+	if r.CodeDocsURL(CodeRateLimited) != spec.DocsURL {
+		t.Errorf("docs url mismatch. expected: %s, got: %s", spec.DocsURL, r.CodeDocsURL(CodeRateLimited))
+	}
+
+	if _, ok := r.Lookup(Code(-1)); ok {
+		t.Errorf("expected unregistered code lookup to report false")
+	}
+	if r.CodeSeverity(Code(-1)) != SeverityError {
+		t.Errorf("expected unregistered codes to default to SeverityError")
+	}
+}
+
+func TestReverseLookups(t *testing.T) {
+	cases := []struct {
+		str    string
+		status int
+		expect Code
+	}{
+		{"missing", 404, CodeNotFound},
+		{"unavailable", 503, CodeUnavailable},
+		{"error", 500, CodeGeneric},
+		{"auth", 401, CodeUnauthorized},
+	}
+
+	for i, c := range cases {
+		if got := CodeFromString(c.str); got != c.expect {
+			t.Errorf("case %d string %q mismatch. expected: %d, got: %d", i, c.str, c.expect, got)
+		}
+		if got := CodeFromHTTPStatus(c.status); got != c.expect {
+			t.Errorf("case %d status %d mismatch. expected: %d, got: %d", i, c.status, c.expect, got)
+		}
+	}
+
+	if got := CodeFromString("nope"); got != CodeUnknown {
+		t.Errorf("expected unmatched string to return CodeUnknown. got: %d", got)
+	}
+	if got := CodeFromHTTPStatus(302); got != CodeUnknown {
+		t.Errorf("expected unmatched status to return CodeUnknown. got: %d", got)
+	}
+}
+
+func TestCodeFromHTTPStatusFallbacks(t *testing.T) {
+	cases := []struct {
+		status int
+		expect Code
+	}{
+		{408, CodeTimeout},
+		{502, CodeUnavailable},
+		{504, CodeTimeout},
+		{418, CodeInvalidArgs},
+		{422, CodeInvalidArgs},
+		{599, CodeGeneric},
+		{200, CodeUnknown},
+	}
+	for _, c := range cases {
+		if got := CodeFromHTTPStatus(c.status); got != c.expect {
+			t.Errorf("status %d mismatch. expected: %d, got: %d", c.status, c.expect, got)
+		}
+	}
+
+	r := NewRegistry()
+	if err := r.RegisterSpec(Code(100), CodeSpec{HTTPStatus: 422, Name: "unprocessable"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.CodeFromHTTPStatus(422); got != Code(100) {
+		t.Errorf("expected registered codes to take precedence over fallbacks. got: %d", got)
+	}
+}
+
+func TestRegistryFreeze(t *testing.T) {
+	r := NewRegistry()
+	if r.Frozen() {
+		t.Errorf("expected new registry not to be frozen")
+	}
+	r.Freeze()
+	if !r.Frozen() {
+		t.Errorf("expected registry to be frozen")
+	}
+
+	err := r.Register(Code(204), 500, "late")
+	if err == nil {
+		t.Fatal("expected registering on a frozen registry to error")
+	}
+	if e, ok := err.(*Error); !ok || e.Code() != CodeForbidden {
+		t.Errorf("expected frozen error to have CodeForbidden. got: %s", err)
+	}
+	if err := r.SetDefaultMessages(CodeNotFound, "gone", ""); err == nil {
+		t.Errorf("expected setting messages on a frozen registry to error")
+	}
+	if _, ok := r.Lookup(Code(204)); ok {
+		t.Errorf("expected code not to be registered")
+	}
+}
+
+func TestReservedCodes(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(MaxReservedCode, 500, "mine"); err == nil {
+		t.Errorf("expected registering a reserved code to error")
+	}
+	if err := r.Register(MaxReservedCode+1, 500, "mine"); err != nil {
+		t.Errorf("expected registering the first unreserved code not to error. got: %s", err)
+	}
+}
+
+func TestAllocateRange(t *testing.T) {
+	r := NewRegistry()
+	r.Register(Code(100), 500, "app")
+
+	a, err := r.AllocateRange("dataset", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Start != 101 {
+		t.Errorf("start mismatch. expected: %d, got: %d", 101, a.Start)
+	}
+	b, err := r.AllocateRange("p2p", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Start != 111 {
+		t.Errorf("start mismatch. expected: %d, got: %d", 111, b.Start)
+	}
+	if b.Code(4) != 115 {
+		t.Errorf("code mismatch. expected: %d, got: %d", 115, b.Code(4))
+	}
+	if !a.Contains(110) || a.Contains(111) {
+		t.Errorf("range bounds mismatch for %v", a)
+	}
+
+	if _, err := r.AllocateRange("dataset", 2); err == nil {
+		t.Errorf("expected allocating a duplicate range name to error")
+	}
+	if _, err := r.AllocateRange("empty", 0); err == nil {
+		t.Errorf("expected allocating an empty range to error")
+	}
+	if _, err := r.ReserveRange("fixed", 105, 10); err == nil {
+		t.Errorf("expected reserving an overlapping range to error")
+	}
+	if _, err := r.ReserveRange("builtin", 90, 20); err == nil {
+		t.Errorf("expected reserving a range overlapping reserved codes to error")
+	}
+	if _, err := r.ReserveRange("fixed", 500, 10); err != nil {
+		t.Errorf("unexpected error reserving range: %s", err)
+	}
+	if len(r.Ranges()) != 3 {
+		t.Errorf("ranges length mismatch. expected: %d, got: %d", 3, len(r.Ranges()))
+	}
+}
+
+func TestAllocateRangeSkipsCollisions(t *testing.T) {
+	r := NewRegistry()
+	specs := map[Code]CodeSpec{}
+	for c := Code(101); c < 20101; c++ {
+		specs[c] = CodeSpec{HTTPStatus: 500, Name: fmt.Sprintf("app %d", c)}
+	}
+	if err := r.RegisterSpecs(specs); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReserveRange("fixed", 20101, 1000); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(Code(21150), 500, "stray"); err != nil {
+		t.Fatal(err)
+	}
+
+	cr, err := r.AllocateRange("dataset", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cr.Start != 21151 {
+		t.Errorf("start mismatch. expected: %d, got: %d", 21151, cr.Start)
+	}
+
+	_, err = r.ReserveRange("overlap", 21100, 10)
+	if e, ok := err.(*Error); !ok || e.Message() != "range overlaps existing range" {
+		t.Errorf("expected an overlapping range error. got: %v", err)
+	}
+}
+
+func TestRegisterAlias(t *testing.T) {
+	r := NewRegistry()
+	oldCode, newCode := Code(300), Code(310)
+	r.Register(newCode, 409, "conflict")
+
+	if err := r.RegisterAlias(oldCode, Code(311)); err == nil {
+		t.Errorf("expected aliasing to an unregistered code to error")
+	}
+	if err := r.RegisterAlias(oldCode, newCode); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RegisterAlias(oldCode, newCode); err == nil {
+		t.Errorf("expected registering a duplicate alias to error")
+	}
+	if err := r.Register(oldCode, 500, "old"); err == nil {
+		t.Errorf("expected registering a code that's already an alias to error")
+	}
+
+	if !r.IsDeprecated(oldCode) || r.IsDeprecated(newCode) {
+		t.Errorf("deprecation mismatch")
+	}
+	if got := r.Canonical(oldCode); got != newCode {
+		t.Errorf("canonical mismatch. expected: %d, got: %d", newCode, got)
+	}
+	if got := r.Canonical(newCode); got != newCode {
+		t.Errorf("canonical mismatch. expected: %d, got: %d", newCode, got)
+	}
+	if got := r.CodeString(oldCode); got != "conflict" {
+		t.Errorf("expected alias to resolve to replacement string. got: %s", got)
+	}
+	if len(r.Aliases()) != 1 {
+		t.Errorf("aliases length mismatch. expected: %d, got: %d", 1, len(r.Aliases()))
+	}
+}
+
+func TestRegistryValidate(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Validate(); err != nil {
+		t.Errorf("expected built-in registry to be valid. got: %s", err)
+	}
+
+	r.RegisterSpec(Code(100), CodeSpec{HTTPStatus: 504, Name: "database", Friendly: "no database"})
+	if err := r.Validate(); err != nil {
+		t.Errorf("unexpected validation error: %s", err)
+	}
+
+	r.RegisterSpec(Code(101), CodeSpec{HTTPStatus: 700, Name: "missing"})
+	err := r.Validate()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	expect := `arguments: invalid registry: code 101 shares name "missing" with code 6; code 101 has invalid http status 700; code 101 has no default friendly message`
+	if err.Error() != expect {
+		t.Errorf("error mismatch.\nexpected: %s\ngot:      %s", expect, err.Error())
+	}
+}
+
+func TestCodeSlugs(t *testing.T) {
+	if got := CodeSlug(CodeNotFound); got != "not_found" {
+		t.Errorf("slug mismatch. expected: %s, got: %s", "not_found", got)
+	}
+	if got := CodeFromSlug("invalid_args"); got != CodeInvalidArgs {
+		t.Errorf("code mismatch. expected: %d, got: %d", CodeInvalidArgs, got)
+	}
+	if got := CodeFromSlug("nope"); got != CodeUnknown {
+		t.Errorf("expected unmatched slug to return CodeUnknown. got: %d", got)
+	}
+
+	r := NewRegistry()
+	r.Register(Code(100), 504, "no database")
+	r.RegisterSpec(Code(101), CodeSpec{HTTPStatus: 409, Name: "conflict", Slug: "dataset_conflict"})
+	if got := r.CodeSlug(Code(100)); got != "no_database" {
+		t.Errorf("expected slug to derive from name. got: %s", got)
+	}
+	if got := r.CodeFromSlug("dataset_conflict"); got != Code(101) {
+		t.Errorf("code mismatch. expected: %d, got: %d", 101, got)
+	}
+}
+
+func TestBuiltinCodes(t *testing.T) {
+	cases := []struct {
+		code   Code
+		status int
+		slug   string
+	}{
+		{CodeConflict, 409, "conflict"},
+		{CodeTooManyRequests, 429, "too_many_requests"},
+		{CodeTimeout, 504, "timeout"},
+		{CodeCancelled, 499, "cancelled"},
+		{CodeNotImplemented, 501, "not_implemented"},
+		{CodePreconditionFailed, 412, "precondition_failed"},
+		{CodeGone, 410, "gone"},
+		{CodeInternal, 500, "internal"},
+	}
+
+	for _, c := range cases {
+		if c.code > MaxReservedCode {
+			t.Errorf("built-in code %s is outside the reserved range", c.slug)
+		}
+		if got := CodeHTTPStatus(c.code); got != c.status {
+			t.Errorf("%s status mismatch. expected: %d, got: %d", c.slug, c.status, got)
+		}
+		if got := CodeSlug(c.code); got != c.slug {
+			t.Errorf("slug mismatch. expected: %s, got: %s", c.slug, got)
+		}
+	}
+}
+
+func TestValidateParent(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterSpec(Code(100), CodeSpec{HTTPStatus: 401, Name: "expired", Friendly: "expired", Parent: Code(150)})
+	if err := r.Validate(); err == nil {
+		t.Errorf("expected unregistered parent to fail validation")
+	}
+}
+
+func TestRegisterCodes(t *testing.T) {
+	r := NewRegistry()
+	r.Register(Code(102), 500, "existing")
+	err := r.RegisterSpecs(map[Code]CodeSpec{
+		Code(100): {HTTPStatus: 504, Name: "database"},
+		Code(102): {HTTPStatus: 409, Name: "conflict"},
+	})
+	if err == nil {
+		t.Errorf("expected registering an existing code to error")
+	}
+	if _, ok := r.Lookup(Code(100)); ok {
+		t.Errorf("expected failed batch registration not to register any codes")
+	}
+
+	err = r.RegisterSpecs(map[Code]CodeSpec{
+		Code(100): {HTTPStatus: 504, Name: "database"},
+		Code(101): {HTTPStatus: 409, Name: "conflict"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.CodeString(Code(101)) != "conflict" {
+		t.Errorf("string mismatch. expected: %s, got: %s", "conflict", r.CodeString(Code(101)))
+	}
+}
+
+func TestMustRegisterCode(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected registering an existing code to panic")
+		}
+	}()
+	MustRegisterCode(CodeNotFound, 404, "missing")
+}
+
+func TestOnRegister(t *testing.T) {
+	r := NewRegistry()
+	seen := map[Code]string{}
+	remove := r.OnRegister(func(c Code, spec CodeSpec) {
+		seen[c] = spec.Name
+	})
+	if seen[CodeNotFound] != "missing" {
+		t.Errorf("expected hook to be called with existing codes")
+	}
+
+	r.Register(Code(100), 504, "database")
+	r.RegisterSpecs(map[Code]CodeSpec{Code(101): {Name: "conflict"}})
+	r.Register(Code(100), 504, "database")
+	if seen[Code(100)] != "database" || seen[Code(101)] != "conflict" {
+		t.Errorf("expected hook to be called with new codes. got: %v", seen)
+	}
+	if len(seen) != len(r.Codes()) {
+		t.Errorf("hook call count mismatch. expected: %d, got: %d", len(r.Codes()), len(seen))
+	}
+
+	remove()
+	r.Register(Code(102), 500, "removed")
+	if _, ok := seen[Code(102)]; ok {
+		t.Errorf("expected removed hook not to be called")
+	}
+}
+
+func TestCodeGRPCStatus(t *testing.T) {
+	if got := CodeGRPCStatus(CodeNotFound); got != 5 {
+		t.Errorf("grpc code mismatch. expected: %d, got: %d", 5, got)
+	}
+	if got := CodeGRPCStatus(CodeUnauthorized); got != 16 {
+		t.Errorf("grpc code mismatch. expected: %d, got: %d", 16, got)
+	}
+
+	r := NewRegistry()
+	r.Register(Code(100), 429, "slow down")
+	r.Register(Code(101), 418, "teapot")
+	r.RegisterSpec(Code(102), CodeSpec{HTTPStatus: 400, Name: "range", GRPCCode: 11})
+	if got := r.CodeGRPCStatus(Code(100)); got != 8 {
+		t.Errorf("expected grpc code to derive from http status. got: %d", got)
+	}
+	if got := r.CodeGRPCStatus(Code(101)); got != 2 {
+		t.Errorf("expected unmapped http status to give codes.Unknown. got: %d", got)
+	}
+	if got := r.CodeGRPCStatus(Code(102)); got != 11 {
+		t.Errorf("expected explicit grpc code. got: %d", got)
+	}
+}
+
+func TestCodeWSCloseStatus(t *testing.T) {
+	cases := []struct {
+		code   Code
+		expect int
+	}{
+		{CodeInvalidArgs, 1007},
+		{CodeUnauthorized, 1008},
+		{CodeNotFound, 1008},
+		{CodeTooManyRequests, 1013},
+		{CodeUnavailable, 1013},
+		{CodeCancelled, 1001},
+		{CodeInternal, 1011},
+		{CodeGeneric, 1011},
+	}
+	for _, c := range cases {
+		if got := CodeWSCloseStatus(c.code); got != c.expect {
+			t.Errorf("close code mismatch for %s. expected: %d, got: %d", CodeSlug(c.code), c.expect, got)
+		}
+	}
+
+	r := NewRegistry()
+	r.RegisterSpec(Code(100), CodeSpec{HTTPStatus: 409, Name: "stale", WSCloseStatus: 4001})
+	if got := r.CodeWSCloseStatus(Code(100)); got != 4001 {
+		t.Errorf("expected explicit close code. got: %d", got)
+	}
+}
+
+func TestCodeFromGRPCStatus(t *testing.T) {
+	cases := []struct {
+		gc     uint32
+		expect Code
+	}{
+		{0, CodeUnknown},
+		{5, CodeNotFound},
+		{16, CodeUnauthorized},
+		{4, CodeTimeout},
+		{2, CodeGeneric},
+		{15, CodeGeneric},
+	}
+	for _, c := range cases {
+		if got := CodeFromGRPCStatus(c.gc); got != c.expect {
+			t.Errorf("grpc code %d mismatch. expected: %d, got: %d", c.gc, c.expect, got)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package errors
+
+import (
+	"context"
+	"sync"
+)
+
+// Reporter ships errors somewhere, like an error tracker or alerting system.
+// Applications set a reporter once with SetReporter and call Report, instead
+// of scattering SDK calls. Report must be safe for concurrent use
+type Reporter interface {
+	Report(ctx context.Context, e *Error)
+}
+
+// ReporterFunc adapts a function to the Reporter interface
+type ReporterFunc func(ctx context.Context, e *Error)
+
+// Report implements the Reporter interface
+func (fn ReporterFunc) Report(ctx context.Context, e *Error) {
+	fn(ctx, e)
+}
+
+// multiReporter fans reports out to a list of reporters
+type multiReporter []Reporter
+
+// Report implements the Reporter interface
+func (m multiReporter) Report(ctx context.Context, e *Error) {
+	for _, r := range m {
+		r.Report(ctx, e)
+	}
+}
+
+// MultiReporter creates a reporter that reports to every reporter in order.
+// nil reporters are skipped
+func MultiReporter(reporters ...Reporter) Reporter {
+	m := make(multiReporter, 0, len(reporters))
+	for _, r := range reporters {
+		switch x := r.(type) {
+		case nil:
+		case multiReporter:
+			m = append(m, x...)
+		default:
+			m = append(m, r)
+		}
+	}
+	return m
+}
+
+// reporter is the reporter set with SetReporter
+var reporter = struct {
+	sync.RWMutex
+	r Reporter
+}{}
+
+// SetReporter sets the reporter Report sends errors to. Combine reporters
+// with MultiReporter. nil disables reporting
+func SetReporter(r Reporter) {
+	reporter.Lock()
+	reporter.r = r
+	reporter.Unlock()
+}
+
+// Report sends an error to the reporter set with SetReporter, doing nothing
+// when no reporter is set. The nearest *Error in err's chain is reported.
+// Errors that aren't an *Error are wrapped with CodeUnknown, or the code of a
+// context error. nil errors aren't reported
+func Report(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	reporter.RLock()
+	r := reporter.r
+	reporter.RUnlock()
+	if r == nil {
+		return
+	}
+	e, ok := AsError(err)
+	if !ok {
+		e = &Error{code: contextCode(err), cause: err}
+	}
+	r.Report(ctx, e)
+}
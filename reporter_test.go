@@ -0,0 +1,56 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	defer SetReporter(nil)
+
+	var a, b []*Error
+	SetReporter(MultiReporter(
+		ReporterFunc(func(ctx context.Context, e *Error) { a = append(a, e) }),
+		nil,
+		MultiReporter(ReporterFunc(func(ctx context.Context, e *Error) { b = append(b, e) })),
+	))
+
+	ctx := context.Background()
+	err := New(CodeNotFound, "no dataset")
+	Report(ctx, fmt.Errorf("loading: %w", err))
+	Report(ctx, context.DeadlineExceeded)
+	Report(ctx, nil)
+
+	if len(a) != 2 || len(b) != 2 {
+		t.Fatalf("expected every reporter to get both reports. got: %d, %d", len(a), len(b))
+	}
+	if a[0] != err {
+		t.Errorf("expected the nearest *Error to be reported. got: %v", a[0])
+	}
+	if a[1].Code() != CodeTimeout {
+		t.Errorf("code mismatch. expected: %d, got: %d", CodeTimeout, a[1].Code())
+	}
+
+	SetReporter(nil)
+	Report(ctx, err)
+	if len(a) != 2 {
+		t.Errorf("expected no reports once the reporter is removed")
+	}
+}
+
+func TestRecoverReports(t *testing.T) {
+	defer SetReporter(nil)
+
+	var reported []*Error
+	SetReporter(ReporterFunc(func(ctx context.Context, e *Error) { reported = append(reported, e) }))
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("oh no")
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if len(reported) != 1 || reported[0].Code() != CodeInternal {
+		t.Errorf("expected the panic to be reported. got: %v", reported)
+	}
+}
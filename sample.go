@@ -0,0 +1,117 @@
+package errors
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// maxSampledFingerprints caps the fingerprints a SampledReporter tracks.
+// When the cap is reached the least frequent fingerprint is dropped, so
+// storms in progress stay sampled
+const maxSampledFingerprints = 4096
+
+// SampleOptions configures a SampledReporter. Both limits apply separately
+// to every fingerprint, and a report must pass both to be forwarded
+type SampleOptions struct {
+	// Every forwards one in Every reports, starting with the first. Zero or
+	// one forwards every report
+	Every int
+	// Rate is the number of reports a second forwarded once Burst is spent.
+	// Zero disables rate limiting
+	Rate float64
+	// Burst is the number of reports forwarded before Rate applies. Burst
+	// defaults to one
+	Burst int
+}
+
+// SampledReporter forwards a sample of reports to another reporter, so a
+// storm of a single error doesn't overwhelm the reporting backend or budget.
+// Reports are grouped by Fingerprint
+type SampledReporter struct {
+	next Reporter
+	opts SampleOptions
+	now  func() time.Time
+
+	lk     sync.Mutex
+	states map[string]*sampleState
+}
+
+// sampleState tracks sampling for one fingerprint
+type sampleState struct {
+	count  int
+	tokens float64
+	last   time.Time
+	// seen is the time of the latest report
+	seen time.Time
+}
+
+// NewSampledReporter wraps a reporter with sampling
+func NewSampledReporter(next Reporter, opts SampleOptions) *SampledReporter {
+	if opts.Burst < 1 {
+		opts.Burst = 1
+	}
+	return &SampledReporter{
+		next:   next,
+		opts:   opts,
+		now:    time.Now,
+		states: map[string]*sampleState{},
+	}
+}
+
+// Report implements the Reporter interface
+func (s *SampledReporter) Report(ctx context.Context, e *Error) {
+	if s.sample(Fingerprint(e)) {
+		s.next.Report(ctx, e)
+	}
+}
+
+// sample reports whether to forward a report with a fingerprint
+func (s *SampledReporter) sample(fp string) bool {
+	now := s.now()
+	s.lk.Lock()
+	defer s.lk.Unlock()
+
+	st, ok := s.states[fp]
+	if !ok {
+		if len(s.states) >= maxSampledFingerprints {
+			s.dropLeastFrequent()
+		}
+		st = &sampleState{tokens: float64(s.opts.Burst), last: now}
+		s.states[fp] = st
+	}
+
+	st.count++
+	st.seen = now
+	if s.opts.Every > 1 && (st.count-1)%s.opts.Every != 0 {
+		return false
+	}
+	if s.opts.Rate <= 0 {
+		return true
+	}
+	st.tokens += now.Sub(st.last).Seconds() * s.opts.Rate
+	if max := float64(s.opts.Burst); st.tokens > max {
+		st.tokens = max
+	}
+	st.last = now
+	if st.tokens < 1 {
+		return false
+	}
+	st.tokens--
+	return true
+}
+
+// dropLeastFrequent removes the state of the least frequent, least recent
+// fingerprint. s.lk must be held
+func (s *SampledReporter) dropLeastFrequent() {
+	var drop string
+	var dropState *sampleState
+	for fp, st := range s.states {
+		if dropState == nil || st.count < dropState.count || (st.count == dropState.count && st.seen.Before(dropState.seen)) {
+			drop, dropState = fp, st
+		}
+	}
+	if dropState != nil {
+		delete(s.states, drop)
+	}
+}
@@ -0,0 +1,63 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSampledReporterEvery(t *testing.T) {
+	var forwarded int
+	s := NewSampledReporter(ReporterFunc(func(ctx context.Context, e *Error) { forwarded++ }), SampleOptions{Every: 3})
+
+	ctx := context.Background()
+	for i := 0; i < 7; i++ {
+		s.Report(ctx, New(CodeNotFound, "no dataset"))
+	}
+	if forwarded != 3 {
+		t.Errorf("forwarded count mismatch. expected: %d, got: %d", 3, forwarded)
+	}
+}
+
+func TestSampledReporterRate(t *testing.T) {
+	var forwarded []string
+	s := NewSampledReporter(ReporterFunc(func(ctx context.Context, e *Error) {
+		forwarded = append(forwarded, e.Message())
+	}), SampleOptions{Rate: 1, Burst: 2})
+	now := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	storm := func() *Error { return New(CodeUnavailable, "storm") }
+	for i := 0; i < 5; i++ {
+		s.Report(ctx, storm())
+	}
+	s.Report(ctx, New(CodeTimeout, "other"))
+	if len(forwarded) != 3 {
+		t.Errorf("expected the burst & other fingerprints to be forwarded. got: %v", forwarded)
+	}
+
+	now = now.Add(1500 * time.Millisecond)
+	s.Report(ctx, storm())
+	s.Report(ctx, storm())
+	if len(forwarded) != 4 {
+		t.Errorf("expected one report after refilling a token. got: %v", forwarded)
+	}
+}
+
+func TestSampledReporterCap(t *testing.T) {
+	s := NewSampledReporter(ReporterFunc(func(ctx context.Context, e *Error) {}), SampleOptions{Every: 100})
+	for i := 0; i < 10; i++ {
+		s.sample("storm")
+	}
+	for i := 0; i < maxSampledFingerprints*2; i++ {
+		s.sample(fmt.Sprintf("one-off %d", i))
+	}
+	if len(s.states) > maxSampledFingerprints {
+		t.Errorf("expected at most %d fingerprints. got: %d", maxSampledFingerprints, len(s.states))
+	}
+	if s.sample("storm") {
+		t.Errorf("expected reaching the cap to keep sampling a storm in progress")
+	}
+}
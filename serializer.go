@@ -0,0 +1,168 @@
+package errors
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"mime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Serializer converts errors to and from a wire format
+type Serializer interface {
+	Marshal(*Error) ([]byte, error)
+	Unmarshal([]byte) (*Error, error)
+}
+
+// SerializerFuncs adapts a pair of functions to the Serializer interface
+type SerializerFuncs struct {
+	MarshalFunc   func(*Error) ([]byte, error)
+	UnmarshalFunc func([]byte) (*Error, error)
+}
+
+// Marshal calls s.MarshalFunc
+func (s SerializerFuncs) Marshal(e *Error) ([]byte, error) {
+	return s.MarshalFunc(e)
+}
+
+// Unmarshal calls s.UnmarshalFunc
+func (s SerializerFuncs) Unmarshal(data []byte) (*Error, error) {
+	return s.UnmarshalFunc(data)
+}
+
+// serializers maps media types to the serializer for that type
+var serializers = struct {
+	lk     sync.RWMutex
+	byType map[string]Serializer
+}{byType: map[string]Serializer{}}
+
+// RegisterSerializer sets the serializer for a content type, replacing any
+// existing one. Content type parameters are ignored, so "application/json"
+// and "application/json; charset=utf-8" share a serializer. HTTP and RPC
+// writers dispatch on registered content types, and users can plug in
+// proprietary formats here. Formats that need a third-party package register
+// from their own subpackage, eg: importing
+// github.com/qri-io/errors/errorscbor registers "application/cbor"
+func RegisterSerializer(contentType string, s Serializer) error {
+	mediaType, err := parseMediaType(contentType)
+	if err != nil {
+		return err
+	}
+	serializers.lk.Lock()
+	defer serializers.lk.Unlock()
+	serializers.byType[mediaType] = s
+	return nil
+}
+
+// SerializerFor returns the serializer for a content type, and whether one is
+// registered
+func SerializerFor(contentType string) (Serializer, bool) {
+	mediaType, err := parseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+	serializers.lk.RLock()
+	defer serializers.lk.RUnlock()
+	s, ok := serializers.byType[mediaType]
+	return s, ok
+}
+
+// SerializerContentTypes lists the media types with a registered serializer
+// in sorted order
+func SerializerContentTypes() []string {
+	serializers.lk.RLock()
+	defer serializers.lk.RUnlock()
+	types := make([]string, 0, len(serializers.byType))
+	for t := range serializers.byType {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// marshalWith marshals e with the serializer registered for contentType. pkg
+// is the package that registers one, named in the error returned when none is
+// registered
+func marshalWith(contentType, pkg string, e *Error) ([]byte, error) {
+	s, ok := SerializerFor(contentType)
+	if !ok {
+		return nil, noSerializerError(contentType, pkg)
+	}
+	return s.Marshal(e)
+}
+
+// unmarshalWith sets e to the error the serializer registered for contentType
+// reads from data
+func unmarshalWith(contentType, pkg string, e *Error, data []byte) error {
+	s, ok := SerializerFor(contentType)
+	if !ok {
+		return noSerializerError(contentType, pkg)
+	}
+	got, err := s.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	*e = *got
+	return nil
+}
+
+// noSerializerError is returned when no serializer is registered for
+// contentType, naming the package to import to register one
+func noSerializerError(contentType, pkg string) *Error {
+	return NewFriendlyFix(CodeNotImplemented, "no serializer registered", "", "import "+pkg+" to register one", contentType)
+}
+
+// parseMediaType strips parameters from a content type and lowercases it
+func parseMediaType(contentType string) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", Wrap(CodeInvalidArgs, err, "invalid content type", contentType)
+	}
+	return strings.ToLower(mediaType), nil
+}
+
+func init() {
+	builtin := map[string]Serializer{
+		"application/json": SerializerFuncs{
+			MarshalFunc: func(e *Error) ([]byte, error) { return json.Marshal(e) },
+			UnmarshalFunc: func(data []byte) (*Error, error) {
+				e := &Error{}
+				return e, json.Unmarshal(data, e)
+			},
+		},
+		ProblemContentType: SerializerFuncs{
+			MarshalFunc: func(e *Error) ([]byte, error) { return json.Marshal(ProblemDetails(e)) },
+			UnmarshalFunc: func(data []byte) (*Error, error) {
+				p := &Problem{}
+				if err := json.Unmarshal(data, p); err != nil {
+					return nil, err
+				}
+				return FromProblem(p), nil
+			},
+		},
+		"application/xml": SerializerFuncs{
+			MarshalFunc: func(e *Error) ([]byte, error) { return xml.Marshal(e) },
+			UnmarshalFunc: func(data []byte) (*Error, error) {
+				e := &Error{}
+				return e, xml.Unmarshal(data, e)
+			},
+		},
+		"text/plain": SerializerFuncs{
+			MarshalFunc: func(e *Error) ([]byte, error) { return e.MarshalText() },
+			UnmarshalFunc: func(data []byte) (*Error, error) {
+				e := &Error{}
+				return e, e.UnmarshalText(data)
+			},
+		},
+		"text/html": SerializerFuncs{
+			MarshalFunc: renderHTML,
+			UnmarshalFunc: func(data []byte) (*Error, error) {
+				return nil, New(CodeNotImplemented, "html errors can't be decoded")
+			},
+		},
+	}
+	for contentType, s := range builtin {
+		serializers.byType[contentType] = s
+	}
+}
@@ -0,0 +1,76 @@
+package errors
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSerializersRoundTrip(t *testing.T) {
+	e := NewFriendly(CodeNotFound, "no dataset", "couldn't find that dataset").WithField("ref")
+	builtin := []string{
+		"application/json",
+		"application/xml",
+		"text/plain",
+	}
+	for _, contentType := range builtin {
+		s, ok := SerializerFor(contentType)
+		if !ok {
+			t.Fatalf("missing serializer for %s", contentType)
+		}
+		data, err := s.Marshal(e)
+		if err != nil {
+			t.Fatalf("%s marshal error: %s", contentType, err)
+		}
+		got, err := s.Unmarshal(data)
+		if err != nil {
+			t.Fatalf("%s unmarshal error: %s", contentType, err)
+		}
+		if got.Code() != e.Code() || got.Friendly() != e.Friendly() || got.Field() != e.Field() {
+			t.Errorf("%s round trip mismatch. expected: %s, got: %s", contentType, e.Friendly(), got.Friendly())
+		}
+	}
+}
+
+func TestRegisterSerializer(t *testing.T) {
+	s := SerializerFuncs{
+		MarshalFunc: func(e *Error) ([]byte, error) { return []byte(e.Message()), nil },
+		UnmarshalFunc: func(data []byte) (*Error, error) {
+			return New(CodeGeneric, string(data)), nil
+		},
+	}
+	if err := RegisterSerializer("Application/X-Test; charset=utf-8", s); err != nil {
+		t.Fatal(err)
+	}
+	got, ok := SerializerFor("application/x-test")
+	if !ok {
+		t.Fatalf("expected registered serializer to be found ignoring case & parameters")
+	}
+	data, err := got.Marshal(New(CodeNotFound, "no dataset"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "no dataset" {
+		t.Errorf("marshal mismatch. expected: %s, got: %s", "no dataset", data)
+	}
+
+	if err := RegisterSerializer("not a content type;", s); err == nil {
+		t.Errorf("expected invalid content type to error")
+	}
+	if _, ok := SerializerFor("application/x-unregistered"); ok {
+		t.Errorf("expected unregistered content type to be missing")
+	}
+}
+
+func TestMarshalUnregisteredCodec(t *testing.T) {
+	e := New(CodeNotFound, "no dataset")
+	_, err := e.MarshalCBOR()
+	if !IsCode(err, CodeNotImplemented) {
+		t.Errorf("expected marshaling without a cbor serializer to be not implemented. got: %v", err)
+	}
+	if fix := err.(*Error).Fix(); !strings.Contains(fix, "github.com/qri-io/errors/errorscbor") {
+		t.Errorf("expected the fix to name the package to import. got: %s", fix)
+	}
+	if err := e.UnmarshalMsgpack([]byte{0x80}); !IsCode(err, CodeNotImplemented) {
+		t.Errorf("expected unmarshaling without a msgpack serializer to be not implemented. got: %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package errors
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogHandler is a slog.Handler middleware that expands attributes holding an
+// *Error into a group of structured attributes: the message, code, slug,
+// http status and fingerprint. Existing log calls like
+// logger.Error("failed", "err", err) gain classification without changes.
+// Attributes holding other errors are passed through untouched
+type SlogHandler struct {
+	next slog.Handler
+}
+
+// NewSlogHandler wraps a handler with error attribute expansion
+func NewSlogHandler(next slog.Handler) *SlogHandler {
+	return &SlogHandler{next: next}
+}
+
+// Enabled implements the slog.Handler interface
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements the slog.Handler interface
+func (h *SlogHandler) Handle(ctx context.Context, rec slog.Record) error {
+	expanded := slog.NewRecord(rec.Time, rec.Level, rec.Message, rec.PC)
+	rec.Attrs(func(a slog.Attr) bool {
+		expanded.AddAttrs(expandErrorAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, expanded)
+}
+
+// WithAttrs implements the slog.Handler interface
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	expanded := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		expanded[i] = expandErrorAttr(a)
+	}
+	return &SlogHandler{next: h.next.WithAttrs(expanded)}
+}
+
+// WithGroup implements the slog.Handler interface
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	return &SlogHandler{next: h.next.WithGroup(name)}
+}
+
+// expandErrorAttr replaces an attribute holding an *Error with a group of
+// structured attributes, descending into groups
+func expandErrorAttr(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		group := a.Value.Group()
+		expanded := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			expanded[i] = expandErrorAttr(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(expanded...)}
+	case slog.KindAny:
+		err, ok := a.Value.Any().(error)
+		if !ok {
+			return a
+		}
+		e, ok := AsError(err)
+		if !ok {
+			return a
+		}
+		reg := e.Registry()
+		code := reg.Canonical(ResolveCode(err))
+		return slog.Group(a.Key,
+			slog.String("message", err.Error()),
+			slog.Int("code", int(code)),
+			slog.String("slug", reg.CodeSlug(code)),
+			slog.Int("http_status", reg.CodeHTTPStatus(code)),
+			slog.String("fingerprint", Fingerprint(err)),
+		)
+	}
+	return a
+}
@@ -0,0 +1,41 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+)
+
+func TestSlogHandler(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(NewSlogHandler(slog.NewJSONHandler(buf, nil)))
+	err := New(CodeNotFound, "no dataset")
+	wrapped := fmt.Errorf("loading: %w", err)
+	logger.With("prev", err).Error("failed", "err", wrapped, "plain", fmt.Errorf("oh no"))
+
+	rec := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatal(err)
+	}
+	attr, ok := rec["err"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected err to be expanded into a group. got: %s", buf.String())
+	}
+	if attr["slug"] != "not_found" || attr["http_status"] != float64(404) || attr["code"] != float64(CodeNotFound) {
+		t.Errorf("attribute mismatch. got: %v", attr)
+	}
+	if attr["fingerprint"] != Fingerprint(wrapped) {
+		t.Errorf("fingerprint mismatch. expected: %s, got: %v", Fingerprint(wrapped), attr["fingerprint"])
+	}
+	if attr["message"] != "loading: missing: no dataset" {
+		t.Errorf("message mismatch. expected: %s, got: %v", "loading: missing: no dataset", attr["message"])
+	}
+	if _, ok := rec["prev"].(map[string]interface{}); !ok {
+		t.Errorf("expected attributes added with With to be expanded. got: %s", buf.String())
+	}
+	if rec["plain"] != "oh no" {
+		t.Errorf("expected plain errors to be left alone. got: %v", rec["plain"])
+	}
+}
@@ -0,0 +1,72 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// SSEEvent is the event name of Server-Sent Events error frames
+const SSEEvent = "error"
+
+// WriteSSE writes err to a Server-Sent Events stream as an "error" event, with
+// the JSON encoded error as data. The code ResolveCode picks for err is
+// written, and DefaultOutputMode applies. Writers that implement http.Flusher
+// are flushed, so long-lived streams deliver the error immediately. A nil
+// error writes nothing
+func WriteSSE(w io.Writer, err error) error {
+	if err == nil {
+		return nil
+	}
+	e := resolvedError(err).forOutput(OutputDefault)
+	data, merr := json.Marshal(e)
+	if merr != nil {
+		return merr
+	}
+	if _, werr := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", SSEEvent, data); werr != nil {
+		return werr
+	}
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// ParseSSE reads an error from a single Server-Sent Events frame, the lines
+// of an event without the blank line that ends it. Frames that aren't an
+// "error" event return a nil error with ok set to false, so clients can hand
+// every frame of a stream to ParseSSE
+func ParseSSE(frame []byte) (e *Error, ok bool, err error) {
+	event := "message"
+	data := &bytes.Buffer{}
+	for _, line := range strings.Split(strings.ReplaceAll(string(frame), "\r\n", "\n"), "\n") {
+		if line == "" || strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value := line, ""
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+		switch field {
+		case "event":
+			event = value
+		case "data":
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+		}
+	}
+	if event != SSEEvent {
+		return nil, false, nil
+	}
+
+	e = &Error{}
+	if err := json.Unmarshal(data.Bytes(), e); err != nil {
+		return nil, true, Wrap(CodeInvalidSyntax, err, "parsing error event")
+	}
+	return e, true, nil
+}
@@ -0,0 +1,56 @@
+package errors
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWriteSSE(t *testing.T) {
+	w := httptest.NewRecorder()
+	e := NewFriendly(CodeUnavailable, "upstream closed", "the stream was interrupted")
+	if err := WriteSSE(w, e); err != nil {
+		t.Fatal(err)
+	}
+	if !w.Flushed {
+		t.Errorf("expected writer to be flushed")
+	}
+	frame := w.Body.String()
+	if !strings.HasPrefix(frame, "event: error\ndata: {") || !strings.HasSuffix(frame, "}\n\n") {
+		t.Errorf("frame mismatch. got: %q", frame)
+	}
+
+	got, ok, err := ParseSSE([]byte(strings.TrimSuffix(frame, "\n\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatalf("expected frame to be an error event")
+	}
+	if got.Code() != CodeUnavailable || got.Message() != "upstream closed" {
+		t.Errorf("parsed error mismatch. expected: %s, got: %s", e, got)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := WriteSSE(buf, nil); err != nil || buf.Len() != 0 {
+		t.Errorf("expected nil error to write nothing. got: %q", buf.String())
+	}
+}
+
+func TestParseSSE(t *testing.T) {
+	if _, ok, err := ParseSSE([]byte("data: {\"progress\":0.5}")); ok || err != nil {
+		t.Errorf("expected message events to be skipped. got: %t %v", ok, err)
+	}
+	frame := ": keepalive\r\nevent: error\r\ndata: {\"code\":6,\r\ndata: \"message\":\"no dataset\"}"
+	got, ok, err := ParseSSE([]byte(frame))
+	if err != nil || !ok {
+		t.Fatalf("expected multi-line data to parse. got: %t %v", ok, err)
+	}
+	if got.Code() != CodeNotFound {
+		t.Errorf("code mismatch. expected: %d, got: %d", CodeNotFound, got.Code())
+	}
+	if _, ok, err := ParseSSE([]byte("event: error\ndata: nope")); !ok || err == nil {
+		t.Errorf("expected invalid error data to error")
+	}
+}
@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// maxStackDepth caps the number of frames captured for an error
+const maxStackDepth = 32
+
+// Frame is a single function call in a stack trace
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// String formats a frame as the function name followed by an indented file
+// and line number, matching the layout of a go panic
+func (f Frame) String() string {
+	return fmt.Sprintf("%s\n\t%s:%d", f.Function, f.File, f.Line)
+}
+
+// stack is a list of program counters captured with runtime.Callers
+type stack []uintptr
+
+// callers captures the current call stack, skipping skip frames. A skip of 0
+// refers to runtime.Callers itself
+func callers(skip int) stack {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(skip, pcs)
+	return stack(pcs[:n])
+}
+
+// frames resolves program counters into frames
+func (s stack) frames() []Frame {
+	if len(s) == 0 {
+		return nil
+	}
+	frames := make([]Frame, 0, len(s))
+	iter := runtime.CallersFrames(s)
+	for {
+		f, more := iter.Next()
+		frames = append(frames, Frame{Function: f.Function, File: f.File, Line: f.Line})
+		if !more {
+			break
+		}
+	}
+	return frames
+}
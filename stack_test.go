@@ -0,0 +1,32 @@
+package errors
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStackTrace(t *testing.T) {
+	constructors := map[string]func() *Error{
+		"New":             func() *Error { return New(CodeGeneric, "a") },
+		"NewFriendly":     func() *Error { return NewFriendly(CodeGeneric, "a", "b") },
+		"NewFriendlyFix":  func() *Error { return NewFriendlyFix(CodeGeneric, "a", "b", "c") },
+		"Wrap":            func() *Error { return Wrap(CodeGeneric, New(CodeGeneric, "a"), "b") },
+		"WrapFriendly":    func() *Error { return WrapFriendly(CodeGeneric, New(CodeGeneric, "a"), "b", "c") },
+		"WrapFriendlyFix": func() *Error { return WrapFriendlyFix(CodeGeneric, New(CodeGeneric, "a"), "b", "c", "d") },
+		"WrapAll":         func() *Error { return WrapAll(CodeGeneric, []error{New(CodeGeneric, "a")}, "b") },
+	}
+
+	for name, fn := range constructors {
+		frames := fn().StackTrace()
+		if len(frames) == 0 {
+			t.Errorf("%s: expected a stack trace", name)
+			continue
+		}
+		if !strings.Contains(frames[0].Function, "TestStackTrace") {
+			t.Errorf("%s: expected first frame to be the caller. got: %s", name, frames[0].Function)
+		}
+		if !strings.HasSuffix(frames[0].File, "stack_test.go") {
+			t.Errorf("%s: file mismatch. expected: stack_test.go, got: %s", name, frames[0].File)
+		}
+	}
+}
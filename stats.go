@@ -0,0 +1,172 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxStatsFingerprints caps the fingerprints a Stats collector tracks. When
+// the cap is reached the least frequent fingerprint is dropped
+const maxStatsFingerprints = 4096
+
+// Stats collects in-process error statistics: counts by code, the most
+// frequent fingerprints, and the last occurrence. Stats is a Reporter, so
+// combine it with other reporters using MultiReporter. Stats is also an
+// expvar.Var and an http.Handler, serving snapshots as JSON, so operators can
+// ask a running process what has been failing. Publish it with
+// expvar.Publish(name, stats) to serve it at /debug/vars. The errors package
+// doesn't import expvar itself, which would register /debug/vars on
+// http.DefaultServeMux for every program importing errors
+type Stats struct {
+	top int
+	now func() time.Time
+
+	lk           sync.Mutex
+	total        int
+	codes        map[string]int
+	fingerprints map[string]*FingerprintStats
+	last         *FingerprintStats
+}
+
+// StatsSnapshot is a point-in-time copy of collected statistics
+type StatsSnapshot struct {
+	Total int `json:"total"`
+	// Codes counts errors by code slug
+	Codes map[string]int `json:"codes"`
+	// Top lists the most frequent fingerprints, most frequent first
+	Top []FingerprintStats `json:"top"`
+	// Last is the most recent error, nil if none have been collected
+	Last *FingerprintStats `json:"last,omitempty"`
+}
+
+// FingerprintStats describes errors sharing a Fingerprint
+type FingerprintStats struct {
+	Fingerprint string    `json:"fingerprint"`
+	Code        Code      `json:"code"`
+	Slug        string    `json:"slug"`
+	Message     string    `json:"message"`
+	Count       int       `json:"count"`
+	FirstSeen   time.Time `json:"first_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+}
+
+// NewStats creates a Stats collector whose snapshots list the top most
+// frequent fingerprints. top defaults to 10
+func NewStats(top int) *Stats {
+	if top < 1 {
+		top = 10
+	}
+	return &Stats{
+		top:          top,
+		now:          time.Now,
+		codes:        map[string]int{},
+		fingerprints: map[string]*FingerprintStats{},
+	}
+}
+
+// Report implements the Reporter interface
+func (s *Stats) Report(ctx context.Context, e *Error) {
+	reg := e.Registry()
+	code := reg.Canonical(ResolveCode(e))
+	slug := reg.CodeSlug(code)
+	fp := Fingerprint(e)
+	now := s.now()
+
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	s.total++
+	s.codes[slug]++
+	fs, ok := s.fingerprints[fp]
+	if !ok {
+		if len(s.fingerprints) >= maxStatsFingerprints {
+			s.dropLeastFrequent()
+		}
+		fs = &FingerprintStats{
+			Fingerprint: fp,
+			Code:        code,
+			Slug:        slug,
+			FirstSeen:   now,
+		}
+		s.fingerprints[fp] = fs
+	}
+	fs.Count++
+	fs.LastSeen = now
+	fs.Message = e.Error()
+	s.last = fs
+}
+
+// dropLeastFrequent removes the least frequent, least recent fingerprint.
+// s.lk must be held
+func (s *Stats) dropLeastFrequent() {
+	var drop *FingerprintStats
+	for _, fs := range s.fingerprints {
+		if drop == nil || fs.Count < drop.Count || (fs.Count == drop.Count && fs.LastSeen.Before(drop.LastSeen)) {
+			drop = fs
+		}
+	}
+	if drop != nil {
+		delete(s.fingerprints, drop.Fingerprint)
+	}
+}
+
+// Snapshot returns a copy of the collected statistics
+func (s *Stats) Snapshot() StatsSnapshot {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+
+	snap := StatsSnapshot{
+		Total: s.total,
+		Codes: make(map[string]int, len(s.codes)),
+		Top:   make([]FingerprintStats, 0, len(s.fingerprints)),
+	}
+	for slug, n := range s.codes {
+		snap.Codes[slug] = n
+	}
+	for _, fs := range s.fingerprints {
+		snap.Top = append(snap.Top, *fs)
+	}
+	sort.Slice(snap.Top, func(i, j int) bool {
+		a, b := snap.Top[i], snap.Top[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.LastSeen.After(b.LastSeen)
+	})
+	if len(snap.Top) > s.top {
+		snap.Top = snap.Top[:s.top]
+	}
+	if s.last != nil {
+		last := *s.last
+		snap.Last = &last
+	}
+	return snap
+}
+
+// Reset clears collected statistics
+func (s *Stats) Reset() {
+	s.lk.Lock()
+	s.total = 0
+	s.codes = map[string]int{}
+	s.fingerprints = map[string]*FingerprintStats{}
+	s.last = nil
+	s.lk.Unlock()
+}
+
+// String implements the expvar.Var interface, returning a JSON snapshot
+func (s *Stats) String() string {
+	data, err := json.Marshal(s.Snapshot())
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
+
+// ServeHTTP implements the http.Handler interface, writing a JSON snapshot
+func (s *Stats) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(s.String()))
+}
@@ -0,0 +1,72 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Stats can be published with expvar.Publish
+var _ expvar.Var = (*Stats)(nil)
+
+func TestStats(t *testing.T) {
+	s := NewStats(1)
+	start := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	now := start
+	s.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	notFound := New(CodeNotFound, "no dataset")
+	for i := 0; i < 3; i++ {
+		s.Report(ctx, notFound)
+		now = now.Add(time.Second)
+	}
+	s.Report(ctx, New(CodeTimeout, "slow peer"))
+
+	snap := s.Snapshot()
+	if snap.Total != 4 {
+		t.Errorf("total mismatch. expected: %d, got: %d", 4, snap.Total)
+	}
+	if snap.Codes["not_found"] != 3 || snap.Codes["timeout"] != 1 {
+		t.Errorf("codes mismatch. got: %v", snap.Codes)
+	}
+	if len(snap.Top) != 1 {
+		t.Fatalf("expected top to be capped. got: %d", len(snap.Top))
+	}
+	top := snap.Top[0]
+	if top.Fingerprint != Fingerprint(notFound) || top.Count != 3 {
+		t.Errorf("top mismatch. got: %+v", top)
+	}
+	if top.FirstSeen != start || top.LastSeen != start.Add(2*time.Second) {
+		t.Errorf("seen times mismatch. got: %v - %v", top.FirstSeen, top.LastSeen)
+	}
+	if snap.Last == nil || snap.Last.Slug != "timeout" {
+		t.Errorf("last mismatch. got: %+v", snap.Last)
+	}
+
+	s.Reset()
+	if snap := s.Snapshot(); snap.Total != 0 || len(snap.Top) != 0 || snap.Last != nil {
+		t.Errorf("expected reset to clear stats. got: %+v", snap)
+	}
+}
+
+func TestStatsServeHTTP(t *testing.T) {
+	s := NewStats(0)
+	s.Report(context.Background(), New(CodeNotFound, "no dataset"))
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest("GET", "/debug/errors", nil))
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("content type mismatch. expected: %s, got: %s", "application/json", ct)
+	}
+	snap := StatsSnapshot{}
+	if err := json.Unmarshal(w.Body.Bytes(), &snap); err != nil {
+		t.Fatal(err)
+	}
+	if snap.Total != 1 || snap.Codes["not_found"] != 1 {
+		t.Errorf("snapshot mismatch. got: %+v", snap)
+	}
+}
@@ -0,0 +1,91 @@
+package errors
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// streamErrorMarker is the leading byte of stream error frames
+const streamErrorMarker byte = 0xEE
+
+// maxStreamErrorSize bounds the length of a stream error frame body
+const maxStreamErrorSize = 64 << 10
+
+// WriteStreamError writes an error to a stream as a frame holding a marker
+// byte, the uvarint length of the body, and the EncodeCompact body. Use it to
+// send an error to a peer before closing a libp2p stream, so the peer can
+// reconstruct the code, friendly message and details with ReadStreamError
+// instead of seeing a stream reset. The error's code is replaced by the code
+// ResolveCode picks, and the error is redacted when DefaultOutputMode is
+// OutputExternal
+func WriteStreamError(w io.Writer, err error) error {
+	body := EncodeCompact(resolvedError(err).forOutput(OutputDefault))
+	if len(body) > maxStreamErrorSize {
+		return New(CodeInvalidArgs, "stream error exceeds maximum frame size", len(body))
+	}
+	frame := make([]byte, 0, len(body)+binary.MaxVarintLen64+1)
+	frame = append(frame, streamErrorMarker)
+	frame = binary.AppendUvarint(frame, uint64(len(body)))
+	frame = append(frame, body...)
+	_, werr := w.Write(frame)
+	return werr
+}
+
+// ReadStreamError reads an error frame written by WriteStreamError. It
+// returns io.EOF if the stream ends before a frame starts, and an invalid
+// syntax error if the stream doesn't hold an error frame. No bytes past the
+// frame are read
+func ReadStreamError(r io.Reader) (*Error, error) {
+	sr, ok := r.(streamReader)
+	if !ok {
+		sr = &byteReader{Reader: r}
+	}
+	marker, err := sr.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if marker != streamErrorMarker {
+		return nil, New(CodeInvalidSyntax, "stream doesn't hold an error frame", marker)
+	}
+	size, err := binary.ReadUvarint(sr)
+	if err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	if size > maxStreamErrorSize {
+		return nil, New(CodeInvalidSyntax, "stream error exceeds maximum frame size", size)
+	}
+	body := make([]byte, size)
+	if _, err := io.ReadFull(sr, body); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	return DecodeCompact(body)
+}
+
+// unexpectedEOF reports a stream that ends within a frame as
+// io.ErrUnexpectedEOF
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// streamReader reads frames byte-by-byte for varints, and in bulk for bodies
+type streamReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// byteReader adds unbuffered single byte reads to a reader
+type byteReader struct {
+	io.Reader
+	buf [1]byte
+}
+
+// ReadByte implements the io.ByteReader interface
+func (b *byteReader) ReadByte() (byte, error) {
+	if _, err := io.ReadFull(b.Reader, b.buf[:]); err != nil {
+		return 0, err
+	}
+	return b.buf[0], nil
+}
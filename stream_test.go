@@ -0,0 +1,75 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestStreamErrorRoundTrip(t *testing.T) {
+	e := NewFriendly(CodeNotFound, "no dataset", "couldn't find that dataset", "b5/world_bank").
+		WithDetail("peer", "QmPeer")
+	buf := &bytes.Buffer{}
+	if err := WriteStreamError(buf, fmt.Errorf("resolving ref: %w", e)); err != nil {
+		t.Fatal(err)
+	}
+	buf.WriteString("trailing")
+
+	got, err := ReadStreamError(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Code() != CodeNotFound {
+		t.Errorf("code mismatch. expected: %d, got: %d", CodeNotFound, got.Code())
+	}
+	if got.FriendlyMessage() != "couldn't find that dataset" || got.Details()["peer"] != "QmPeer" {
+		t.Errorf("error mismatch. expected: %s, got: %s", e.Friendly(), got.Friendly())
+	}
+	if buf.String() != "trailing" {
+		t.Errorf("expected bytes past the frame to be left unread. got: %q", buf.String())
+	}
+}
+
+func TestWriteStreamErrorExternal(t *testing.T) {
+	prev := DefaultOutputMode
+	DefaultOutputMode = OutputExternal
+	defer func() { DefaultOutputMode = prev }()
+
+	e := NewFriendly(CodeNotFound, "no row in datasets", "couldn't find that dataset", "b5/world_bank").
+		WithDetail("peer", "QmPeer")
+	buf := &bytes.Buffer{}
+	if err := WriteStreamError(buf, e); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadStreamError(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Code() != CodeNotFound || got.FriendlyMessage() != "couldn't find that dataset" {
+		t.Errorf("error mismatch. expected: %s, got: %s", e.Friendly(), got.Friendly())
+	}
+	if got.Message() != "" || len(got.Data()) != 0 || len(got.Details()) != 0 {
+		t.Errorf("expected external output to be redacted. got: %q %v %v", got.Message(), got.Data(), got.Details())
+	}
+}
+
+func TestReadStreamErrorFailures(t *testing.T) {
+	if _, err := ReadStreamError(&bytes.Buffer{}); err != io.EOF {
+		t.Errorf("empty stream error mismatch. expected: %s, got: %v", io.EOF, err)
+	}
+	if _, err := ReadStreamError(bytes.NewReader([]byte("hello"))); !IsCode(err, CodeInvalidSyntax) {
+		t.Errorf("expected invalid syntax error for a non-error frame. got: %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	WriteStreamError(buf, New(CodeTimeout, "peer took too long"))
+	frame := buf.Bytes()
+	// read through a reader without ReadByte to exercise the unbuffered path
+	if _, err := ReadStreamError(io.MultiReader(bytes.NewReader(frame[:len(frame)-2]))); err != io.ErrUnexpectedEOF {
+		t.Errorf("truncated frame error mismatch. expected: %s, got: %v", io.ErrUnexpectedEOF, err)
+	}
+	if got, err := ReadStreamError(io.MultiReader(bytes.NewReader(frame))); err != nil || got.Code() != CodeTimeout {
+		t.Errorf("unbuffered read mismatch. got: %v %v", got, err)
+	}
+}
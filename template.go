@@ -0,0 +1,103 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"text/template"
+)
+
+// TemplateFuncs are functions for http body templates. "json" encodes a value
+// as JSON, for embedding values in JSON bodies. Add them to a template with
+// Funcs before parsing
+var TemplateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// BodyData is the data http body templates are executed with
+type BodyData struct {
+	// Status is the http status code
+	Status int
+	// Code is the code of the error
+	Code Code
+	// Slug is the slug of the code
+	Slug string
+	// Message is the machine message, which is empty in external output
+	Message string
+	// Friendly is the user-facing message
+	Friendly string
+	// Fix is the message on how to fix the error
+	Fix string
+	// Field is the name of the input field the error applies to
+	Field string
+	// Details are the structured details of the error
+	Details map[string]interface{}
+	// HelpURL is the docs url for the code
+	HelpURL string
+}
+
+// bodyTemplateKey identifies a body template
+type bodyTemplateKey struct {
+	code      Code
+	mediaType string
+}
+
+// bodyTemplates holds http body templates by code & media type
+var bodyTemplates = struct {
+	lk    sync.RWMutex
+	byKey map[bodyTemplateKey]*template.Template
+}{byKey: map[bodyTemplateKey]*template.Template{}}
+
+// SetBodyTemplate sets a template that renders http response bodies for a
+// code in a content type, replacing the serializer for that content type.
+// Templates are executed with a BodyData, so for example all CodeUnauthorized
+// JSON responses can include a login url. Setting a nil template removes it
+func SetBodyTemplate(c Code, contentType string, tmpl *template.Template) error {
+	mediaType, err := parseMediaType(contentType)
+	if err != nil {
+		return err
+	}
+	bodyTemplates.lk.Lock()
+	defer bodyTemplates.lk.Unlock()
+	key := bodyTemplateKey{code: c, mediaType: mediaType}
+	if tmpl == nil {
+		delete(bodyTemplates.byKey, key)
+		return nil
+	}
+	bodyTemplates.byKey[key] = tmpl
+	return nil
+}
+
+// bodyTemplate returns the template for a code & media type, if one is set
+func bodyTemplate(c Code, mediaType string) (*template.Template, bool) {
+	bodyTemplates.lk.RLock()
+	defer bodyTemplates.lk.RUnlock()
+	tmpl, ok := bodyTemplates.byKey[bodyTemplateKey{code: c, mediaType: mediaType}]
+	return tmpl, ok
+}
+
+// renderBody executes a body template for an error. The HTTP writers pass
+// errors prepared with outputError, so the friendly message & fix are
+// localized and the message is empty in external output
+func renderBody(tmpl *template.Template, e *Error) ([]byte, error) {
+	reg := e.Registry()
+	buf := &bytes.Buffer{}
+	err := tmpl.Execute(buf, BodyData{
+		Status:   reg.CodeHTTPStatus(e.code),
+		Code:     e.code,
+		Slug:     reg.CodeSlug(e.code),
+		Message:  e.Message(),
+		Friendly: e.friendlyMessage(),
+		Fix:      e.Fix(),
+		Field:    e.field,
+		Details:  e.details,
+		HelpURL:  reg.CodeDocsURL(e.code),
+	})
+	if err != nil {
+		return nil, Wrap(CodeInternal, err, "rendering body template")
+	}
+	return buf.Bytes(), nil
+}
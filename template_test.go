@@ -0,0 +1,77 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"text/template"
+
+	"golang.org/x/text/language"
+	textcatalog "golang.org/x/text/message/catalog"
+)
+
+func TestSetBodyTemplate(t *testing.T) {
+	tmpl := template.Must(template.New("unauthorized").Funcs(TemplateFuncs).Parse(
+		`{"code":{{json .Slug}},"friendly":{{json .Friendly}},"login_url":"https://qri.cloud/login"}`))
+	if err := SetBodyTemplate(CodeUnauthorized, "application/json", tmpl); err != nil {
+		t.Fatal(err)
+	}
+	defer SetBodyTemplate(CodeUnauthorized, "application/json", nil)
+
+	w := httptest.NewRecorder()
+	WriteHTTP(w, NewFriendly(CodeUnauthorized, "no token", `you need to "log in"`))
+	body := map[string]string{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("expected template output to be valid JSON. got: %s", w.Body.String())
+	}
+	if body["login_url"] != "https://qri.cloud/login" || body["friendly"] != `you need to "log in"` {
+		t.Errorf("body mismatch. got: %v", body)
+	}
+	if w.Code != 401 {
+		t.Errorf("status mismatch. expected: %d, got: %d", 401, w.Code)
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept", "application/xml")
+	w = httptest.NewRecorder()
+	WriteHTTPRequest(w, r, New(CodeUnauthorized, "no token"))
+	if w.Header().Get("Content-Type") != "application/xml; charset=utf-8" {
+		t.Errorf("expected templates to apply only to their content type")
+	}
+
+	if err := SetBodyTemplate(CodeUnauthorized, "application/json", nil); err != nil {
+		t.Fatal(err)
+	}
+	w = httptest.NewRecorder()
+	WriteHTTP(w, New(CodeUnauthorized, "no token"))
+	restored := map[string]interface{}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &restored); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := restored["login_url"]; ok {
+		t.Errorf("expected removing a template to restore the serializer")
+	}
+}
+
+func TestBodyTemplateLanguageExternal(t *testing.T) {
+	cat := textcatalog.NewBuilder()
+	cat.SetString(language.Spanish, MessageID(CodeUnauthorized, FriendlyMessageField), "necesitas iniciar sesión")
+	SetCatalog(cat)
+	defer SetCatalog(nil)
+
+	tmpl := template.Must(template.New("unauthorized").Parse(`{{.Friendly}}|{{.Message}}`))
+	if err := SetBodyTemplate(CodeUnauthorized, "text/plain", tmpl); err != nil {
+		t.Fatal(err)
+	}
+	defer SetBodyTemplate(CodeUnauthorized, "text/plain", nil)
+
+	hw := &HTTPWriter{Mode: OutputExternal}
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept", "text/plain")
+	r.Header.Set("Accept-Language", "es")
+	w := httptest.NewRecorder()
+	hw.WriteRequest(w, r, New(CodeUnauthorized, "token expired"))
+	if got := w.Body.String(); got != "necesitas iniciar sesión|" {
+		t.Errorf("body mismatch. expected: %s, got: %s", "necesitas iniciar sesión|", got)
+	}
+}
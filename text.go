@@ -0,0 +1,112 @@
+package errors
+
+import (
+	goerrors "errors"
+	"strconv"
+	"strings"
+)
+
+// MarshalText implements the encoding.TextMarshaler interface, producing a
+// compact single-line logfmt form of the error, eg:
+//
+//	code=not_found msg="dataset not found" friendly="couldn't find that dataset"
+//
+// values containing spaces, quotes or equals signs are quoted
+func (e Error) MarshalText() ([]byte, error) {
+	reg := e.Registry()
+	pairs := [][2]string{
+		{"code", reg.CodeSlug(reg.Canonical(e.code))},
+		{"msg", e.Message()},
+	}
+	if friendly := e.friendlyMessage(); friendly != "" {
+		pairs = append(pairs, [2]string{"friendly", friendly})
+	}
+	if fix := e.Fix(); fix != "" {
+		pairs = append(pairs, [2]string{"fix", fix})
+	}
+	if e.field != "" {
+		pairs = append(pairs, [2]string{"field", e.field})
+	}
+
+	strs := make([]string, len(pairs))
+	for i, p := range pairs {
+		strs[i] = p[0] + "=" + logfmtValue(p[1])
+	}
+	return []byte(strings.Join(strs, " ")), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, reading
+// the output of MarshalText. The code may be a slug or a number
+func (e *Error) UnmarshalText(text []byte) error {
+	pairs, err := parseLogfmt(string(text))
+	if err != nil {
+		return err
+	}
+
+	e.code = CodeUnknown
+	e.cause = goerrors.New("")
+	e.friendly, e.fix, e.field = "", "", ""
+	for _, p := range pairs {
+		switch p[0] {
+		case "code":
+			if n, err := strconv.Atoi(p[1]); err == nil {
+				e.code = Code(n)
+			} else {
+				e.code = e.Registry().CodeFromSlug(p[1])
+			}
+		case "msg":
+			e.cause = goerrors.New(p[1])
+		case "friendly":
+			e.friendly = p[1]
+		case "fix":
+			e.fix = p[1]
+		case "field":
+			e.field = p[1]
+		}
+	}
+	return nil
+}
+
+// logfmtValue quotes a value if it can't be written bare
+func logfmtValue(v string) string {
+	if v == "" || strings.ContainsAny(v, " \t\r\n\"=\\") {
+		return strconv.Quote(v)
+	}
+	return v
+}
+
+// parseLogfmt splits a line of logfmt into key-value pairs
+func parseLogfmt(line string) ([][2]string, error) {
+	var pairs [][2]string
+	for {
+		line = strings.TrimLeft(line, " ")
+		if line == "" {
+			return pairs, nil
+		}
+		eq := strings.IndexByte(line, '=')
+		if eq <= 0 {
+			return nil, New(CodeInvalidSyntax, "expected key=value pair", line)
+		}
+		key := line[:eq]
+		line = line[eq+1:]
+
+		var val string
+		if strings.HasPrefix(line, `"`) {
+			quoted, err := strconv.QuotedPrefix(line)
+			if err != nil {
+				return nil, Wrap(CodeInvalidSyntax, err, "invalid quoted value", key)
+			}
+			if val, err = strconv.Unquote(quoted); err != nil {
+				return nil, Wrap(CodeInvalidSyntax, err, "invalid quoted value", key)
+			}
+			line = line[len(quoted):]
+		} else {
+			end := strings.IndexByte(line, ' ')
+			if end == -1 {
+				end = len(line)
+			}
+			val, line = line[:end], line[end:]
+		}
+		pairs = append(pairs, [2]string{key, val})
+	}
+}
@@ -0,0 +1,40 @@
+package errors
+
+import (
+	"testing"
+)
+
+func TestErrorText(t *testing.T) {
+	e := NewFriendly(CodeNotFound, "dataset not found", `couldn't find "b5/world_bank"`).WithField("ref")
+
+	text, err := e.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `code=not_found msg="dataset not found" friendly="couldn't find \"b5/world_bank\"" field=ref`
+	if string(text) != expect {
+		t.Errorf("text mismatch.\nexpected: %s\ngot:      %s", expect, string(text))
+	}
+
+	got := &Error{}
+	if err := got.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+	if got.Code() != CodeNotFound || got.Error() != e.Error() || got.Friendly() != e.Friendly() || got.Field() != "ref" {
+		t.Errorf("round trip mismatch. expected: %s, got: %s", e.Friendly(), got.Friendly())
+	}
+
+	if err := got.UnmarshalText([]byte(`code=101 msg=boom`)); err != nil {
+		t.Fatal(err)
+	}
+	if got.Code() != Code(101) || got.Message() != "boom" {
+		t.Errorf("expected numeric code to parse. got: %d %s", got.Code(), got.Message())
+	}
+
+	bad := []string{`code`, `msg="unterminated`, `=value`}
+	for _, b := range bad {
+		if err := got.UnmarshalText([]byte(b)); err == nil {
+			t.Errorf("expected %q to error", b)
+		}
+	}
+}
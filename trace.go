@@ -0,0 +1,59 @@
+package errors
+
+import "context"
+
+// detail keys WrapCtx records context ids in
+const (
+	TraceIDDetail   = "trace_id"
+	SpanIDDetail    = "span_id"
+	RequestIDDetail = "request_id"
+)
+
+// spanKey is the context key for span ids
+type spanKey struct{}
+
+// spanIDs are the trace & span ids of a span
+type spanIDs struct {
+	traceID, spanID string
+}
+
+// ContextWithSpan returns a copy of ctx holding the trace & span ids of the
+// active span, for programs that propagate traces without a tracing library
+func ContextWithSpan(ctx context.Context, traceID, spanID string) context.Context {
+	return context.WithValue(ctx, spanKey{}, spanIDs{traceID: traceID, spanID: spanID})
+}
+
+// SpanFromContext returns the trace & span ids of the span active in ctx,
+// returning empty strings when there's no span. Tracing integrations replace
+// it to read their own span contexts. The default reads ids set with
+// ContextWithSpan
+var SpanFromContext = func(ctx context.Context) (traceID, spanID string) {
+	ids, _ := ctx.Value(spanKey{}).(spanIDs)
+	return ids.traceID, ids.spanID
+}
+
+// WrapCtx is like Wrap, also recording the trace & span ids SpanFromContext
+// finds in ctx, and the request id set with ContextWithRequestID, as the
+// TraceIDDetail, SpanIDDetail & RequestIDDetail details. An error report
+// alone is then enough to find the distributed trace it belongs to. If err is
+// nil, WrapCtx returns nil
+func WrapCtx(ctx context.Context, c Code, err error, message string, data ...interface{}) *Error {
+	if err == nil {
+		return nil
+	}
+	e := wrapError(c, err, message, data)
+	if ctx == nil {
+		return hooked(e)
+	}
+	traceID, spanID := SpanFromContext(ctx)
+	if traceID != "" {
+		e.WithDetail(TraceIDDetail, traceID)
+	}
+	if spanID != "" {
+		e.WithDetail(SpanIDDetail, spanID)
+	}
+	if id := RequestIDFromContext(ctx); id != "" {
+		e.WithDetail(RequestIDDetail, id)
+	}
+	return hooked(e)
+}
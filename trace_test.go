@@ -0,0 +1,36 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestWrapCtx(t *testing.T) {
+	ctx := ContextWithSpan(context.Background(), "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7")
+	ctx = ContextWithRequestID(ctx, "req-123")
+
+	e := WrapCtx(ctx, CodeUnavailable, fmt.Errorf("connection refused"), "fetching dataset")
+	expect := map[string]interface{}{
+		TraceIDDetail:   "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanIDDetail:    "00f067aa0ba902b7",
+		RequestIDDetail: "req-123",
+	}
+	for key, val := range expect {
+		if e.Details()[key] != val {
+			t.Errorf("detail %q mismatch. expected: %v, got: %v", key, val, e.Details()[key])
+		}
+	}
+	if e.Message() != "fetching dataset: connection refused" {
+		t.Errorf("message mismatch. expected: %s, got: %s", "fetching dataset: connection refused", e.Message())
+	}
+	if frames := e.StackTrace(); len(frames) == 0 || !strings.HasSuffix(frames[0].Function, "TestWrapCtx") {
+		t.Errorf("expected stack to start at the caller of WrapCtx. got: %v", frames)
+	}
+
+	plain := WrapCtx(context.Background(), CodeGeneric, fmt.Errorf("oh no"), "failed")
+	if len(plain.Details()) != 0 {
+		t.Errorf("expected no details without ids in the context. got: %v", plain.Details())
+	}
+}
@@ -0,0 +1,54 @@
+package errors
+
+import (
+	"encoding/xml"
+	goerrors "errors"
+)
+
+// xmlError is the XML form of an Error
+type xmlError struct {
+	XMLName  xml.Name `xml:"error"`
+	Code     Code     `xml:"code,attr"`
+	Slug     string   `xml:"slug,attr,omitempty"`
+	Status   int      `xml:"status,attr"`
+	Message  string   `xml:"message"`
+	Friendly string   `xml:"friendly,omitempty"`
+	Fix      string   `xml:"fix,omitempty"`
+	Field    string   `xml:"field,omitempty"`
+}
+
+// MarshalXML implements the xml.Marshaler interface, writing a stable
+// <error code="" slug="" status=""> element with message, friendly, fix and
+// field children. The element is always named "error"
+func (e Error) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	reg := e.Registry()
+	code := reg.Canonical(e.code)
+	return enc.Encode(xmlError{
+		Code:     code,
+		Slug:     reg.CodeSlug(code),
+		Status:   reg.CodeHTTPStatus(code),
+		Message:  e.Message(),
+		Friendly: e.friendlyMessage(),
+		Fix:      e.Fix(),
+		Field:    e.field,
+	})
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface, reading the output
+// of MarshalXML
+func (e *Error) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	xe := xmlError{}
+	if err := dec.DecodeElement(&xe, &start); err != nil {
+		return err
+	}
+	code := xe.Code
+	if code == CodeUnknown && xe.Slug != "" {
+		code = e.Registry().CodeFromSlug(xe.Slug)
+	}
+	e.code = code
+	e.cause = goerrors.New(xe.Message)
+	e.friendly = xe.Friendly
+	e.fix = xe.Fix
+	e.field = xe.Field
+	return nil
+}
@@ -0,0 +1,40 @@
+package errors
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestErrorXML(t *testing.T) {
+	e := NewFriendly(CodeNotFound, "dataset not found", "couldn't find that dataset")
+
+	data, err := xml.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `<error code="6" slug="not_found" status="404"><message>dataset not found</message><friendly>couldn&#39;t find that dataset</friendly></error>`
+	if string(data) != expect {
+		t.Errorf("xml mismatch.\nexpected: %s\ngot:      %s", expect, string(data))
+	}
+
+	got := &Error{}
+	if err := xml.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Code() != CodeNotFound || got.Error() != e.Error() || got.Friendly() != e.Friendly() {
+		t.Errorf("round trip mismatch. expected: %s, got: %s", e.Error(), got.Error())
+	}
+
+	wrapper := struct {
+		XMLName xml.Name `xml:"response"`
+		Err     *Error
+	}{Err: New(CodeUnauthorized, "no token")}
+	data, err = xml.Marshal(wrapper)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect = `<response><error code="4" slug="unauthorized" status="401"><message>no token</message><friendly>you need to be logged in to do that</friendly><fix>please log in and try again</fix></error></response>`
+	if string(data) != expect {
+		t.Errorf("nested xml mismatch.\nexpected: %s\ngot:      %s", expect, string(data))
+	}
+}
@@ -0,0 +1,13 @@
+package errors
+
+// MarshalYAML implements the yaml.Marshaler interface, writing the same
+// fields as MarshalJSON
+func (e Error) MarshalYAML() (interface{}, error) {
+	return e.wireError(), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface, reading the output
+// of MarshalYAML
+func (e *Error) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return e.DecodeWire(unmarshal)
+}